@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"seatalk-bot/internal/filestore"
+	"seatalk-bot/internal/logging"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/seatalkemu"
+)
+
+func main() {
+	addr := flag.String("addr", ":7070", "address to listen on")
+	appID := flag.String("app-id", "emulator-app", "app ID accepted by the auth endpoint")
+	appSecret := flag.String("app-secret", "emulator-secret", "app secret accepted by the auth endpoint")
+	signingSecret := flag.String("signing-secret", "", "secret used to sign fired callbacks")
+	botURL := flag.String("bot-url", "http://localhost:6969/event-callback", "event callback URL of the bot")
+	tokenTTL := flag.Duration("token-ttl", 2*time.Hour, "lifetime of issued access tokens")
+	allowAnonymous := flag.Bool("allow-anonymous", false, "accept send requests without an access token")
+	employeesFile := flag.String("employees", "", "JSON file of employee profiles served by the contacts API")
+	flag.Parse()
+
+	logging.Setup(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+
+	var employees []response.EmployeeProfile
+	if *employeesFile != "" {
+		if err := filestore.LoadJSON(*employeesFile, &employees); err != nil {
+			slog.Error("failed to load employees", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	emulator := seatalkemu.New(seatalkemu.Options{
+		AppID:          *appID,
+		AppSecret:      *appSecret,
+		SigningSecret:  *signingSecret,
+		BotURL:         *botURL,
+		TokenTTL:       *tokenTTL,
+		AllowAnonymous: *allowAnonymous,
+		Employees:      employees,
+	})
+
+	// Start the emulator
+	slog.Info("starting SeaTalk emulator", "addr", *addr, "bot_url", *botURL)
+	if err := http.ListenAndServe(*addr, logging.Middleware(emulator.Handler())); err != nil {
+		slog.Error("failed to start emulator", "error", err)
+		os.Exit(1)
+	}
+}
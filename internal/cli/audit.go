@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+)
+
+// runAudit prints the audit log entries matching the given filters
+func runAudit(env *environment, args []string) error {
+	flags := newFlagSet(env, "audit")
+	action := flags.String("action", "", "action or action prefix, e.g. schedule or job.run")
+	actor := flags.String("actor", "", "actor, e.g. scheduler or employee:<code>")
+	target := flags.String("target", "", "rotation, job, command or recipient kind")
+	group := flags.String("group", "", "group ID")
+	since := flags.String("since", "", "first day to show (2006-01-02)")
+	limit := flags.Int("limit", 50, "number of most recent entries to show, 0 for all")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *limit < 0 {
+		return errUsage
+	}
+
+	filter := audit.Filter{Action: *action, Actor: *actor, Target: *target, GroupID: *group, Limit: *limit}
+	if *since != "" {
+		day, err := time.Parse(time.DateOnly, *since)
+		if err != nil {
+			return errUsage
+		}
+		filter.Since = day
+	}
+
+	entries, err := auditLog(env.dataDir()).Query(filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTOR\tACTION\tTARGET\tOUTCOME\tREASON\tDETAILS")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Time.Format(time.RFC3339), e.Actor, e.Action, e.Target, e.Outcome, e.Reason, formatAuditDetails(e))
+	}
+	return w.Flush()
+}
+
+// auditLog opens the audit log kept in dataDir
+func auditLog(dataDir string) *audit.Log {
+	return audit.Open(filepath.Join(dataDir, constants.AuditFile), clock.Real{})
+}
+
+// formatAuditDetails joins the recipients, message ID, error and details of an entry
+func formatAuditDetails(e audit.Entry) string {
+	var parts []string
+	if e.GroupID != "" {
+		parts = append(parts, "group="+e.GroupID)
+	}
+	if e.EmployeeCode != "" {
+		parts = append(parts, "employee="+e.EmployeeCode)
+	}
+	if e.MessageID != "" {
+		parts = append(parts, "message="+e.MessageID)
+	}
+	keys := make([]string, 0, len(e.Details))
+	for key, value := range e.Details {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"="+e.Details[key])
+	}
+	if e.Error != "" {
+		parts = append(parts, "error="+e.Error)
+	}
+	return strings.Join(parts, " ")
+}
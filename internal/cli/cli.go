@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"seatalk-bot/internal/config"
+	"seatalk-bot/internal/logging"
+)
+
+// errUsage reports that a subcommand was invoked with invalid arguments
+var errUsage = errors.New("invalid usage")
+
+// command is a CLI subcommand
+type command struct {
+	usage string
+	run   func(env *environment, args []string) error
+}
+
+// environment carries the global options and output streams of a CLI run
+type environment struct {
+	envFile      string
+	dryRun       bool
+	dryRunOutput string
+	sandboxGroup string
+	stdout       io.Writer
+	stderr       io.Writer
+}
+
+var commands = map[string]command{
+	"serve":     {usage: "serve", run: runServe},
+	"send":      {usage: "send (--group <id> | --employee <code>) --text <text> [--thread <id>]", run: runSend},
+	"schedule":  {usage: "schedule show|advance|validate <file> [--pic <name>] [--runway <periods>] [--per-period <n>]", run: runSchedule},
+	"rotation":  {usage: "rotation init|show|plan|apply [--rotation <name>] [--replan]", run: runRotation},
+	"import":    {usage: "import <file.csv|file.xlsx> [--rotation <name>] [--format csv|xlsx] [--name-column <header>] [--date-column <header>] [--email-column <header>] [--check] [--skip-invalid]", run: runImport},
+	"jobs":      {usage: "jobs list|run <name>", run: runJobs},
+	"directory": {usage: "directory list|sync|lookup <email|employee-code>", run: runDirectory},
+	"groups":    {usage: "groups list", run: runGroups},
+	"audit":     {usage: "audit [--action <action>] [--actor <actor>] [--target <target>] [--group <id>] [--since <2006-01-02>] [--limit <n>]", run: runAudit},
+	"token":     {usage: "token", run: runToken},
+	"config":    {usage: "config check", run: runConfig},
+	"simulate":  {usage: "simulate [--start <2006-01-02>] [--weeks <n>] [--file <file>] [--messages]", run: runSimulate},
+}
+
+// Run executes the subcommand given in args and returns the process exit code.
+// Without a subcommand the bot server is started.
+func Run(args []string, stdout, stderr io.Writer) int {
+	env := &environment{stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("seatalk-bot", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&env.envFile, "env", config.EnvFile(), "path of the .env file to load")
+	flags.BoolVar(&env.dryRun, "dry-run", false, "render outgoing messages instead of sending them; jobs run on a copy of the state")
+	flags.StringVar(&env.dryRunOutput, "dry-run-output", "", "file to append rendered messages to (\"-\" for stdout)")
+	flags.StringVar(&env.sandboxGroup, "sandbox-group", "", "redirect every message to this group ID")
+	flags.Usage = func() { printUsage(stderr, flags) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	name, rest := "serve", flags.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	if name == "help" {
+		printUsage(stdout, flags)
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		printUsage(stderr, flags)
+		return 2
+	}
+
+	if err := cmd.run(env, rest); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "usage: seatalk-bot %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// loadConfig loads the configuration and sets up logging for commands talking to SeaTalk
+func (env *environment) loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfigFrom(env.envFile)
+	if err != nil {
+		return nil, err
+	}
+	if env.dryRun {
+		cfg.DryRun = true
+	}
+	if env.dryRunOutput != "" {
+		cfg.DryRunOutput = env.dryRunOutput
+	}
+	if env.sandboxGroup != "" {
+		cfg.SandboxGroupID = env.sandboxGroup
+	}
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
+	return cfg, nil
+}
+
+// dataDir returns the directory holding bot state. Commands working on local
+// files use it without requiring the SeaTalk credentials to be configured.
+func (env *environment) dataDir() string {
+	if cfg, err := env.loadConfig(); err == nil {
+		return cfg.DataDir
+	}
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		return dir
+	}
+	return config.DefaultDataDir
+}
+
+// templatesDir returns the directory of the template overrides, found like dataDir
+func (env *environment) templatesDir() string {
+	if cfg, err := env.loadConfig(); err == nil {
+		return cfg.TemplatesDir
+	}
+	return os.Getenv("TEMPLATES_DIR")
+}
+
+func printUsage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: seatalk-bot [flags] <command> [arguments]")
+	fmt.Fprintln(w, "\ncommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+
+	fmt.Fprintln(w, "\nflags:")
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+}
+
+// newFlagSet creates the flag set of a subcommand
+func newFlagSet(env *environment, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	return flags
+}
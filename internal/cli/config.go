@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+
+	"seatalk-bot/internal/config"
+	"seatalk-bot/pkg/templates"
+)
+
+// runConfig checks that the configuration loads and is valid
+func runConfig(env *environment, args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errUsage
+	}
+
+	cfg, err := config.LoadConfigFrom(env.envFile)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if _, err := templates.Load(cfg.TemplatesDir); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.stdout, "%s: configuration OK\n", env.envFile)
+	return nil
+}
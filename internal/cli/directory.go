@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"seatalk-bot/pkg/directory"
+	"seatalk-bot/pkg/eventcallback"
+)
+
+// runDirectory lists the employee directory, syncs it with SeaTalk, or looks
+// up one employee by email or employee code
+func runDirectory(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+	case args[0] == "sync" && len(args) == 1:
+	case args[0] == "lookup" && len(args) == 2:
+	default:
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+	dir := service.Directory()
+
+	switch args[0] {
+	case "sync":
+		if err := service.SyncDirectory(); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stdout, "directory synced, %d employees known\n", len(dir.Entries()))
+		return nil
+	case "lookup":
+		lookup := dir.Lookup
+		if strings.Contains(args[1], "@") {
+			lookup = dir.LookupEmail
+		}
+		entry, err := lookup(args[1])
+		if err != nil {
+			return err
+		}
+		return printEntries(env, []directory.Entry{entry})
+	}
+	return printEntries(env, dir.Entries())
+}
+
+// printEntries writes directory entries as a table
+func printEntries(env *environment, entries []directory.Entry) error {
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEMAIL\tEMPLOYEE CODE\tSEATALK ID\tDEPARTMENT\tUPDATED")
+	for _, e := range entries {
+		updated := "-"
+		if !e.UpdatedAt.IsZero() {
+			updated = e.UpdatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Name, e.Email, e.EmployeeCode, e.SeatalkID, e.Department, updated)
+	}
+	return w.Flush()
+}
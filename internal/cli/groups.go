@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"seatalk-bot/pkg/eventcallback"
+)
+
+// runGroups lists the groups the bot is in with their settings
+func runGroups(env *environment, args []string) error {
+	if len(args) != 1 || args[0] != "list" {
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP ID\tNAME\tACTIVE\tJOBS\tTIMEZONE\tQUIET HOURS\tROLES")
+	for _, g := range service.Groups().List() {
+		var bindings []string
+		for _, b := range service.Roles().Bindings(g.GroupID) {
+			bindings = append(bindings, b.EmployeeCode+"="+b.Role.String())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\t%s\n",
+			g.GroupID, g.Name, g.Active, strings.Join(g.Jobs, ","), g.Location(), g.QuietHours, strings.Join(bindings, ","))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/eventcallback"
+	"seatalk-bot/pkg/importer"
+	"seatalk-bot/pkg/schedule"
+)
+
+// runImport replaces a rotation schedule with the entries of a CSV or XLSX
+// file, reporting the rows that cannot be imported
+func runImport(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	filename := args[0]
+
+	flags := newFlagSet(env, "import")
+	name := flags.String("rotation", constants.StockInventoryRotation, "rotation to replace")
+	format := flags.String("format", "", "file format, csv or xlsx (detected when empty)")
+	var columns importer.Columns
+	flags.StringVar(&columns.Name, "name-column", "", "header of the name column")
+	flags.StringVar(&columns.Date, "date-column", "", "header of the date column")
+	flags.StringVar(&columns.Email, "email-column", "", "header of the email column")
+	check := flags.Bool("check", false, "only report the rows, without importing them")
+	skipInvalid := flags.Bool("skip-invalid", false, "import the valid rows even when some rows are invalid")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	result, err := importer.Parse(data, *format, columns)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range result.Issues {
+		fmt.Fprintf(env.stdout, "%s: %s\n", filename, issue)
+	}
+	fmt.Fprintf(env.stdout, "%s: %d of %d rows valid\n", filename, len(result.Schedules), result.Rows)
+	if *check {
+		return nil
+	}
+	if !result.Valid() && !*skipInvalid {
+		return errors.New(constants.ErrInvalidImport + ": fix the rows above or use --skip-invalid")
+	}
+	if len(result.Schedules) == 0 {
+		return errors.New(constants.ErrInvalidImport + ": no valid rows")
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := service.Schedules().Get(*name)
+	if err != nil {
+		return err
+	}
+	ctx := audit.WithReason(audit.WithActor(context.Background(), audit.ActorCLI), "import "+filename)
+	if err := store.Replace(ctx, result.Schedules); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "rotation %s replaced with %d entries\n", *name, len(result.Schedules))
+	fmt.Fprint(env.stdout, schedule.DisplayFullSchedule(result.Schedules))
+	return nil
+}
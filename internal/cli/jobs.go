@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/eventcallback"
+)
+
+// runJobs lists the scheduled jobs with their next run times, or runs one now
+func runJobs(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+	case args[0] == "run" && len(args) == 2:
+	default:
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	var opts []eventcallback.Option
+	if cfg.DryRun && args[0] == "run" {
+		// Run on a copy of the state so that nothing the job changes is kept
+		workDir, cleanup, err := copyState(cfg.DataDir, constants.StockInventoryScheduleFile)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		cfg.DataDir = workDir
+		opts = append(opts, eventcallback.WithScheduleFile(filepath.Join(workDir, filepath.Base(constants.StockInventoryScheduleFile))))
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg, opts...)
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "run" {
+		if err := service.Jobs().Trigger(args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stdout, "job %s completed\n", args[1])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSPEC\tNEXT RUN\tPAUSED")
+	for _, info := range service.Jobs().List() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", info.Name, info.Spec, info.Next.Format(time.RFC3339), info.Paused)
+	}
+	return w.Flush()
+}
+
+// copyState copies the data directory and the schedule file into a temporary
+// directory, returning it with a function removing it
+func copyState(dataDir, scheduleFile string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "seatalk-bot-dry-run")
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	err = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dataDir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0o755)
+		}
+		return copyFile(path, filepath.Join(dir, rel))
+	})
+	// A missing data directory or schedule file is as missing in the copy
+	if err == nil || errors.Is(err, fs.ErrNotExist) {
+		err = copyFile(scheduleFile, filepath.Join(dir, filepath.Base(scheduleFile)))
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		cleanup()
+		return "", nil, fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	return dir, cleanup, nil
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
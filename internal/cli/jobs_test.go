@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/seatalkemu"
+)
+
+// setupJobRun points the CLI at an emulator and at a working directory
+// holding a schedule with a PIC last week, this week and the next, returning
+// the schedule file and its content
+func setupJobRun(t *testing.T) (string, string) {
+	t.Helper()
+	emu := seatalkemu.NewServer(seatalkemu.Options{AppID: "app", AppSecret: "secret", SigningSecret: "signing"})
+	t.Cleanup(emu.Close)
+	cfg := emu.Config()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	for key, value := range map[string]string{
+		"SEATALK_APP_ID":              cfg.AppID,
+		"SEATALK_APP_SECRET":          cfg.AppSecret,
+		"SEATALK_API_URL":             cfg.APIURL,
+		"SEATALK_AUTH_URL":            cfg.AuthURL,
+		"SEATALK_SEND_GROUP_CHAT_URL": cfg.GroupChatUrl,
+		"SINGLE_CHAT_URL":             cfg.SingleChatUrl,
+		"SEATALK_SIGNING_SECRET":      cfg.SigningSecret,
+		"DATA_DIR":                    filepath.Join(dir, "data"),
+		"LOG_LEVEL":                   "error",
+	} {
+		t.Setenv(key, value)
+	}
+	if err := os.WriteFile(".env", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A group receiving the PIC announcements
+	err = filestore.SaveJSON(filepath.Join(dir, "data", constants.GroupsFile), []map[string]interface{}{
+		{"group_id": "g1", "active": true, "jobs": []string{constants.JobStockInventoryPIC}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start, _ := schedule.WeekRange(time.Now())
+	content := "Zoe," + start.AddDate(0, 0, -7).Format(constants.DateFormat) + ",zoe@example.com\n" +
+		"Alice," + start.Format(constants.DateFormat) + ",alice@example.com\n" +
+		"Bob," + start.AddDate(0, 0, 7).Format(constants.DateFormat) + ",bob@example.com\n"
+	if err := os.WriteFile(constants.StockInventoryScheduleFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, constants.StockInventoryScheduleFile), content
+}
+
+func TestDryRunJobKeepsState(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+		// kept reports whether the schedule and the audit log are left untouched
+		kept bool
+	}{
+		{"dry run", true, true},
+		{"real run", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, content := setupJobRun(t)
+			args := []string{"--env", ".env", "jobs", "run", constants.JobStockInventoryPIC}
+			if tt.dryRun {
+				args = append([]string{"--dry-run"}, args...)
+			}
+			if code := Run(args, io.Discard, io.Discard); code != 0 {
+				t.Fatalf("exit code %d", code)
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kept := string(data) == content; kept != tt.kept {
+				t.Errorf("schedule kept = %v, want %v:\n%s", kept, tt.kept, data)
+			}
+			_, err = os.Stat(filepath.Join("data", constants.AuditFile))
+			if kept := os.IsNotExist(err); kept != tt.kept {
+				t.Errorf("audit log left alone = %v, want %v", kept, tt.kept)
+			}
+			if _, err := os.Stat(filepath.Join("data", constants.ScheduleHistoryDir)); os.IsNotExist(err) != tt.kept {
+				t.Errorf("history left alone = %v, want %v", os.IsNotExist(err), tt.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/eventcallback"
+	"seatalk-bot/pkg/rotation"
+)
+
+// runRotation sets up the rotation engine of a rotation, shows its members
+// and turn counts, or previews and applies a plan of the coming periods
+func runRotation(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	action := args[0]
+
+	flags := newFlagSet(env, "rotation "+action)
+	name := flags.String("rotation", constants.StockInventoryRotation, "rotation to work on")
+	replan := flags.Bool("replan", false, "plan again every period after the current week (plan and apply)")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+	rot, err := service.Rotation(*name)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "init":
+		if rot.Configured() {
+			return errors.New(constants.ErrRotationConfigured + ": " + *name)
+		}
+		store, err := service.Schedules().Get(*name)
+		if err != nil {
+			return err
+		}
+		schedules, err := store.Read()
+		if err != nil {
+			return err
+		}
+		if err := rot.Init(schedules); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stdout, "rotation %s configured with %d members\n", *name, len(rot.Config().Members))
+		return nil
+	case "show":
+		return printRotation(env, rot)
+	case "plan":
+		_, plan, err := service.PlanRotation(*name, *replan)
+		if err != nil {
+			return err
+		}
+		return printPlan(env, plan)
+	case "apply":
+		ctx := audit.WithReason(audit.WithActor(context.Background(), audit.ActorCLI), "rotation apply "+*name)
+		plan, err := service.ExtendRotation(ctx, *name, *replan)
+		if err != nil {
+			return err
+		}
+		return printPlan(env, plan)
+	default:
+		return errUsage
+	}
+}
+
+// printRotation writes the settings, members and turn counts of a rotation
+func printRotation(env *environment, rot *rotation.Rotation) error {
+	cfg := rot.Config()
+	fmt.Fprintf(env.stdout, "period: %d days, planned %d periods ahead, %d per period\n",
+		cfg.Period(), cfg.Ahead(), cfg.Slots())
+	fmt.Fprintf(env.stdout, "max consecutive: %d, min gap: %d, excluded dates: %d\n\n",
+		cfg.Constraints.MaxConsecutive, cfg.Constraints.MinGap, len(cfg.Constraints.ExcludedDates))
+
+	stats := rot.Stats()
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEMAIL\tWEIGHT\tTURNS\tLAST\tAVAILABILITY")
+	for _, m := range cfg.Members {
+		s := stats[m.Key()]
+		last := "-"
+		if !s.Last.IsZero() {
+			last = s.Last.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%g\t%d\t%s\t%s\n", m.Name, m.Email, m.Share(), s.Turns, last, formatAvailability(m))
+	}
+	return w.Flush()
+}
+
+// formatAvailability describes the availability windows and leaves of a member
+func formatAvailability(m rotation.Member) string {
+	var parts []string
+	for _, w := range m.Available {
+		parts = append(parts, "available "+formatWindow(w))
+	}
+	for _, w := range m.Unavailable {
+		parts = append(parts, "away "+formatWindow(w))
+	}
+	if len(parts) == 0 {
+		return "always"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatWindow writes a window as from..to, leaving open ends empty
+func formatWindow(w rotation.Window) string {
+	var from, to string
+	if !w.From.IsZero() {
+		from = w.From.Format("2006-01-02")
+	}
+	if !w.To.IsZero() {
+		to = w.To.Format("2006-01-02")
+	}
+	return from + ".." + to
+}
+
+// printPlan writes the assignments and warnings of a plan
+func printPlan(env *environment, plan rotation.Plan) error {
+	if len(plan.Assignments) == 0 && len(plan.Warnings) == 0 {
+		fmt.Fprintln(env.stdout, "the schedule already covers the planned periods")
+		return nil
+	}
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tPIC\tEMAIL")
+	for _, a := range plan.Assignments {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Date.Format("2006-01-02"), a.PIC, a.Email)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(env.stdout, "warning: %s: %s\n", warning.Date.Format("2006-01-02"), warning.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/schedule"
+)
+
+// runSchedule inspects or rotates a schedule file
+func runSchedule(env *environment, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	action, filename := args[0], args[1]
+
+	flags := newFlagSet(env, "schedule "+action)
+	pic := flags.String("pic", "", "current PIC to rotate after (advance only)")
+	runway := flags.Int("runway", schedule.DefaultRunway, "periods after this week that should be scheduled (validate only)")
+	perPeriod := flags.Int("per-period", schedule.DefaultPerPeriod, "PICs sharing a turn (validate only)")
+	if err := flags.Parse(args[2:]); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+
+	switch action {
+	case "show":
+		schedules, err := schedule.ReadSchedules(filename)
+		if err != nil {
+			return err
+		}
+		start, end := schedule.GetCurrentWeekRange()
+		fmt.Fprint(env.stdout, "PICs for this week: \n")
+		fmt.Fprint(env.stdout, schedule.FormatPICsWithinRange(schedules, start, end))
+		fmt.Fprint(env.stdout, schedule.DisplayFullSchedule(schedules))
+		return nil
+	case "advance":
+		return advanceSchedule(env, filename, *pic)
+	case "validate":
+		return validateSchedule(env, filename, schedule.LintOptions{Now: time.Now(), PerPeriod: *perPeriod, Runway: *runway})
+	default:
+		return errUsage
+	}
+}
+
+// validateSchedule lists the issues of a schedule file, failing when any of
+// them is an error
+func validateSchedule(env *environment, filename string, opts schedule.LintOptions) error {
+	issues, err := schedule.LintFile(filename, opts)
+	if err != nil {
+		return err
+	}
+	errorCount := 0
+	for _, issue := range issues {
+		fmt.Fprintf(env.stdout, "%s: %s\n", filename, issue)
+		if issue.Severity == schedule.SeverityError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return errors.New(constants.ErrInvalidSchedule + ": " + strconv.Itoa(errorCount) + " error(s)")
+	}
+	fmt.Fprintf(env.stdout, "%s: OK, %d warnings\n", filename, len(issues))
+	return nil
+}
+
+// advanceSchedule rotates the schedule after the given PIC, or after every
+// PIC of the current week when none is given
+func advanceSchedule(env *environment, filename, pic string) error {
+	pics := []string{pic}
+	if pic == "" {
+		schedules, err := schedule.ReadSchedules(filename)
+		if err != nil {
+			return err
+		}
+		start, end := schedule.GetCurrentWeekRange()
+		pics = nil
+		for _, s := range schedule.SchedulesWithinRange(schedules, start, end) {
+			pics = append(pics, s.PIC)
+		}
+		if len(pics) == 0 {
+			fmt.Fprintln(env.stdout, "no PIC scheduled this week, nothing to advance")
+			return nil
+		}
+	}
+
+	// Record the changes in the audit log and the history of the rotation,
+	// named after the schedule file
+	name := filepath.Base(filename)
+	if name == constants.StockInventoryScheduleFile {
+		name = constants.StockInventoryRotation
+	}
+	dataDir := env.dataDir()
+	store := schedule.NewStore(name, filename, clock.Real{})
+	store.SetAudit(auditLog(dataDir))
+	history, err := schedule.LoadHistory(filepath.Join(dataDir, constants.ScheduleHistoryDir, name+".json"))
+	if err != nil {
+		return err
+	}
+	store.SetHistory(history)
+	ctx := audit.WithReason(audit.WithActor(context.Background(), audit.ActorCLI), "schedule advance "+name)
+	for _, p := range pics {
+		if err := store.Advance(ctx, p); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stdout, "advanced rotation after %s\n", p)
+	}
+
+	schedules, err := schedule.ReadSchedules(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(env.stdout, schedule.DisplayFullSchedule(schedules))
+	return nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/eventcallback"
+)
+
+// runSend sends a text message to a group or an employee
+func runSend(env *environment, args []string) error {
+	flags := newFlagSet(env, "send")
+	groupID := flags.String("group", "", "group ID to send the message to")
+	employeeCode := flags.String("employee", "", "employee code to send the message to")
+	text := flags.String("text", "", "message text")
+	threadID := flags.String("thread", "", "thread ID to reply in (groups only)")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+	if strings.TrimSpace(*text) == "" || (*groupID == "") == (*employeeCode == "") {
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+	service, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+
+	if *groupID != "" {
+		resp, err := service.SendMessageToGroup(request.SendMessageToBotGroupRequest{
+			GroupID: *groupID,
+			Message: request.MessageGroup{
+				Tag: "Text",
+				Text: request.TextGroup{
+					Format:  1,
+					Content: *text,
+				},
+				ThreadID: *threadID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stdout, "sent to group %s (code %d, message ID %s)\n", *groupID, resp.Code, resp.MessegeId)
+		return nil
+	}
+
+	resp, err := service.SendMessageToSubscriber(request.SendMessageToBotSubscriberRequest{
+		EmployeeCode: *employeeCode,
+		Message: request.MessageSingle{
+			Tag: "Text",
+			Text: request.TextSingle{
+				Format:  1,
+				Content: *text,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "sent to employee %s (code %d)\n", *employeeCode, resp.Code)
+	return nil
+}
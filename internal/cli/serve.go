@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/logging"
+	"seatalk-bot/internal/metrics"
+	"seatalk-bot/pkg/admin"
+	"seatalk-bot/pkg/calendar"
+	"seatalk-bot/pkg/eventcallback"
+	"seatalk-bot/pkg/health"
+)
+
+// runServe starts the HTTP server and the scheduled jobs
+func runServe(env *environment, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+
+	// Load the configuration
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Initialize the EventCallbackService, loading and validating the message templates
+	eventService, err := eventcallback.NewEventCallbackService(cfg)
+	if err != nil {
+		return err
+	}
+	if err := eventService.Migrate(); err != nil {
+		return err
+	}
+	eventService.Start()
+	defer eventService.Stop()
+
+	// Set up the readiness checks
+	tokenService := eventService.Tokens()
+	checker := health.NewChecker(5 * time.Second)
+	checker.Add("config", func(ctx context.Context) error {
+		return cfg.Validate()
+	})
+	checker.Add("token", func(ctx context.Context) error {
+		_, err := tokenService.RefreshToken()
+		return err
+	})
+	checker.Add("schedule", func(ctx context.Context) error {
+		for _, name := range eventService.Schedules().Names() {
+			store, err := eventService.Schedules().Get(name)
+			if err != nil {
+				return err
+			}
+			if _, err := store.Read(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	checker.Add("scheduler", eventService.Jobs().Running)
+
+	// Set up the HTTP handler for event callbacks
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", checker.HandleHealthz)
+	mux.HandleFunc("/readyz", checker.HandleReadyz)
+	mux.HandleFunc("/event-callback", eventService.HandleEventCallback)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle(calendar.RotationPath, calendar.NewHandler(eventService.Schedules(), cfg.CalendarToken, clock.Real{}))
+	if cfg.AdminToken != "" {
+		mux.Handle("/admin/", admin.NewHandler(cfg.AdminToken, eventService.Jobs(), eventService.Schedules(), eventService, eventService.Audit()))
+	} else {
+		slog.Warn("ADMIN_TOKEN is not set, admin API disabled")
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("Hello, World!"))
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Start the HTTP server
+	port := ":6969" // Change the port as needed
+	slog.Info("starting server", "port", port)
+	return http.ListenAndServe(port, logging.Middleware(mux))
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/config"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/logging"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/eventcallback"
+	"seatalk-bot/pkg/schedule"
+)
+
+// simulationGroupID is the group the simulated announcements are addressed to
+const simulationGroupID = "simulation"
+
+// capturingSender keeps the messages of a simulation instead of sending them
+type capturingSender struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (c *capturingSender) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, "[to "+req.EmployeeCode+"] "+req.Message.Text.Content)
+	return response.SendMessageToBotSubscriberResponse{}, nil
+}
+
+func (c *capturingSender) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, req.Message.Text.Content)
+	return response.SendMessageToBotGroupResponse{}, nil
+}
+
+// drain returns and forgets the captured messages
+func (c *capturingSender) drain() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	messages := c.messages
+	c.messages = nil
+	return messages
+}
+
+// runSimulate replays the weekly PIC announcement and rotation on a copy of
+// the schedule file, moving a fake clock from one scheduled run to the next
+func runSimulate(env *environment, args []string) error {
+	flags := newFlagSet(env, "simulate")
+	start := flags.String("start", "", "date to start from (2006-01-02), defaults to today")
+	weeks := flags.Int("weeks", 4, "number of weekly announcements to replay")
+	file := flags.String("file", constants.StockInventoryScheduleFile, "schedule file to simulate (left untouched)")
+	verbose := flags.Bool("messages", false, "print the full announcement of every week")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *weeks < 1 {
+		return errUsage
+	}
+
+	startTime := time.Now()
+	if *start != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *start, time.Local)
+		if err != nil {
+			return errUsage
+		}
+		startTime = parsed
+	}
+
+	// Work on a copy so the real rotation is never modified
+	workFile, cleanup, err := copyScheduleFile(*file)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if err := copyRotationFile(env.dataDir(), filepath.Dir(workFile)); err != nil {
+		return err
+	}
+
+	// Render with the template overrides of the deployment
+	templatesDir := env.templatesDir()
+
+	logging.Setup("warn", "text")
+	clk := clock.NewFake(startTime)
+	sender := &capturingSender{}
+	service, err := eventcallback.NewEventCallbackService(
+		&config.Config{RegressionGroupID: simulationGroupID, DataDir: filepath.Dir(workFile), TemplatesDir: templatesDir},
+		eventcallback.WithClock(clk),
+		eventcallback.WithSender(sender),
+		eventcallback.WithScheduleFile(workFile),
+	)
+	if err != nil {
+		return err
+	}
+	// The simulation group receives the announcements like a legacy deployment
+	if err := service.Migrate(); err != nil {
+		return err
+	}
+	store, err := service.Schedules().Get(constants.StockInventoryRotation)
+	if err != nil {
+		return err
+	}
+
+	for week := 1; week <= *weeks; week++ {
+		next, err := service.Jobs().NextAfter(constants.JobStockInventoryPIC, clk.Now())
+		if err != nil {
+			return err
+		}
+		clk.Set(next)
+		weekStart, weekEnd := store.CurrentWeek()
+
+		before, err := store.Read()
+		if err != nil {
+			return err
+		}
+		jobErr := service.Jobs().Trigger(constants.JobStockInventoryPIC)
+		after, err := store.Read()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(env.stdout, "Week %d: %s (rotation week %s .. %s)\n", week,
+			next.Format("Mon 2006-01-02 15:04 MST"), weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02"))
+		fmt.Fprintf(env.stdout, "  on duty:  %s\n", formatPICs(schedule.SchedulesWithinRange(before, weekStart, weekEnd)))
+		if jobErr != nil {
+			fmt.Fprintf(env.stdout, "  error:    %v\n", jobErr)
+		}
+		fmt.Fprintf(env.stdout, "  schedule: %s\n", formatTimeline(after))
+
+		for _, message := range sender.drain() {
+			if *verbose {
+				fmt.Fprintf(env.stdout, "  message:\n    %s\n", strings.ReplaceAll(strings.TrimRight(message, "\n"), "\n", "\n    "))
+			}
+		}
+	}
+	return nil
+}
+
+// copyScheduleFile copies a schedule file into a temporary directory
+func copyScheduleFile(filename string) (string, func(), error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", constants.ErrorFileOpen, err)
+	}
+
+	dir, err := os.MkdirTemp("", "seatalk-bot-simulation")
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	workFile := filepath.Join(dir, filepath.Base(filename))
+	if err := os.WriteFile(workFile, data, 0o644); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	return workFile, cleanup, nil
+}
+
+// copyRotationFile copies the rotation engine settings and turn counts of
+// the stock inventory rotation, when configured, into the simulation directory
+func copyRotationFile(dataDir, workDir string) error {
+	name := filepath.Join(constants.RotationsDir, constants.StockInventoryRotation+".json")
+	data, err := os.ReadFile(filepath.Join(dataDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", constants.ErrorFileOpen, err)
+	}
+	if err := os.MkdirAll(filepath.Join(workDir, constants.RotationsDir), 0o755); err != nil {
+		return fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, name), data, 0o644); err != nil {
+		return fmt.Errorf("%s: %w", constants.ErrorFileCreate, err)
+	}
+	return nil
+}
+
+// formatPICs lists the PICs of the given schedules
+func formatPICs(schedules []schedule.Schedule) string {
+	if len(schedules) == 0 {
+		return "(nobody)"
+	}
+	names := make([]string, 0, len(schedules))
+	for _, s := range schedules {
+		names = append(names, s.PIC)
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatTimeline lists the schedule on a single line
+func formatTimeline(schedules []schedule.Schedule) string {
+	entries := make([]string, 0, len(schedules))
+	for _, s := range schedules {
+		entries = append(entries, s.Date.Format("01-02")+" "+s.PIC)
+	}
+	return strings.Join(entries, " | ")
+}
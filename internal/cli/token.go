@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	tokernservice "seatalk-bot/pkg/tokenservice"
+)
+
+// runToken fetches an app access token and prints when it expires
+func runToken(env *environment, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+
+	cfg, err := env.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tokenService := tokernservice.NewTokenService(cfg)
+	if _, err := tokenService.GetToken(); err != nil {
+		return err
+	}
+
+	expiresAt := tokenService.ExpiresAt()
+	fmt.Fprintf(env.stdout, "token obtained, expires at %s (in %s)\n",
+		expiresAt.Format(time.RFC3339), time.Until(expiresAt).Round(time.Second))
+	return nil
+}
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"errors"
 	"log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
 
 	"github.com/joho/godotenv"
 )
@@ -17,23 +24,279 @@ type Config struct {
 	SingleChatUrl     string
 	GroupChatUrl      string
 	RegressionGroupID string
+	SigningSecret     string
+	LogLevel          string
+	LogFormat         string
+	AdminToken        string
+	DryRun            bool
+	DryRunOutput      string
+	SandboxGroupID    string
+	TemplatesDir      string
+	DataDir           string
+	ProfileUrl        string
+	EmployeeCodeUrl   string
+	DirectoryTTL      time.Duration
+	PICNoticeDays     int
+	// Owners are the employee codes holding the global owner role
+	Owners []string
+	// PublicURL is the address the bot is reachable at, used in shared links
+	PublicURL string
+	// CalendarToken protects the calendar feeds when set
+	CalendarToken string
+	// RunwayPeriods is how many periods after the current week should be
+	// scheduled before admins are warned that the rotation runs out
+	RunwayPeriods int
+	// RunwayAlert is where runway warnings go: RunwayAlertDM, RunwayAlertGroup
+	// or RunwayAlertBoth
+	RunwayAlert string
+	// AnswerBackend answers free-form questions: AnswerBackendOpenAI,
+	// AnswerBackendStub, or empty to disable answers
+	AnswerBackend string
+	// AnswerURL is the base URL of the OpenAI-compatible API, e.g. https://api.openai.com/v1
+	AnswerURL    string
+	AnswerAPIKey string
+	AnswerModel  string
+	// AnswerSystemPrompt replaces the built-in instructions given to the model
+	AnswerSystemPrompt string
+	// AnswerMaxTokens bounds the tokens generated for an answer
+	AnswerMaxTokens int
+	// AnswerMaxChars bounds the length of questions sent and answers posted
+	AnswerMaxChars int
+	// AnswerHistory is how many earlier messages of a conversation are sent
+	// along with a question
+	AnswerHistory int
+	AnswerTimeout time.Duration
+}
+
+// DefaultEnvFile is the .env file loaded when SEATALK_ENV_FILE is not set
+const DefaultEnvFile = "/home/arvalinno/seatalk_bot/.env"
+
+// DefaultDataDir is the directory holding bot state when DATA_DIR is not set
+const DefaultDataDir = "data"
+
+// DefaultDirectoryTTL is how long fetched employee profiles are trusted when
+// DIRECTORY_TTL is not set
+const DefaultDirectoryTTL = 24 * time.Hour
+
+// DefaultPICNoticeDays is how many days ahead subscribers are told they are
+// PIC when PIC_NOTICE_DAYS is not set
+const DefaultPICNoticeDays = 7
+
+// DefaultRunwayPeriods is how many periods after the current week should be
+// scheduled when RUNWAY_PERIODS is not set
+const DefaultRunwayPeriods = 2
+
+// Backends answering free-form questions
+const (
+	AnswerBackendOpenAI = "openai"
+	AnswerBackendStub   = "stub"
+)
+
+// Defaults of the answer limits, used when the ANSWER_* variables are not set
+const (
+	DefaultAnswerMaxTokens = 300
+	DefaultAnswerMaxChars  = 1500
+	DefaultAnswerHistory   = 10
+	DefaultAnswerTimeout   = 20 * time.Second
+)
+
+// Destinations of runway warnings
+const (
+	RunwayAlertDM    = "dm"
+	RunwayAlertGroup = "group"
+	RunwayAlertBoth  = "both"
+)
+
+// EnvFile returns the path of the .env file to load
+func EnvFile() string {
+	if path := os.Getenv("SEATALK_ENV_FILE"); path != "" {
+		return path
+	}
+	return DefaultEnvFile
 }
 
 // LoadConfig loads the configuration from the .env file
 func LoadConfig() *Config {
-	err := godotenv.Load("/home/arvalinno/seatalk_bot/.env")
+	cfg, err := LoadConfigFrom(EnvFile())
 	if err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
+	return cfg
+}
+
+// LoadConfigFrom loads the configuration from the given .env file
+func LoadConfigFrom(path string) (*Config, error) {
+	if err := godotenv.Load(path); err != nil {
+		return nil, err
+	}
+
+	apiURL := strings.TrimSuffix(os.Getenv("SEATALK_API_URL"), "/")
 
 	return &Config{
-		AppID:             os.Getenv("SEATALK_APP_ID"),
-		AppSecret:         os.Getenv("SEATALK_APP_SECRET"),
-		APIURL:            os.Getenv("SEATALK_API_URL"),
-		AuthURL:           os.Getenv("SEATALK_AUTH_URL"),
-		Port:              os.Getenv("PORT"),
-		SingleChatUrl:     os.Getenv("SINGLE_CHAT_URL"),
-		GroupChatUrl:      os.Getenv("SEATALK_SEND_GROUP_CHAT_URL"),
-		RegressionGroupID: os.Getenv("REGRESSION_GROUP_ID"),
+		AppID:              os.Getenv("SEATALK_APP_ID"),
+		AppSecret:          os.Getenv("SEATALK_APP_SECRET"),
+		APIURL:             apiURL,
+		AuthURL:            os.Getenv("SEATALK_AUTH_URL"),
+		Port:               os.Getenv("PORT"),
+		SingleChatUrl:      getenvFallback("SEATALK_SEND_SINGLE_CHAT_URL", "SINGLE_CHAT_URL"),
+		GroupChatUrl:       os.Getenv("SEATALK_SEND_GROUP_CHAT_URL"),
+		RegressionGroupID:  os.Getenv("REGRESSION_GROUP_ID"),
+		SigningSecret:      os.Getenv("SEATALK_SIGNING_SECRET"),
+		LogLevel:           os.Getenv("LOG_LEVEL"),
+		LogFormat:          os.Getenv("LOG_FORMAT"),
+		AdminToken:         os.Getenv("ADMIN_TOKEN"),
+		DryRun:             getenvBool("DRY_RUN"),
+		DryRunOutput:       os.Getenv("DRY_RUN_OUTPUT"),
+		SandboxGroupID:     os.Getenv("SANDBOX_GROUP_ID"),
+		TemplatesDir:       os.Getenv("TEMPLATES_DIR"),
+		DataDir:            getenvDefault("DATA_DIR", DefaultDataDir),
+		ProfileUrl:         getenvDefault("SEATALK_PROFILE_URL", apiPath(apiURL, "/contacts/v2/profile")),
+		EmployeeCodeUrl:    getenvDefault("SEATALK_EMPLOYEE_CODE_URL", apiPath(apiURL, "/contacts/v2/get_employee_code_with_email")),
+		DirectoryTTL:       getenvDuration("DIRECTORY_TTL", DefaultDirectoryTTL),
+		PICNoticeDays:      getenvInt("PIC_NOTICE_DAYS", DefaultPICNoticeDays),
+		Owners:             getenvList("OWNERS"),
+		PublicURL:          strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"),
+		CalendarToken:      os.Getenv("CALENDAR_TOKEN"),
+		RunwayPeriods:      getenvInt("RUNWAY_PERIODS", DefaultRunwayPeriods),
+		RunwayAlert:        strings.ToLower(getenvDefault("RUNWAY_ALERT", RunwayAlertDM)),
+		AnswerBackend:      strings.ToLower(os.Getenv("ANSWER_BACKEND")),
+		AnswerURL:          strings.TrimSuffix(os.Getenv("ANSWER_URL"), "/"),
+		AnswerAPIKey:       os.Getenv("ANSWER_API_KEY"),
+		AnswerModel:        os.Getenv("ANSWER_MODEL"),
+		AnswerSystemPrompt: os.Getenv("ANSWER_SYSTEM_PROMPT"),
+		AnswerMaxTokens:    getenvInt("ANSWER_MAX_TOKENS", DefaultAnswerMaxTokens),
+		AnswerMaxChars:     getenvInt("ANSWER_MAX_CHARS", DefaultAnswerMaxChars),
+		AnswerHistory:      getenvInt("ANSWER_HISTORY", DefaultAnswerHistory),
+		AnswerTimeout:      getenvDuration("ANSWER_TIMEOUT", DefaultAnswerTimeout),
+	}, nil
+}
+
+// Validate reports missing or malformed settings required to talk to SeaTalk
+func (c *Config) Validate() error {
+	var problems []string
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"SEATALK_APP_ID", c.AppID},
+		{"SEATALK_APP_SECRET", c.AppSecret},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			problems = append(problems, r.name+" is not set")
+		}
+	}
+
+	urls := []struct {
+		name  string
+		value string
+	}{
+		{"SEATALK_AUTH_URL", c.AuthURL},
+		{"SEATALK_SEND_SINGLE_CHAT_URL", c.SingleChatUrl},
+		{"SEATALK_SEND_GROUP_CHAT_URL", c.GroupChatUrl},
+	}
+	for _, u := range urls {
+		if strings.TrimSpace(u.value) == "" {
+			problems = append(problems, u.name+" is not set")
+			continue
+		}
+		if parsed, err := url.Parse(u.value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, u.name+" is not a valid URL")
+		}
+	}
+
+	// Roles trust the sender written in the callback, which only the
+	// signature proves
+	if len(c.Owners) > 0 && strings.TrimSpace(c.SigningSecret) == "" {
+		problems = append(problems, "SEATALK_SIGNING_SECRET is required when OWNERS is set")
+	}
+
+	switch c.RunwayAlert {
+	case "", RunwayAlertDM, RunwayAlertGroup, RunwayAlertBoth:
+	default:
+		problems = append(problems, "RUNWAY_ALERT must be dm, group or both")
+	}
+
+	switch c.AnswerBackend {
+	case "", AnswerBackendStub:
+	case AnswerBackendOpenAI:
+		if parsed, err := url.Parse(c.AnswerURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, "ANSWER_URL is not a valid URL")
+		}
+		if strings.TrimSpace(c.AnswerModel) == "" {
+			problems = append(problems, "ANSWER_MODEL is not set")
+		}
+	default:
+		problems = append(problems, "ANSWER_BACKEND must be openai, stub or empty")
+	}
+
+	if len(problems) > 0 {
+		return errors.New(constants.ErrInvalidConfig + ": " + strings.Join(problems, "; "))
 	}
+	return nil
+}
+
+// getenvFallback returns the first non-empty environment variable among keys
+func getenvFallback(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getenvDefault returns the environment variable, or def when it is empty
+func getenvDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// getenvDuration parses the environment variable as a duration, returning def
+// when it is empty or malformed
+func getenvDuration(key string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
+
+// getenvInt parses the environment variable as a positive integer, returning
+// def when it is empty or malformed
+func getenvInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
+
+// getenvList splits a comma-separated environment variable, dropping empty items
+func getenvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// apiPath joins an API base URL and an endpoint path, or returns an empty
+// string when no base URL is configured
+func apiPath(base, path string) string {
+	if base == "" {
+		return ""
+	}
+	return base + path
+}
+
+// getenvBool reports whether the environment variable is set to a true value
+func getenvBool(key string) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	return err == nil && value
 }
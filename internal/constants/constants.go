@@ -3,5 +3,20 @@ package constants
 const (
 	DateFormat                 = "2-Jan-2006"
 	StockInventoryScheduleFile = "stock_inventory_schedule.txt"
-	ReminderReturnRefund       = "Hello, it's time to do the return refund test. Please make sure to do it before 12 PM today. Thank you!"
+	StockInventoryRotation     = "stock-inventory"
+	JobStockInventoryPIC       = "stock_inventory_pic"
+	JobReturnRefundReminder    = "return_refund_reminder"
+	JobDirectorySync           = "directory_sync"
+	JobPICNotice               = "pic_notice"
+	JobReleaseHeld             = "release_held_messages"
+	LocalesFile                = "locales.json"
+	DirectoryFile              = "directory.json"
+	SubscriptionsFile          = "subscriptions.json"
+	GroupsFile                 = "groups.json"
+	HeldMessagesFile           = "held_messages.json"
+	RolesFile                  = "roles.json"
+	AuditFile                  = "audit.log"
+	ScheduleHistoryDir         = "schedule_history"
+	RotationsDir               = "rotations"
+	FAQFile                    = "faq.json"
 )
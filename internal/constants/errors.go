@@ -16,4 +16,46 @@ const (
 	ErrorPreviousPICNotFound  = "no previous PIC found"
 	ErrorInvalidDateFormat    = "invalid date format"
 	ErrorWriteSchedule        = "failed to write schedule"
+	ErrInvalidSignature       = "invalid callback signature"
+	ErrInvalidEventPayload    = "invalid event payload"
+	ErrHandlerPanic           = "event handler panicked"
+	ErrInvalidConfig          = "invalid configuration"
+	ErrCheckTimedOut          = "check timed out"
+	ErrSchedulerNotRunning    = "scheduler is not running"
+	ErrJobExists              = "job already registered"
+	ErrJobNotFound            = "job not found"
+	ErrJobPanicked            = "job panicked"
+	ErrInvalidCronSpec        = "invalid cron spec"
+	ErrRotationNotFound       = "rotation not found"
+	ErrUnauthorizedAdmin      = "invalid admin token"
+	ErrMissingRecipient       = "either group_id or employee_code is required"
+	ErrEmptyMessage           = "message text is required"
+	ErrFailedToDecodeRequest  = "failed to decode request"
+	ErrInvalidScheduleEntry   = "invalid schedule entry"
+	ErrInvalidTemplate        = "invalid message template"
+	ErrTemplateNotFound       = "message template not found"
+	ErrFailedToRenderTemplate = "failed to render message template"
+	ErrFailedToDecodeFile     = "failed to decode file"
+	ErrUnsupportedLocale      = "unsupported locale"
+	ErrUnknownCommand         = "unknown command"
+	ErrAtAllNotAllowed        = "group does not allow mentioning everyone"
+	ErrEmployeeNotFound       = "employee not found"
+	ErrNoDirectorySource      = "no employee directory source is configured"
+	ErrUnknownTopic           = "unknown notification topic"
+	ErrGroupNotFound          = "group not found"
+	ErrInvalidQuietHours      = "invalid quiet hours"
+	ErrInvalidTimezone        = "invalid timezone"
+	ErrUnknownRole            = "unknown role"
+	ErrPermissionDenied       = "permission denied"
+	ErrVersionNotFound        = "schedule version not found"
+	ErrNothingToUndo          = "no schedule change to undo"
+	ErrRotationNotConfigured  = "rotation has no members configured"
+	ErrRotationConfigured     = "rotation is already configured"
+	ErrInvalidImport          = "invalid import file"
+	ErrImportColumnMissing    = "import file has no column for"
+	ErrInvalidSchedule        = "schedule file is invalid"
+	ErrInvalidFAQ             = "invalid FAQ entry"
+	ErrFAQNotFound            = "FAQ entry not found"
+	ErrUnknownAnswerBackend   = "unknown answer backend"
+	ErrAnswerFailed           = "failed to answer the question"
 )
@@ -0,0 +1,56 @@
+package filestore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"seatalk-bot/internal/constants"
+)
+
+// LoadJSON decodes the JSON file at path into v. A missing file leaves v untouched.
+func LoadJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.New(constants.ErrorFileRead + ": " + err.Error())
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.New(constants.ErrFailedToDecodeFile + ": " + path + ": " + err.Error())
+	}
+	return nil
+}
+
+// SaveJSON atomically replaces the file at path with v encoded as JSON,
+// creating the parent directory when needed
+func SaveJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.New(constants.ErrFailedToMarshalPayload + ": " + err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	return nil
+}
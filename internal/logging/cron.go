@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronLogger adapts a slog.Logger to the cron.Logger interface
+type cronLogger struct {
+	logger *slog.Logger
+}
+
+// CronLogger returns a cron.Logger writing to the given slog.Logger
+func CronLogger(logger *slog.Logger) cron.Logger {
+	return cronLogger{logger: logger.With("component", "cron")}
+}
+
+func (l cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Debug(msg, keysAndValues...)
+}
+
+func (l cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error(msg, append([]interface{}{"error", err}, keysAndValues...)...)
+}
+
+// Job wraps a job function so each run is logged with the job name, duration and
+// error. Panics are logged with the job name and re-raised to the cron.Recover wrapper.
+func Job(name string, fn func() error) func() error {
+	return JobAt(name, slog.LevelInfo, fn)
+}
+
+// JobAt is Job logging the start and end of successful runs at the given
+// level. Failures are always logged as errors.
+func JobAt(name string, level slog.Level, fn func() error) func() error {
+	return func() error {
+		logger := slog.Default().With("job", name)
+		start := time.Now()
+		logger.Log(context.Background(), level, "job started")
+
+		defer func() {
+			if p := recover(); p != nil {
+				logger.Error("job panicked", "panic", p, "duration", time.Since(start))
+				panic(p)
+			}
+		}()
+
+		if err := fn(); err != nil {
+			logger.Error("job failed", "duration", time.Since(start), "error", err)
+			return err
+		}
+		logger.Log(context.Background(), level, "job finished", "duration", time.Since(start))
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in and out of the HTTP server
+const RequestIDHeader = "X-Request-ID"
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	// wrote is set once the handler has sent the headers or part of the body
+	wrote bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status, r.wrote = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware assigns a request ID to every request, logs it with its status and
+// duration, and recovers from panics in the wrapped handler
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := WithRequestID(r.Context(), requestID)
+		logger := FromContext(ctx)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		defer func() {
+			if p := recover(); p != nil {
+				logger.Error("panic serving request",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"panic", p,
+					"stack", string(debug.Stack()),
+				)
+				// A response already under way cannot be replaced by an error
+				if !rec.wrote {
+					http.Error(rec, "Internal server error", http.StatusInternalServerError)
+				}
+			}
+			logger.Info("http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"duration", time.Since(start),
+			)
+		}()
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+	})
+}
+
+// NewRequestID generates a random request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		slog.Warn("failed to generate request ID", "error", err)
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
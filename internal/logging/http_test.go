@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareRecovers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+		body    string
+	}{
+		{
+			"panic before writing",
+			func(w http.ResponseWriter, r *http.Request) { panic("boom") },
+			http.StatusInternalServerError, "Internal server error\n",
+		},
+		{
+			"panic after the headers",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("boom")
+			},
+			http.StatusAccepted, "",
+		},
+		{
+			"panic after part of the body",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"partial":`))
+				panic("boom")
+			},
+			http.StatusOK, `{"partial":`,
+		},
+		{
+			"no panic",
+			func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) },
+			http.StatusOK, "ok",
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		Middleware(tt.handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != tt.status || w.Body.String() != tt.body {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, w.Code, w.Body.String(), tt.status, tt.body)
+		}
+	}
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get(RequestIDHeader); got != "abc123" {
+		t.Errorf("%s = %q, want the one of the request", RequestIDHeader, got)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := w.Header().Get(RequestIDHeader); len(got) != 16 || strings.Trim(got, "0123456789abcdef") != "" {
+		t.Errorf("%s = %q, want a generated ID", RequestIDHeader, got)
+	}
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "seatalk_bot"
+
+// Callback outcomes
+const (
+	OutcomeOK           = "ok"
+	OutcomeError        = "error"
+	OutcomeIgnored      = "ignored"
+	OutcomeUnauthorized = "unauthorized"
+	OutcomeBadRequest   = "bad_request"
+	OutcomePanic        = "panic"
+)
+
+var (
+	callbacksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callbacks_total",
+		Help:      "Event callbacks received, by event type and outcome.",
+	}, []string{"event_type", "outcome"})
+
+	callbackDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "callback_duration_seconds",
+		Help:      "Time spent handling event callbacks, by event type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"event_type"})
+
+	outboundRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "seatalk_requests_total",
+		Help:      "Outbound SeaTalk API calls, by endpoint, HTTP status and SeaTalk response code.",
+	}, []string{"endpoint", "status", "code"})
+
+	outboundRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "seatalk_request_duration_seconds",
+		Help:      "Latency of outbound SeaTalk API calls, by endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	tokenRefreshesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_refreshes_total",
+		Help:      "App access token requests sent to the auth endpoint.",
+	})
+
+	tokenRefreshFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_refresh_failures_total",
+		Help:      "App access token requests that failed.",
+	})
+
+	jobRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Scheduled job runs, by job and outcome.",
+	}, []string{"job", "outcome"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of scheduled job runs, by job.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"job"})
+
+	jobLastDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_duration_seconds",
+		Help:      "Duration of the most recent run of each job.",
+	}, []string{"job"})
+
+	jobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of each job.",
+	}, []string{"job"})
+
+	sendsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seatalk_sends_in_flight",
+		Help:      "Messages being sent to SeaTalk, awaiting its response.",
+	})
+)
+
+// Handler returns the HTTP handler serving the /metrics endpoint
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveCallback records a handled event callback
+func ObserveCallback(eventType, outcome string, duration time.Duration) {
+	callbacksTotal.WithLabelValues(eventType, outcome).Inc()
+	callbackDuration.WithLabelValues(eventType).Observe(duration.Seconds())
+}
+
+// ObserveSeaTalkRequest records an outbound SeaTalk API call.
+// A zero status means the request never got a response.
+func ObserveSeaTalkRequest(endpoint string, status, code int, duration time.Duration) {
+	statusLabel := "none"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	outboundRequestsTotal.WithLabelValues(endpoint, statusLabel, strconv.Itoa(code)).Inc()
+	outboundRequestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// ObserveTokenRefresh records an access token request and whether it failed
+func ObserveTokenRefresh(err error) {
+	tokenRefreshesTotal.Inc()
+	if err != nil {
+		tokenRefreshFailuresTotal.Inc()
+	}
+}
+
+// SendsInFlightAdd adjusts the number of messages being sent to SeaTalk
+func SendsInFlightAdd(delta int) {
+	sendsInFlight.Add(float64(delta))
+}
+
+// Job wraps a scheduled job so its runs, duration and last success are recorded
+func Job(name string, fn func() error) func() error {
+	return func() (err error) {
+		start := time.Now()
+		outcome := OutcomePanic
+		defer func() {
+			duration := time.Since(start)
+			jobRunsTotal.WithLabelValues(name, outcome).Inc()
+			jobDuration.WithLabelValues(name).Observe(duration.Seconds())
+			jobLastDuration.WithLabelValues(name).Set(duration.Seconds())
+			if outcome == OutcomeOK {
+				jobLastSuccess.WithLabelValues(name).SetToCurrentTime()
+			}
+		}()
+
+		err = fn()
+		outcome = OutcomeOK
+		if err != nil {
+			outcome = OutcomeError
+		}
+		return err
+	}
+}
@@ -0,0 +1,22 @@
+package request
+
+// AdminScheduleEntry represents a rotation entry edited through the admin API.
+// Date uses the 2006-01-02 format.
+type AdminScheduleEntry struct {
+	PIC   string `json:"pic"`
+	Date  string `json:"date"`
+	Email string `json:"email"`
+}
+
+// AdminUpdateScheduleRequest replaces every entry of a rotation
+type AdminUpdateScheduleRequest struct {
+	Entries []AdminScheduleEntry `json:"entries"`
+}
+
+// AdminSendMessageRequest sends an ad-hoc message to a group or an employee
+type AdminSendMessageRequest struct {
+	GroupID      string `json:"group_id,omitempty"`
+	EmployeeCode string `json:"employee_code,omitempty"`
+	Text         string `json:"text"`
+	ThreadID     string `json:"thread_id,omitempty"`
+}
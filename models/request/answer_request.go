@@ -0,0 +1,16 @@
+package request
+
+// ChatMessage is a message of a conversation sent to an OpenAI-compatible
+// chat completions endpoint
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatCompletionRequest asks an OpenAI-compatible endpoint to continue a conversation
+type ChatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+}
@@ -1,48 +1,28 @@
 package request
 
-type EventCallbackRequest struct {
-	EventID   string `json:"event_id"`
-	EventType string `json:"event_type"`
-	Timestamp int64  `json:"timestamp"`
-	AppID     string `json:"app_id"`
-	Event     struct {
-		SeaTalkChallenge string `json:"seatalk_challenge"`
-		EmployeeCode     string `json:"employee_code"`
-		GroupID          string `json:"group_id"`
-		Message          struct {
-			MessageID       string `json:"message_id"`
-			QuotedMessageID string `json:"quoted_message_id"`
-			ThreadID        string `json:"thread_id"`
-			Sender          struct {
-				SeatalkID    string `json:"seatalk_id"`
-				EmployeeCode string `json:"employee_code"`
-			} `json:"sender"`
-			MessageSentTime int64  `json:"message_sent_time"`
-			Tag             string `json:"tag"`
-			Text            struct {
-				Content       string `json:"content"`
-				PlainText     string `json:"plain_text"`
-				MentionedList []struct {
-					Username  string `json:"username"`
-					SeatalkID string `json:"seatalk_id"`
-				} `json:"mentioned_list"`
-			} `json:"text"`
-		} `json:"message"`
-		Group struct {
-			GroupID       string `json:"group_id"`
-			GroupName     string `json:"group_name"`
-			GroupSettings struct {
-				ChatHistoryForNewMembers string `json:"chat_history_for_new_members"`
-				CanNotifyWithAtAll       bool   `json:"can_notify_with_at_all"`
-				CanViewMemberList        bool   `json:"can_view_member_list"`
-			} `json:"group_settings"`
-		} `json:"group"`
-		Inviter struct {
-			SeaTalkID    string `json:"seatalk_id"`
-			EmployeeCode string `json:"employee_code"`
-		} `json:"inviter"`
-	} `json:"event"`
+import "encoding/json"
+
+// Event types delivered to the event callback URL
+const (
+	EventTypeVerification                     = "event_verification"
+	EventTypeNewBotSubscriber                 = "new_bot_subscriber"
+	EventTypeMessageFromBotSubscriber         = "message_from_bot_subscriber"
+	EventTypeBotAddedToGroupChat              = "bot_added_to_group_chat"
+	EventTypeBotRemovedFromGroupChat          = "bot_removed_from_group_chat"
+	EventTypeNewMentionedMessageFromGroupChat = "new_mentioned_message_from_group_chat"
+	EventTypeInteractiveMessageClick          = "interactive_message_click"
+)
+
+// EventCallbackEnvelope is the common wrapper of every event callback.
+// Event is kept raw and decoded into a concrete type based on EventType.
+type EventCallbackEnvelope struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Timestamp int64           `json:"timestamp"`
+	AppID     string          `json:"app_id"`
+	Event     json.RawMessage `json:"event"`
 }
+
 type EventCallback struct {
 	SeatalkChallenge string `json:"seatalk_challenge"`
 }
@@ -0,0 +1,6 @@
+package request
+
+// GetEmployeeCodesByEmailRequest looks up the employee codes of the given emails
+type GetEmployeeCodesByEmailRequest struct {
+	Emails []string `json:"emails"`
+}
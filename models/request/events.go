@@ -0,0 +1,90 @@
+package request
+
+// VerificationEvent is sent when the callback URL is configured
+type VerificationEvent struct {
+	SeaTalkChallenge string `json:"seatalk_challenge"`
+}
+
+// NewBotSubscriberEvent is sent when a user subscribes to the bot
+type NewBotSubscriberEvent struct {
+	EmployeeCode string `json:"employee_code"`
+	SeatalkID    string `json:"seatalk_id"`
+}
+
+// MessageFromBotSubscriberEvent is sent when a subscriber messages the bot directly
+type MessageFromBotSubscriberEvent struct {
+	EmployeeCode string       `json:"employee_code"`
+	SeatalkID    string       `json:"seatalk_id"`
+	Message      EventMessage `json:"message"`
+}
+
+// NewMentionedMessageFromGroupChatEvent is sent when the bot is mentioned in a group
+type NewMentionedMessageFromGroupChatEvent struct {
+	GroupID string       `json:"group_id"`
+	Message EventMessage `json:"message"`
+}
+
+// BotAddedToGroupChatEvent is sent when the bot joins a group
+type BotAddedToGroupChatEvent struct {
+	Group   EventGroup `json:"group"`
+	Inviter EventUser  `json:"inviter"`
+}
+
+// BotRemovedFromGroupChatEvent is sent when the bot is removed from a group
+type BotRemovedFromGroupChatEvent struct {
+	Group   EventGroup `json:"group"`
+	Remover EventUser  `json:"remover"`
+}
+
+// InteractiveMessageClickEvent is sent when a user clicks a callback button
+type InteractiveMessageClickEvent struct {
+	MessageID    string `json:"message_id"`
+	EmployeeCode string `json:"employee_code"`
+	SeatalkID    string `json:"seatalk_id"`
+	GroupID      string `json:"group_id"`
+	Value        string `json:"value"`
+}
+
+// EventMessage represents a message carried by a callback event
+type EventMessage struct {
+	MessageID       string           `json:"message_id"`
+	QuotedMessageID string           `json:"quoted_message_id"`
+	ThreadID        string           `json:"thread_id"`
+	Sender          EventUser        `json:"sender"`
+	MessageSentTime int64            `json:"message_sent_time"`
+	Tag             string           `json:"tag"`
+	Text            EventMessageText `json:"text"`
+}
+
+// EventMessageText represents the text content of a callback message
+type EventMessageText struct {
+	Content       string          `json:"content"`
+	PlainText     string          `json:"plain_text"`
+	MentionedList []MentionedUser `json:"mentioned_list"`
+}
+
+// MentionedUser represents a user mentioned in a group message
+type MentionedUser struct {
+	Username  string `json:"username"`
+	SeatalkID string `json:"seatalk_id"`
+}
+
+// EventUser identifies the user behind an event
+type EventUser struct {
+	SeatalkID    string `json:"seatalk_id"`
+	EmployeeCode string `json:"employee_code"`
+}
+
+// EventGroup represents the group details of a group event
+type EventGroup struct {
+	GroupID       string             `json:"group_id"`
+	GroupName     string             `json:"group_name"`
+	GroupSettings EventGroupSettings `json:"group_settings"`
+}
+
+// EventGroupSettings represents the settings of a group
+type EventGroupSettings struct {
+	ChatHistoryForNewMembers string `json:"chat_history_for_new_members"`
+	CanNotifyWithAtAll       bool   `json:"can_notify_with_at_all"`
+	CanViewMemberList        bool   `json:"can_view_member_list"`
+}
@@ -0,0 +1,70 @@
+package response
+
+import "time"
+
+// AdminJob represents a scheduled job returned by the admin API
+type AdminJob struct {
+	Name    string     `json:"name"`
+	Spec    string     `json:"spec"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+	PrevRun *time.Time `json:"prev_run,omitempty"`
+	Paused  bool       `json:"paused"`
+}
+
+// AdminJobsResponse lists the scheduled jobs
+type AdminJobsResponse struct {
+	Jobs []AdminJob `json:"jobs"`
+}
+
+// AdminRotationsResponse lists the rotations managed by the bot
+type AdminRotationsResponse struct {
+	Rotations []string `json:"rotations"`
+}
+
+// AdminScheduleEntry represents a rotation entry returned by the admin API
+type AdminScheduleEntry struct {
+	PIC   string `json:"pic"`
+	Date  string `json:"date"`
+	Email string `json:"email"`
+}
+
+// AdminScheduleResponse represents the entries of a rotation
+type AdminScheduleResponse struct {
+	Rotation string               `json:"rotation"`
+	Entries  []AdminScheduleEntry `json:"entries"`
+}
+
+// AdminImportIssue represents a row of an imported file that has a problem
+type AdminImportIssue struct {
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Message  string `json:"message"`
+}
+
+// AdminImportResponse represents the validation report of an imported file
+type AdminImportResponse struct {
+	Rotation string               `json:"rotation"`
+	Rows     int                  `json:"rows"`
+	Imported int                  `json:"imported"`
+	Applied  bool                 `json:"applied"`
+	Issues   []AdminImportIssue   `json:"issues"`
+	Entries  []AdminScheduleEntry `json:"entries"`
+}
+
+// AdminMessageResponse represents the result of an ad-hoc message
+type AdminMessageResponse struct {
+	Code      int    `json:"code"`
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// AdminStatusResponse acknowledges an admin action
+type AdminStatusResponse struct {
+	Status string `json:"status"`
+}
+
+// ErrorResponse represents an error returned by the bot's own API
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
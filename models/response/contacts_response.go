@@ -0,0 +1,30 @@
+package response
+
+// EmployeeProfile is the profile of an employee returned by the contacts API
+type EmployeeProfile struct {
+	EmployeeCode    string   `json:"employee_code"`
+	SeatalkID       string   `json:"seatalk_id"`
+	SeatalkNickname string   `json:"seatalk_nickname"`
+	Name            string   `json:"name"`
+	Email           string   `json:"email"`
+	Departments     []string `json:"departments"`
+}
+
+// GetEmployeeProfilesResponse represents the response of the profile API
+type GetEmployeeProfilesResponse struct {
+	Code      int               `json:"code"`
+	Employees []EmployeeProfile `json:"employees"`
+}
+
+// EmployeeCodeResult maps an email to the employee code registered with it
+type EmployeeCodeResult struct {
+	Email          string `json:"email"`
+	EmployeeCode   string `json:"employee_code"`
+	EmployeeStatus int    `json:"employee_status"`
+}
+
+// GetEmployeeCodesByEmailResponse represents the response of the employee code lookup API
+type GetEmployeeCodesByEmailResponse struct {
+	Code      int                  `json:"code"`
+	Employees []EmployeeCodeResult `json:"employees"`
+}
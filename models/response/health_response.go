@@ -0,0 +1,14 @@
+package response
+
+// HealthResponse represents the body returned by the health and readiness endpoints
+type HealthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult represents the outcome of a single readiness check
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
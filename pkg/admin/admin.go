@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/jobs"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Messenger sends messages through the SeaTalk API
+type Messenger interface {
+	SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error)
+	SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error)
+}
+
+// auditReasonHeader carries the reason recorded in the audit log for a change
+const auditReasonHeader = "X-Audit-Reason"
+
+// Handler serves the authenticated admin REST API
+type Handler struct {
+	token     string
+	jobs      *jobs.Runner
+	schedules *schedule.Registry
+	messenger Messenger
+	audit     *audit.Log
+	mux       *http.ServeMux
+}
+
+// NewHandler creates the admin API protected by the given bearer token.
+// Every change made through it is recorded in the audit log.
+func NewHandler(token string, runner *jobs.Runner, schedules *schedule.Registry, messenger Messenger, log *audit.Log) *Handler {
+	h := &Handler{
+		token:     token,
+		jobs:      runner,
+		schedules: schedules,
+		messenger: messenger,
+		audit:     log,
+		mux:       http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /admin/jobs", h.listJobs)
+	h.mux.HandleFunc("POST /admin/jobs/{name}/run", h.runJob)
+	h.mux.HandleFunc("POST /admin/jobs/{name}/pause", h.pauseJob)
+	h.mux.HandleFunc("POST /admin/jobs/{name}/resume", h.resumeJob)
+	h.mux.HandleFunc("GET /admin/schedules", h.listRotations)
+	h.mux.HandleFunc("GET /admin/schedules/{rotation}", h.getSchedule)
+	h.mux.HandleFunc("PUT /admin/schedules/{rotation}", h.updateSchedule)
+	h.mux.HandleFunc("POST /admin/schedules/{rotation}/import", h.importSchedule)
+	h.mux.HandleFunc("POST /admin/messages", h.sendMessage)
+
+	return h
+}
+
+// ServeHTTP authenticates the request and routes it to the admin endpoint
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		writeError(w, http.StatusUnauthorized, constants.ErrUnauthorizedAdmin)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized checks the bearer token of the request against the admin token
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// auditContext attributes the changes made by a request to the admin API,
+// explained by its X-Audit-Reason header
+func auditContext(r *http.Request) context.Context {
+	ctx := audit.WithActor(r.Context(), audit.ActorAdminAPI)
+	if reason := strings.TrimSpace(r.Header.Get(auditReasonHeader)); reason != "" {
+		ctx = audit.WithReason(ctx, reason)
+	}
+	return ctx
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, response.ErrorResponse{Error: message})
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/jobs"
+	"seatalk-bot/pkg/schedule"
+)
+
+const token = "admin-token"
+
+// fakeMessenger records the messages sent through the admin API
+type fakeMessenger struct {
+	groups      []request.SendMessageToBotGroupRequest
+	subscribers []request.SendMessageToBotSubscriberRequest
+}
+
+func (m *fakeMessenger) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	m.groups = append(m.groups, req)
+	return response.SendMessageToBotGroupResponse{MessegeId: "m1"}, nil
+}
+
+func (m *fakeMessenger) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	m.subscribers = append(m.subscribers, req)
+	return response.SendMessageToBotSubscriberResponse{}, nil
+}
+
+// newHandler creates an admin API with one job and one rotation
+func newHandler(t *testing.T) (*Handler, *fakeMessenger) {
+	h, messenger, _ := newAuditedHandler(t)
+	return h, messenger
+}
+
+// newAuditedHandler creates an admin API that records into the returned audit log
+func newAuditedHandler(t *testing.T) (*Handler, *fakeMessenger, *audit.Log) {
+	t.Helper()
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "schedule.csv")
+	if err := os.WriteFile(filename, []byte("Alice,20-Oct-2026,alice@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner := jobs.NewRunner(clock.Real{})
+	runner.Add("announce", "0 9 * * 2", func() error { return nil })
+	messenger := &fakeMessenger{}
+	log := audit.Open(filepath.Join(dir, "audit.jsonl"), clock.Real{})
+	store := schedule.NewStore("stock", filename, clock.Real{})
+	store.SetAudit(log)
+	return NewHandler(token, runner, schedule.NewRegistry(store), messenger, log), messenger, log
+}
+
+func TestAdminAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		token  string
+		status int
+		// reply is a fragment expected in the response body
+		reply string
+	}{
+		{"no token", http.MethodGet, "/admin/jobs", "", "", http.StatusUnauthorized, ""},
+		{"wrong token", http.MethodGet, "/admin/jobs", "", "other", http.StatusUnauthorized, ""},
+		{"list jobs", http.MethodGet, "/admin/jobs", "", token, http.StatusOK, `"name":"announce"`},
+		{"run job", http.MethodPost, "/admin/jobs/announce/run", "", token, http.StatusOK, `"completed"`},
+		{"pause job", http.MethodPost, "/admin/jobs/announce/pause", "", token, http.StatusOK, `"paused"`},
+		{"resume job", http.MethodPost, "/admin/jobs/announce/resume", "", token, http.StatusOK, `"resumed"`},
+		{"unknown job", http.MethodPost, "/admin/jobs/missing/run", "", token, http.StatusNotFound, ""},
+		{"list rotations", http.MethodGet, "/admin/schedules", "", token, http.StatusOK, `["stock"]`},
+		{"get schedule", http.MethodGet, "/admin/schedules/stock", "", token, http.StatusOK, `"date":"2026-10-20"`},
+		{"unknown rotation", http.MethodGet, "/admin/schedules/missing", "", token, http.StatusNotFound, ""},
+		{
+			"replace schedule", http.MethodPut, "/admin/schedules/stock",
+			`{"entries":[{"pic":"Bob","date":"2026-10-27","email":"bob@example.com"},{"pic":"Alice","date":"2026-10-20"}]}`,
+			token, http.StatusOK, `[{"pic":"Alice","date":"2026-10-20","email":""},{"pic":"Bob"`,
+		},
+		{"invalid date", http.MethodPut, "/admin/schedules/stock", `{"entries":[{"pic":"Bob","date":"27-Oct-2026"}]}`, token, http.StatusBadRequest, ""},
+		{"comma in name", http.MethodPut, "/admin/schedules/stock", `{"entries":[{"pic":"Bob,Carol","date":"2026-10-27"}]}`, token, http.StatusBadRequest, ""},
+		{"message to group", http.MethodPost, "/admin/messages", `{"group_id":"g1","text":"hello"}`, token, http.StatusOK, `"message_id":"m1"`},
+		{"message to employee", http.MethodPost, "/admin/messages", `{"employee_code":"E001","text":"hello"}`, token, http.StatusOK, ""},
+		{"empty message", http.MethodPost, "/admin/messages", `{"group_id":"g1","text":" "}`, token, http.StatusBadRequest, ""},
+		{"no recipient", http.MethodPost, "/admin/messages", `{"text":"hello"}`, token, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newHandler(t)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.reply) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.reply)
+			}
+		})
+	}
+}
+
+func TestNoTokenDisablesAPI(t *testing.T) {
+	h := NewHandler("", jobs.NewRunner(clock.Real{}), schedule.NewRegistry(), &fakeMessenger{}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMessagesAreSent(t *testing.T) {
+	h, messenger := newHandler(t)
+	for _, body := range []string{
+		`{"group_id":"g1","thread_id":"t1","text":"hello group"}`,
+		`{"employee_code":"E001","text":"hello you"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/messages", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(messenger.groups) != 1 || messenger.groups[0].GroupID != "g1" || messenger.groups[0].Message.ThreadID != "t1" || messenger.groups[0].Message.Text.Content != "hello group" {
+		t.Errorf("group messages = %+v", messenger.groups)
+	}
+	if len(messenger.subscribers) != 1 || messenger.subscribers[0].EmployeeCode != "E001" || messenger.subscribers[0].Message.Text.Content != "hello you" {
+		t.Errorf("subscriber messages = %+v", messenger.subscribers)
+	}
+}
+
+func TestActionsAreAudited(t *testing.T) {
+	h, _, log := newAuditedHandler(t)
+	for _, call := range []struct{ method, path, body string }{
+		{http.MethodPost, "/admin/jobs/announce/pause", ""},
+		{http.MethodPut, "/admin/schedules/stock", `{"entries":[{"pic":"Bob","date":"2026-10-27"}]}`},
+		{http.MethodPost, "/admin/messages", `{"group_id":"g1","text":"hello"}`},
+	} {
+		req := httptest.NewRequest(call.method, call.path, strings.NewReader(call.body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Audit-Reason", "handover")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries, err := log.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{audit.ActionAdminJobPause, audit.ActionScheduleReplace, audit.ActionAdminMessage}
+	if len(entries) != len(want) {
+		t.Fatalf("recorded %+v, want %q", entries, want)
+	}
+	for i, e := range entries {
+		if e.Action != want[i] || e.Actor != audit.ActorAdminAPI || e.Reason != "handover" || e.Outcome != audit.OutcomeOK {
+			t.Errorf("entry %d = %+v, want %s by %s for the given reason", i, e, want[i], audit.ActorAdminAPI)
+		}
+	}
+	if got := entries[1].Details["removed"]; got != "Alice,20-Oct-2026,alice@example.com" {
+		t.Errorf("removed = %q, want the replaced line", got)
+	}
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/importer"
+)
+
+// maxImportSize bounds the size of an imported file
+const maxImportSize = 5 << 20
+
+// importSchedule replaces the entries of a rotation with the rows of the CSV
+// or XLSX file sent as request body. Nothing is written when dry_run is set
+// or when a row is invalid, unless skip_invalid is set.
+func (h *Handler) importSchedule(w http.ResponseWriter, r *http.Request) {
+	store, err := h.schedules.Get(r.PathValue("rotation"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, constants.ErrInvalidImport+": "+err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	columns := importer.Columns{
+		Name:  query.Get("name_column"),
+		Date:  query.Get("date_column"),
+		Email: query.Get("email_column"),
+	}
+	result, err := importer.Parse(data, query.Get("format"), columns)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report := response.AdminImportResponse{
+		Rotation: store.Name(),
+		Rows:     result.Rows,
+		Imported: len(result.Schedules),
+		Issues:   make([]response.AdminImportIssue, 0, len(result.Issues)),
+		Entries:  scheduleResponse(store.Name(), result.Schedules).Entries,
+	}
+	for _, issue := range result.Issues {
+		report.Issues = append(report.Issues, response.AdminImportIssue{
+			Line:     issue.Line,
+			Severity: issue.Severity,
+			Field:    issue.Field,
+			Value:    issue.Value,
+			Message:  issue.Message,
+		})
+	}
+
+	dryRun, _ := strconv.ParseBool(query.Get("dry_run"))
+	skipInvalid, _ := strconv.ParseBool(query.Get("skip_invalid"))
+	switch {
+	case dryRun:
+		writeJSON(w, http.StatusOK, report)
+		return
+	case !result.Valid() && !skipInvalid, len(result.Schedules) == 0:
+		writeJSON(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	if err := store.Replace(auditContext(r), result.Schedules); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	report.Applied = true
+	writeJSON(w, http.StatusOK, report)
+}
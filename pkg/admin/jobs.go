@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/audit"
+)
+
+// listJobs returns every cron entry with its next and previous run times
+func (h *Handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	infos := h.jobs.List()
+	result := response.AdminJobsResponse{Jobs: make([]response.AdminJob, 0, len(infos))}
+	for _, info := range infos {
+		job := response.AdminJob{
+			Name:   info.Name,
+			Spec:   info.Spec,
+			Paused: info.Paused,
+		}
+		if !info.Next.IsZero() {
+			next := info.Next
+			job.NextRun = &next
+		}
+		if !info.Prev.IsZero() {
+			prev := info.Prev
+			job.PrevRun = &prev
+		}
+		result.Jobs = append(result.Jobs, job)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// runJob runs a job immediately and reports its outcome
+func (h *Handler) runJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := h.jobs.Trigger(name)
+	h.audit.RecordResult(auditContext(r), audit.Entry{Action: audit.ActionAdminJobRun, Target: name}, err)
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response.AdminStatusResponse{Status: "completed"})
+}
+
+// pauseJob stops scheduled runs of a job
+func (h *Handler) pauseJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := h.jobs.Pause(name)
+	h.audit.RecordResult(auditContext(r), audit.Entry{Action: audit.ActionAdminJobPause, Target: name}, err)
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response.AdminStatusResponse{Status: "paused"})
+}
+
+// resumeJob re-enables scheduled runs of a job
+func (h *Handler) resumeJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := h.jobs.Resume(name)
+	h.audit.RecordResult(auditContext(r), audit.Entry{Action: audit.ActionAdminJobResume, Target: name}, err)
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response.AdminStatusResponse{Status: "resumed"})
+}
+
+func writeJobError(w http.ResponseWriter, err error) {
+	if strings.HasPrefix(err.Error(), constants.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/audit"
+)
+
+// sendMessage sends an ad-hoc text message to a group or an employee
+func (h *Handler) sendMessage(w http.ResponseWriter, r *http.Request) {
+	var req request.AdminSendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, constants.ErrFailedToDecodeRequest)
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, constants.ErrEmptyMessage)
+		return
+	}
+
+	switch {
+	case req.GroupID != "":
+		resp, err := h.messenger.SendMessageToGroup(request.SendMessageToBotGroupRequest{
+			GroupID: req.GroupID,
+			Message: request.MessageGroup{
+				Tag: "Text",
+				Text: request.TextGroup{
+					Format:  1,
+					Content: req.Text,
+				},
+				ThreadID: req.ThreadID,
+			},
+		})
+		h.audit.RecordResult(auditContext(r), audit.Entry{
+			Action:    audit.ActionAdminMessage,
+			Target:    "group",
+			GroupID:   req.GroupID,
+			MessageID: resp.MessegeId,
+		}, err)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, response.AdminMessageResponse{Code: resp.Code, MessageID: resp.MessegeId})
+	case req.EmployeeCode != "":
+		resp, err := h.messenger.SendMessageToSubscriber(request.SendMessageToBotSubscriberRequest{
+			EmployeeCode: req.EmployeeCode,
+			Message: request.MessageSingle{
+				Tag: "Text",
+				Text: request.TextSingle{
+					Format:  1,
+					Content: req.Text,
+				},
+			},
+		})
+		h.audit.RecordResult(auditContext(r), audit.Entry{
+			Action:       audit.ActionAdminMessage,
+			Target:       "employee",
+			EmployeeCode: req.EmployeeCode,
+		}, err)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, response.AdminMessageResponse{Code: resp.Code})
+	default:
+		writeError(w, http.StatusBadRequest, constants.ErrMissingRecipient)
+	}
+}
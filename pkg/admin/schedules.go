@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/schedule"
+)
+
+// entryDateFormat is the date format used by schedule entries in the admin API
+const entryDateFormat = "2006-01-02"
+
+// listRotations returns the names of the managed rotations
+func (h *Handler) listRotations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, response.AdminRotationsResponse{Rotations: h.schedules.Names()})
+}
+
+// getSchedule returns the entries of a rotation
+func (h *Handler) getSchedule(w http.ResponseWriter, r *http.Request) {
+	store, err := h.schedules.Get(r.PathValue("rotation"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	schedules, err := store.Read()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, scheduleResponse(store.Name(), schedules))
+}
+
+// updateSchedule replaces the entries of a rotation
+func (h *Handler) updateSchedule(w http.ResponseWriter, r *http.Request) {
+	store, err := h.schedules.Get(r.PathValue("rotation"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var req request.AdminUpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, constants.ErrFailedToDecodeRequest)
+		return
+	}
+
+	schedules := make([]schedule.Schedule, 0, len(req.Entries))
+	for _, entry := range req.Entries {
+		pic := strings.TrimSpace(entry.PIC)
+		date, err := time.Parse(entryDateFormat, strings.TrimSpace(entry.Date))
+		if pic == "" || strings.Contains(pic, ",") || err != nil {
+			writeError(w, http.StatusBadRequest, constants.ErrInvalidScheduleEntry+": "+entry.PIC)
+			return
+		}
+		email := strings.TrimSpace(entry.Email)
+		if email != "" && !schedule.ValidEmail(email) {
+			writeError(w, http.StatusBadRequest, constants.ErrInvalidScheduleEntry+": "+entry.Email)
+			return
+		}
+		schedules = append(schedules, schedule.Schedule{
+			PIC:   pic,
+			Date:  date,
+			Email: email,
+		})
+	}
+
+	if err := store.Replace(auditContext(r), schedules); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	updated, err := store.Read()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, scheduleResponse(store.Name(), updated))
+}
+
+func scheduleResponse(rotation string, schedules []schedule.Schedule) response.AdminScheduleResponse {
+	result := response.AdminScheduleResponse{
+		Rotation: rotation,
+		Entries:  make([]response.AdminScheduleEntry, 0, len(schedules)),
+	}
+	for _, s := range schedules {
+		result.Entries = append(result.Entries, response.AdminScheduleEntry{
+			PIC:   s.PIC,
+			Date:  s.Date.Format(entryDateFormat),
+			Email: s.Email,
+		})
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package answer
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"seatalk-bot/internal/config"
+	"seatalk-bot/internal/constants"
+)
+
+// Roles of the turns of a conversation
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Turn is a message of a conversation with the bot
+type Turn struct {
+	Role string
+	// Name is the name of the user who sent a user turn
+	Name string
+	Text string
+}
+
+// Question is a free-form message the bot was asked to answer
+type Question struct {
+	Text string
+	// Locale is the language the answer should be written in
+	Locale string
+	// History holds the earlier turns of the conversation, oldest first
+	History []Turn
+	// Asker is the name of the user asking
+	Asker string
+}
+
+// Answerer answers free-form questions
+type Answerer interface {
+	Answer(ctx context.Context, q Question) (string, error)
+}
+
+// FromConfig returns the Answerer of the configured backend, or nil when
+// answers are disabled
+func FromConfig(cfg *config.Config) (Answerer, error) {
+	switch cfg.AnswerBackend {
+	case "":
+		return nil, nil
+	case config.AnswerBackendStub:
+		return Stub{}, nil
+	case config.AnswerBackendOpenAI:
+		return NewOpenAI(OpenAIOptions{
+			URL:          cfg.AnswerURL,
+			APIKey:       cfg.AnswerAPIKey,
+			Model:        cfg.AnswerModel,
+			SystemPrompt: cfg.AnswerSystemPrompt,
+			MaxTokens:    cfg.AnswerMaxTokens,
+			Timeout:      cfg.AnswerTimeout,
+		}), nil
+	}
+	return nil, errors.New(constants.ErrUnknownAnswerBackend + ": " + cfg.AnswerBackend)
+}
+
+// Truncate shortens text to at most max characters, marking the cut with an
+// ellipsis. A max of zero or less leaves text unchanged.
+func Truncate(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max-1]) + "…"
+}
@@ -0,0 +1,211 @@
+package answer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello world", 6, "hello…"},
+		{"héllo wörld", 4, "hél…"},
+		{"hello", 0, "hello"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.text, tt.max); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryKeepsLatestTurns(t *testing.T) {
+	h := NewHistory(3, clock.NewFake(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)))
+	h.Add("a", Turn{Role: RoleUser, Text: "1"}, Turn{Role: RoleAssistant, Text: "2"})
+	h.Add("a", Turn{Role: RoleUser, Text: "3"}, Turn{Role: RoleAssistant, Text: "4"})
+	h.Add("b", Turn{Role: RoleUser, Text: "other"})
+
+	var texts []string
+	for _, turn := range h.Turns("a") {
+		texts = append(texts, turn.Text)
+	}
+	if want := []string{"2", "3", "4"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("turns = %q, want %q", texts, want)
+	}
+	if turns := h.Turns("missing"); turns != nil {
+		t.Errorf("turns of an unknown conversation = %v, want none", turns)
+	}
+
+	// The returned turns are a copy
+	h.Turns("b")[0].Text = "changed"
+	if got := h.Turns("b")[0].Text; got != "other" {
+		t.Errorf("turn changed through a copy to %q", got)
+	}
+}
+
+func TestHistoryExpires(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC))
+	h := NewHistory(10, clk)
+	h.Add("a", Turn{Role: RoleUser, Text: "old"})
+
+	clk.Advance(conversationTTL)
+	if got := len(h.Turns("a")); got != 1 {
+		t.Fatalf("got %d turns at the TTL, want 1", got)
+	}
+	clk.Advance(time.Second)
+	if turns := h.Turns("a"); turns != nil {
+		t.Errorf("turns after the TTL = %v, want none", turns)
+	}
+
+	// A conversation resumed after expiring starts over
+	h.Add("a", Turn{Role: RoleUser, Text: "new"})
+	if turns := h.Turns("a"); len(turns) != 1 || turns[0].Text != "new" {
+		t.Errorf("turns = %v, want only the new one", turns)
+	}
+}
+
+func TestHistoryEvictsOldestConversation(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC))
+	h := NewHistory(1, clk)
+	for i := 0; i <= maxConversations; i++ {
+		h.Add(strings.Repeat("k", i+1), Turn{Text: "t"})
+		clk.Advance(time.Second)
+	}
+	if got := len(h.convs); got != maxConversations {
+		t.Errorf("kept %d conversations, want %d", got, maxConversations)
+	}
+	if turns := h.Turns("k"); turns != nil {
+		t.Errorf("oldest conversation kept: %v", turns)
+	}
+	if turns := h.Turns(strings.Repeat("k", maxConversations+1)); len(turns) != 1 {
+		t.Errorf("newest conversation lost")
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	h := NewHistory(0, clock.Real{})
+	h.Add("a", Turn{Text: "t"})
+	if turns := h.Turns("a"); turns != nil {
+		t.Errorf("turns = %v, want none when history is disabled", turns)
+	}
+}
+
+func TestStubIsDeterministic(t *testing.T) {
+	q := Question{Text: " when is regression? ", Locale: "id", History: []Turn{{Role: RoleUser, Text: "hi"}}}
+	first, err := Stub{}.Answer(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Stub{}.Answer(context.Background(), q)
+	if want := `[stub id] "when is regression?" after 1 earlier messages`; first != want || second != want {
+		t.Errorf("answers = %q, %q, want %q", first, second, want)
+	}
+}
+
+func TestOpenAIAnswer(t *testing.T) {
+	var got request.ChatCompletionRequest
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  Every Tuesday.  "}}],"usage":{"total_tokens":42}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAI(OpenAIOptions{URL: server.URL, APIKey: "key", Model: "model", SystemPrompt: "Be brief.", MaxTokens: 50, Timeout: time.Second})
+	answer, err := client.Answer(context.Background(), Question{
+		Text:   "when is regression?",
+		Locale: "id",
+		Asker:  "Jane",
+		History: []Turn{
+			{Role: RoleUser, Name: "Budi", Text: "hello"},
+			{Role: RoleAssistant, Text: "Hi!"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer != "Every Tuesday." {
+		t.Errorf("answer = %q", answer)
+	}
+	if path != "/chat/completions" || auth != "Bearer key" {
+		t.Errorf("request to %s with Authorization %q", path, auth)
+	}
+
+	want := request.ChatCompletionRequest{
+		Model: "model",
+		Messages: []request.ChatMessage{
+			{Role: "system", Content: "Be brief. Reply in Bahasa Indonesia."},
+			{Role: RoleUser, Content: "Budi: hello"},
+			{Role: RoleAssistant, Content: "Hi!"},
+			{Role: RoleUser, Content: "Jane: when is regression?"},
+		},
+		MaxTokens:   50,
+		Temperature: 0.2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"api error", http.StatusTooManyRequests, `{"error":{"message":"rate limited","type":"requests"}}`, constants.ErrAnswerFailed + ": status 429: rate limited"},
+		{"status without body", http.StatusBadGateway, `bad gateway`, constants.ErrAnswerFailed + ": status 502"},
+		{"no choices", http.StatusOK, `{"choices":[]}`, constants.ErrAnswerFailed + ": empty answer"},
+		{"blank answer", http.StatusOK, `{"choices":[{"message":{"content":"  "}}]}`, constants.ErrAnswerFailed + ": empty answer"},
+		{"malformed", http.StatusOK, `{"choices":`, constants.ErrFailedToDecodeResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewOpenAI(OpenAIOptions{URL: server.URL, Model: "model", Timeout: time.Second}).Answer(context.Background(), Question{Text: "q"})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIWithoutKeySendsNoAuthorization(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Values("Authorization")
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewOpenAI(OpenAIOptions{URL: server.URL, Model: "model", Timeout: time.Second}).Answer(context.Background(), Question{Text: "q"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(auth) != 0 {
+		t.Errorf("Authorization = %q, want none", auth)
+	}
+}
@@ -0,0 +1,89 @@
+package answer
+
+import (
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+)
+
+// Bounds of the conversations kept in memory
+const (
+	conversationTTL  = 24 * time.Hour
+	maxConversations = 1000
+)
+
+// conversation is the recent turns of a thread or direct chat
+type conversation struct {
+	turns   []Turn
+	updated time.Time
+}
+
+// History caches the latest turns of each conversation with the bot, keyed
+// by thread. It is kept in memory only; conversations idle for a day are
+// forgotten.
+type History struct {
+	maxTurns int
+	clock    clock.Clock
+	mu       sync.Mutex
+	convs    map[string]*conversation
+}
+
+// NewHistory creates a History keeping up to maxTurns turns per conversation
+func NewHistory(maxTurns int, clk clock.Clock) *History {
+	return &History{maxTurns: maxTurns, clock: clk, convs: make(map[string]*conversation)}
+}
+
+// Add appends turns to a conversation, dropping its oldest turns beyond the limit
+func (h *History) Add(key string, turns ...Turn) {
+	if h.maxTurns <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.clock.Now()
+	conv, ok := h.convs[key]
+	if !ok || now.Sub(conv.updated) > conversationTTL {
+		conv = &conversation{}
+		h.convs[key] = conv
+	}
+	conv.turns = append(conv.turns, turns...)
+	if extra := len(conv.turns) - h.maxTurns; extra > 0 {
+		conv.turns = append([]Turn(nil), conv.turns[extra:]...)
+	}
+	conv.updated = now
+
+	if len(h.convs) > maxConversations {
+		h.evict(now)
+	}
+}
+
+// Turns returns the turns of a conversation, oldest first
+func (h *History) Turns(key string) []Turn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conv, ok := h.convs[key]
+	if !ok || h.clock.Now().Sub(conv.updated) > conversationTTL {
+		return nil
+	}
+	return append([]Turn(nil), conv.turns...)
+}
+
+// evict forgets idle conversations, then the least recently updated one
+// while there are too many; the caller holds the lock
+func (h *History) evict(now time.Time) {
+	var oldest string
+	for key, conv := range h.convs {
+		if now.Sub(conv.updated) > conversationTTL {
+			delete(h.convs, key)
+			continue
+		}
+		if oldest == "" || conv.updated.Before(h.convs[oldest].updated) {
+			oldest = key
+		}
+	}
+	if len(h.convs) > maxConversations && oldest != "" {
+		delete(h.convs, oldest)
+	}
+}
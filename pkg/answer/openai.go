@@ -0,0 +1,114 @@
+package answer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/i18n"
+)
+
+// defaultSystemPrompt instructs the model when no prompt is configured
+const defaultSystemPrompt = "You are a helpful assistant in a SeaTalk group chat of a QA team running regression tests. " +
+	"Answer briefly and plainly, without markdown. If you do not know the answer, say so rather than guessing."
+
+// OpenAIOptions configures an OpenAI client
+type OpenAIOptions struct {
+	// URL is the base URL of the API, the chat completions endpoint being URL/chat/completions
+	URL          string
+	APIKey       string
+	Model        string
+	SystemPrompt string
+	MaxTokens    int
+	Timeout      time.Duration
+}
+
+// OpenAI answers questions through an OpenAI-compatible chat completions endpoint
+type OpenAI struct {
+	opts       OpenAIOptions
+	httpClient *http.Client
+}
+
+// NewOpenAI creates an OpenAI client
+func NewOpenAI(opts OpenAIOptions) *OpenAI {
+	if opts.SystemPrompt == "" {
+		opts.SystemPrompt = defaultSystemPrompt
+	}
+	return &OpenAI{opts: opts, httpClient: &http.Client{Timeout: opts.Timeout}}
+}
+
+// Answer sends the conversation and the question to the endpoint and returns
+// the first choice
+func (o *OpenAI) Answer(ctx context.Context, q Question) (string, error) {
+	body, err := json.Marshal(request.ChatCompletionRequest{
+		Model:       o.opts.Model,
+		Messages:    o.messages(q),
+		MaxTokens:   o.opts.MaxTokens,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", errors.New(constants.ErrFailedToMarshalPayload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.opts.URL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", errors.New(constants.ErrFailedToCreateRequest)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.opts.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.opts.APIKey)
+	}
+
+	resp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		return "", errors.New(constants.ErrFailedToExecuteRequest + ": " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	var completion response.ChatCompletionResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&completion)
+	if resp.StatusCode != http.StatusOK {
+		message := "status " + strconv.Itoa(resp.StatusCode)
+		if decodeErr == nil && completion.Error != nil {
+			message += ": " + completion.Error.Message
+		}
+		return "", errors.New(constants.ErrAnswerFailed + ": " + message)
+	}
+	if decodeErr != nil {
+		return "", errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	if len(completion.Choices) == 0 || strings.TrimSpace(completion.Choices[0].Message.Content) == "" {
+		return "", errors.New(constants.ErrAnswerFailed + ": empty answer")
+	}
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
+
+// messages builds the conversation sent to the model
+func (o *OpenAI) messages(q Question) []request.ChatMessage {
+	prompt := o.opts.SystemPrompt
+	if q.Locale != "" {
+		prompt += " Reply in " + i18n.LanguageName(i18n.DefaultLocale, q.Locale) + "."
+	}
+	messages := []request.ChatMessage{{Role: "system", Content: prompt}}
+	for _, t := range q.History {
+		messages = append(messages, request.ChatMessage{Role: t.Role, Content: turnContent(t)})
+	}
+	return append(messages, request.ChatMessage{Role: RoleUser, Content: turnContent(Turn{Role: RoleUser, Name: q.Asker, Text: q.Text})})
+}
+
+// turnContent prefixes user turns with the name of their sender, since
+// several people take part in group conversations
+func turnContent(t Turn) string {
+	if t.Role == RoleUser && t.Name != "" {
+		return t.Name + ": " + t.Text
+	}
+	return t.Text
+}
@@ -0,0 +1,16 @@
+package answer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Stub is a deterministic Answerer for tests and local runs. It echoes the
+// question with the size of the conversation, without calling any service.
+type Stub struct{}
+
+// Answer returns a reply built only from the question
+func (Stub) Answer(ctx context.Context, q Question) (string, error) {
+	return fmt.Sprintf("[stub %s] %q after %d earlier messages", q.Locale, strings.TrimSpace(q.Text), len(q.History)), nil
+}
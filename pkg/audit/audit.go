@@ -0,0 +1,218 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionScheduleReplace = "schedule.replace"
+	ActionScheduleAdvance = "schedule.advance"
+	ActionScheduleRestore = "schedule.restore"
+	ActionJobRun          = "job.run"
+	ActionMessageSent     = "message.sent"
+	ActionCommand         = "command"
+	ActionCommandDenied   = "command.denied"
+	ActionAdminJobRun     = "admin.job.run"
+	ActionAdminJobPause   = "admin.job.pause"
+	ActionAdminJobResume  = "admin.job.resume"
+	ActionAdminMessage    = "admin.message"
+)
+
+// Outcomes of recorded actions
+const (
+	OutcomeOK     = "ok"
+	OutcomeError  = "error"
+	OutcomeDenied = "denied"
+)
+
+// Actors that are not employees
+const (
+	ActorBot       = "bot"
+	ActorScheduler = "scheduler"
+	ActorAdminAPI  = "admin-api"
+	ActorCLI       = "cli"
+)
+
+// Employee returns the actor name of an employee
+func Employee(employeeCode string) string {
+	return "employee:" + employeeCode
+}
+
+// EmployeeOf returns the employee code of an employee actor
+func EmployeeOf(actor string) (string, bool) {
+	return strings.CutPrefix(actor, "employee:")
+}
+
+// Entry is one record of the audit log
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	// Target is what the action applied to, such as a rotation or a job
+	Target       string            `json:"target,omitempty"`
+	GroupID      string            `json:"group_id,omitempty"`
+	EmployeeCode string            `json:"employee_code,omitempty"`
+	MessageID    string            `json:"message_id,omitempty"`
+	Reason       string            `json:"reason,omitempty"`
+	Details      map[string]string `json:"details,omitempty"`
+	Outcome      string            `json:"outcome"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// Filter selects audit entries. Empty fields match every entry.
+type Filter struct {
+	Action  string
+	Actor   string
+	Target  string
+	GroupID string
+	Since   time.Time
+	Until   time.Time
+	// Limit keeps only the most recent entries when positive
+	Limit int
+}
+
+// match reports whether the entry is selected by the filter. Action matches
+// itself and the actions it prefixes, e.g. "schedule" matches "schedule.advance".
+func (f Filter) match(e Entry) bool {
+	switch {
+	case f.Action != "" && e.Action != f.Action && !strings.HasPrefix(e.Action, f.Action+"."):
+		return false
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Target != "" && e.Target != f.Target:
+		return false
+	case f.GroupID != "" && e.GroupID != f.GroupID:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Log is an append-only audit log stored as JSON lines. A nil Log records nothing.
+type Log struct {
+	path  string
+	clock clock.Clock
+	mu    sync.Mutex
+}
+
+// Open returns the audit log stored at path, created on the first record
+func Open(path string, clk clock.Clock) *Log {
+	if clk == nil {
+		clk = clock.Real{}
+	}
+	return &Log{path: path, clock: clk}
+}
+
+// Record appends an entry, filling in the time, and the actor and reason
+// carried by ctx when the entry has none. Failures are logged and returned.
+func (l *Log) Record(ctx context.Context, e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = l.clock.Now()
+	}
+	if e.Actor == "" {
+		e.Actor = ActorFrom(ctx)
+	}
+	if e.Reason == "" {
+		e.Reason = ReasonFrom(ctx)
+	}
+	if e.Outcome == "" {
+		e.Outcome = OutcomeOK
+	}
+
+	err := l.append(e)
+	if err != nil {
+		slog.Error("failed to write audit entry", "action", e.Action, "target", e.Target, "error", err)
+	}
+	return err
+}
+
+// RecordResult appends an entry whose outcome follows err
+func (l *Log) RecordResult(ctx context.Context, e Entry, err error) {
+	if err != nil {
+		e.Outcome, e.Error = OutcomeError, err.Error()
+	}
+	l.Record(ctx, e)
+}
+
+// append writes one JSON line to the log file
+func (l *Log) append(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return errors.New(constants.ErrFailedToMarshalPayload + ": " + err.Error())
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.New(constants.ErrorFileOpen + ": " + err.Error())
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
+	}
+	return file.Close()
+}
+
+// Query returns the entries selected by the filter, oldest first
+func (l *Log) Query(f Filter) ([]Entry, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New(constants.ErrorFileOpen + ": " + err.Error())
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, errors.New(constants.ErrFailedToDecodeFile + ": " + l.path + ": " + err.Error())
+		}
+		if f.match(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(constants.ErrorFileRead + ": " + err.Error())
+	}
+
+	if f.Limit > 0 && len(entries) > f.Limit {
+		entries = entries[len(entries)-f.Limit:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+)
+
+var start = time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+
+func TestRecordDefaults(t *testing.T) {
+	clk := clock.NewFake(start)
+	log := Open(filepath.Join(t.TempDir(), "audit", "audit.jsonl"), clk)
+
+	ctx := WithReason(WithActor(context.Background(), Employee("E001")), "covering for Alice")
+	if err := log.Record(ctx, Entry{Action: ActionScheduleAdvance, Target: "stock"}); err != nil {
+		t.Fatal(err)
+	}
+	log.Record(ctx, Entry{Action: ActionJobRun, Actor: ActorScheduler, Reason: "cron"})
+	log.Record(context.Background(), Entry{Action: ActionMessageSent})
+	log.RecordResult(context.Background(), Entry{Action: ActionJobRun}, errors.New("boom"))
+
+	entries, err := log.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Time: start, Actor: "employee:E001", Action: ActionScheduleAdvance, Target: "stock", Reason: "covering for Alice", Outcome: OutcomeOK},
+		{Time: start, Actor: ActorScheduler, Action: ActionJobRun, Reason: "cron", Outcome: OutcomeOK},
+		{Time: start, Actor: ActorBot, Action: ActionMessageSent, Outcome: OutcomeOK},
+		{Time: start, Actor: ActorBot, Action: ActionJobRun, Outcome: OutcomeError, Error: "boom"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		got := entries[i]
+		if !got.Time.Equal(want[i].Time) || got.Actor != want[i].Actor || got.Action != want[i].Action ||
+			got.Target != want[i].Target || got.Reason != want[i].Reason || got.Outcome != want[i].Outcome || got.Error != want[i].Error {
+			t.Errorf("entry %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	clk := clock.NewFake(start)
+	log := Open(filepath.Join(t.TempDir(), "audit.jsonl"), clk)
+	for _, e := range []Entry{
+		{Action: ActionScheduleReplace, Actor: ActorAdminAPI, Target: "stock"},
+		{Action: ActionScheduleAdvance, Actor: ActorScheduler, Target: "stock", GroupID: "g1"},
+		{Action: ActionMessageSent, GroupID: "g1"},
+		{Action: ActionCommandDenied, Actor: Employee("E001"), GroupID: "g2"},
+		{Action: ActionCommand, Actor: Employee("E001"), GroupID: "g2"},
+	} {
+		if err := log.Record(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+		clk.Advance(time.Hour)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"everything", Filter{}, []string{ActionScheduleReplace, ActionScheduleAdvance, ActionMessageSent, ActionCommandDenied, ActionCommand}},
+		{"action prefix", Filter{Action: "schedule"}, []string{ActionScheduleReplace, ActionScheduleAdvance}},
+		{"exact action", Filter{Action: ActionCommand}, []string{ActionCommandDenied, ActionCommand}},
+		{"prefix must end at a dot", Filter{Action: "sched"}, nil},
+		{"actor", Filter{Actor: Employee("E001")}, []string{ActionCommandDenied, ActionCommand}},
+		{"target", Filter{Target: "stock"}, []string{ActionScheduleReplace, ActionScheduleAdvance}},
+		{"group", Filter{GroupID: "g1"}, []string{ActionScheduleAdvance, ActionMessageSent}},
+		{"since is inclusive", Filter{Since: start.Add(3 * time.Hour)}, []string{ActionCommandDenied, ActionCommand}},
+		{"until is exclusive", Filter{Until: start.Add(2 * time.Hour)}, []string{ActionScheduleReplace, ActionScheduleAdvance}},
+		{"limit keeps the most recent", Filter{Limit: 2}, []string{ActionCommandDenied, ActionCommand}},
+		{"combined", Filter{GroupID: "g2", Action: ActionCommand, Limit: 1}, []string{ActionCommand}},
+	}
+	for _, tt := range tests {
+		entries, err := log.Query(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Action)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	if err := log.Record(context.Background(), Entry{Action: ActionJobRun}); err != nil {
+		t.Errorf("Record() = %v", err)
+	}
+	log.RecordResult(context.Background(), Entry{Action: ActionJobRun}, errors.New("boom"))
+	if entries, err := log.Query(Filter{}); entries != nil || err != nil {
+		t.Errorf("Query() = %v, %v", entries, err)
+	}
+}
+
+func TestQueryBeforeFirstRecord(t *testing.T) {
+	log := Open(filepath.Join(t.TempDir(), "audit.jsonl"), nil)
+	if entries, err := log.Query(Filter{}); entries != nil || err != nil {
+		t.Errorf("Query() = %v, %v, want nothing", entries, err)
+	}
+}
@@ -0,0 +1,34 @@
+package audit
+
+import "context"
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	reasonKey
+)
+
+// WithActor returns a context attributing the actions taken with it to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom returns the actor carried by the context, or ActorBot
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorBot
+}
+
+// WithReason returns a context explaining the actions taken with it
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey, reason)
+}
+
+// ReasonFrom returns the reason carried by the context
+func ReasonFrom(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey).(string)
+	return reason
+}
@@ -0,0 +1,125 @@
+package calendar
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Paths of the feeds
+const (
+	RotationPath = "/calendar/"
+	PersonPath   = "/calendar/person/"
+	feedExt      = ".ics"
+)
+
+// Handler serves the iCalendar feeds of the rotations and of each person
+type Handler struct {
+	schedules *schedule.Registry
+	token     string
+	clock     clock.Clock
+	mux       *http.ServeMux
+}
+
+// NewHandler creates the calendar feeds. When token is set, requests must
+// carry it in the token query parameter.
+func NewHandler(schedules *schedule.Registry, token string, clk clock.Clock) *Handler {
+	h := &Handler{schedules: schedules, token: token, clock: clk, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET "+RotationPath+"{feed}", h.rotationFeed)
+	h.mux.HandleFunc("GET "+PersonPath+"{feed}", h.personFeed)
+	return h
+}
+
+// RotationURL returns the address of the feed of a rotation
+func RotationURL(baseURL, rotation, token string) string {
+	return feedURL(baseURL+RotationPath+url.PathEscape(rotation)+feedExt, token)
+}
+
+// PersonURL returns the address of the feed of the turns of a person
+func PersonURL(baseURL, email, token string) string {
+	return feedURL(baseURL+PersonPath+url.PathEscape(strings.ToLower(email))+feedExt, token)
+}
+
+func feedURL(address, token string) string {
+	if token == "" {
+		return address
+	}
+	return address + "?token=" + url.QueryEscape(token)
+}
+
+// ServeHTTP checks the token of the request and routes it to the feed
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.token)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// rotationFeed serves every turn of a rotation
+func (h *Handler) rotationFeed(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("feed"), feedExt)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	store, err := h.schedules.Get(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	schedules, err := store.Read()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	events := make([]Event, 0, len(schedules))
+	for _, entry := range schedules {
+		events = append(events, Event{Rotation: name, Entry: entry})
+	}
+	h.write(w, "PIC "+name, events)
+}
+
+// personFeed serves the turns of one person across every rotation
+func (h *Handler) personFeed(w http.ResponseWriter, r *http.Request) {
+	email, ok := strings.CutSuffix(r.PathValue("feed"), feedExt)
+	if !ok || email == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var events []Event
+	for _, name := range h.schedules.Names() {
+		store, err := h.schedules.Get(name)
+		if err != nil {
+			continue
+		}
+		schedules, err := store.Read()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, entry := range schedules {
+			if strings.EqualFold(entry.Email, email) {
+				events = append(events, Event{Rotation: name, Entry: entry})
+			}
+		}
+	}
+	h.write(w, "PIC turns of "+email, events)
+}
+
+// write sends the events as an iCalendar feed
+func (h *Handler) write(w http.ResponseWriter, name string, events []Event) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := Write(w, name, events, h.clock.Now()); err != nil {
+		// The headers are out, so the client only sees a truncated feed
+		slog.Warn("failed to write calendar feed", "feed", name, "error", err)
+	}
+}
@@ -0,0 +1,129 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/pkg/schedule"
+)
+
+// newHandler serves two rotations that Alice takes turns in
+func newHandler(t *testing.T, token string) *Handler {
+	t.Helper()
+	dir := t.TempDir()
+	rotations := map[string]string{
+		"stock":   "Alice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026,bob@example.com\n",
+		"release": "Carol,21-Oct-2026,carol@example.com\nAlice,28-Oct-2026,ALICE@example.com\n",
+	}
+	var stores []*schedule.Store
+	for _, name := range []string{"stock", "release"} {
+		filename := filepath.Join(dir, name+".csv")
+		if err := os.WriteFile(filename, []byte(rotations[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stores = append(stores, schedule.NewStore(name, filename, clock.Real{}))
+	}
+	return NewHandler(schedule.NewRegistry(stores...), token, clock.NewFake(now))
+}
+
+// get serves a request to the handler
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+// uids returns the UIDs of the events of a feed
+func uids(feed string) []string {
+	var found []string
+	for _, line := range strings.Split(feed, "\r\n") {
+		if uid, ok := strings.CutPrefix(line, "UID:"); ok {
+			found = append(found, uid)
+		}
+	}
+	return found
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		target string
+		status int
+	}{
+		{"no token configured", "", "/calendar/stock.ics", http.StatusOK},
+		{"missing token", "secret", "/calendar/stock.ics", http.StatusUnauthorized},
+		{"wrong token", "secret", "/calendar/stock.ics?token=guess", http.StatusUnauthorized},
+		{"token prefix", "secret", "/calendar/stock.ics?token=sec", http.StatusUnauthorized},
+		{"right token", "secret", "/calendar/stock.ics?token=secret", http.StatusOK},
+		{"right token on a person feed", "secret", "/calendar/person/alice@example.com.ics?token=secret", http.StatusOK},
+		{"unknown rotation", "secret", "/calendar/missing.ics?token=secret", http.StatusNotFound},
+		{"not a feed", "secret", "/calendar/stock?token=secret", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := get(newHandler(t, tt.token), tt.target)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && w.Header().Get("Content-Type") != "text/calendar; charset=utf-8" {
+			t.Errorf("%s: Content-Type = %q", tt.name, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestFeedURLs(t *testing.T) {
+	if got := RotationURL("https://bot.example.com", "stock room", "a&b"); got != "https://bot.example.com/calendar/stock%20room.ics?token=a%26b" {
+		t.Errorf("RotationURL() = %q", got)
+	}
+	if got := PersonURL("https://bot.example.com", "Alice@Example.com", ""); got != "https://bot.example.com/calendar/person/alice@example.com.ics" {
+		t.Errorf("PersonURL() = %q", got)
+	}
+}
+
+func TestPersonFeedMatchesEmail(t *testing.T) {
+	h := newHandler(t, "")
+	tests := []struct {
+		target string
+		// want are the summaries of the events in the feed
+		want []string
+	}{
+		// Turns come rotation by rotation, in the order of their names
+		{"/calendar/person/alice@example.com.ics", []string{"SUMMARY:PIC release: Alice", "SUMMARY:PIC stock: Alice"}},
+		{"/calendar/person/Alice@Example.COM.ics", []string{"SUMMARY:PIC release: Alice", "SUMMARY:PIC stock: Alice"}},
+		{"/calendar/person/carol@example.com.ics", []string{"SUMMARY:PIC release: Carol"}},
+		{"/calendar/person/alice@example.org.ics", nil},
+		{"/calendar/person/alice.ics", nil},
+	}
+	for _, tt := range tests {
+		w := get(h, tt.target)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", tt.target, w.Code)
+		}
+		var got []string
+		for _, line := range strings.Split(w.Body.String(), "\r\n") {
+			if strings.HasPrefix(line, "SUMMARY:") {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: events %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestUIDsMatchAcrossFeeds(t *testing.T) {
+	h := newHandler(t, "")
+	rotation := uids(get(h, "/calendar/stock.ics").Body.String())
+	person := uids(get(h, "/calendar/person/alice@example.com.ics").Body.String())
+	if len(rotation) != 2 || len(person) != 2 || person[1] != rotation[0] {
+		t.Errorf("rotation UIDs %q, person UIDs %q, want Alice's turn to share its UID", rotation, person)
+	}
+	if again := uids(get(h, "/calendar/stock.ics").Body.String()); strings.Join(again, ",") != strings.Join(rotation, ",") {
+		t.Errorf("UIDs changed between requests: %q, then %q", rotation, again)
+	}
+}
@@ -0,0 +1,100 @@
+package calendar
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+
+	"seatalk-bot/pkg/schedule"
+)
+
+// productID identifies the bot in the feeds it generates
+const productID = "-//seatalk-bot//PIC rotations//EN"
+
+// maxLineOctets is the longest content line allowed by RFC 5545
+const maxLineOctets = 75
+
+// Event is a PIC turn of a rotation
+type Event struct {
+	Rotation string
+	Entry    schedule.Schedule
+}
+
+// UID returns an identifier of the event that stays the same across feeds
+func (e Event) UID() string {
+	sum := sha1.Sum([]byte(e.Rotation + "|" + e.Entry.Date.Format("20060102") + "|" + strings.ToLower(e.Entry.Email) + "|" + e.Entry.PIC))
+	return hex.EncodeToString(sum[:12]) + "@seatalk-bot"
+}
+
+// Write writes the events as an iCalendar feed named name. Every event is an
+// all-day event on the date of the turn.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(name))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.UID())
+		lw.line("DTSTAMP:" + stamp)
+		lw.line("DTSTART;VALUE=DATE:" + e.Entry.Date.Format("20060102"))
+		lw.line("DTEND;VALUE=DATE:" + e.Entry.Date.AddDate(0, 0, 1).Format("20060102"))
+		lw.line("SUMMARY:" + escape("PIC "+e.Rotation+": "+e.Entry.PIC))
+		lw.line("DESCRIPTION:" + escape(e.Entry.PIC+" is PIC for the "+e.Rotation+" rotation."))
+		if e.Entry.Email != "" {
+			lw.line("ATTENDEE;CN=" + paramValue(e.Entry.PIC) + ":mailto:" + e.Entry.Email)
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// escape escapes a text value, turning any line break into a newline
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`).Replace(value)
+}
+
+// paramValue quotes a parameter value when it holds separators
+func paramValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, "'")
+	if strings.ContainsAny(value, ";:,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// lineWriter writes folded CRLF-terminated content lines, keeping the first error
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line, folding it at maxLineOctets without splitting
+// UTF-8 sequences
+func (lw *lineWriter) line(content string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	_, lw.err = io.WriteString(lw.w, b.String())
+}
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"seatalk-bot/pkg/schedule"
+)
+
+var now = time.Date(2026, time.October, 19, 9, 30, 0, 0, time.UTC)
+
+// turn returns the event of a PIC turn on the given day of October 2026
+func turn(rotation, pic, email string, day int) Event {
+	return Event{Rotation: rotation, Entry: schedule.Schedule{
+		PIC:   pic,
+		Date:  time.Date(2026, time.October, day, 0, 0, 0, 0, time.UTC),
+		Email: email,
+	}}
+}
+
+func TestWriteGolden(t *testing.T) {
+	events := []Event{
+		turn("stock", "Zoë", "zoe@example.com", 20),
+		turn("stock", "Bob", "", 31),
+	}
+	var b strings.Builder
+	if err := Write(&b, "PIC stock", events, now.In(time.FixedZone("WIB", 7*60*60))); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//seatalk-bot//PIC rotations//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:PIC stock",
+		"BEGIN:VEVENT",
+		"UID:" + events[0].UID(),
+		"DTSTAMP:20261019T093000Z",
+		"DTSTART;VALUE=DATE:20261020",
+		"DTEND;VALUE=DATE:20261021",
+		"SUMMARY:PIC stock: Zoë",
+		"DESCRIPTION:Zoë is PIC for the stock rotation.",
+		"ATTENDEE;CN=Zoë:mailto:zoe@example.com",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:" + events[1].UID(),
+		"DTSTAMP:20261019T093000Z",
+		// An all-day event ends the next day, across the end of the month
+		"DTSTART;VALUE=DATE:20261031",
+		"DTEND;VALUE=DATE:20261101",
+		"SUMMARY:PIC stock: Bob",
+		"DESCRIPTION:Bob is PIC for the stock rotation.",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"short line", "SUMMARY:PIC", []string{"SUMMARY:PIC"}},
+		{"exactly 75 octets", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"ascii", strings.Repeat("a", 80), []string{strings.Repeat("a", 75), " aaaaa"}},
+		{
+			// é is two octets, so the first line stops at 74 octets rather than splitting one
+			"two-octet runes", "SUMMARY:" + strings.Repeat("é", 40),
+			[]string{"SUMMARY:" + strings.Repeat("é", 33), " " + strings.Repeat("é", 7)},
+		},
+		{
+			"four-octet runes", strings.Repeat("😀", 40),
+			[]string{strings.Repeat("😀", 18), " " + strings.Repeat("😀", 18), " " + strings.Repeat("😀", 4)},
+		},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		lw := &lineWriter{w: &b}
+		lw.line(tt.content)
+		if want := strings.Join(tt.want, "\r\n") + "\r\n"; b.String() != want {
+			t.Errorf("%s: folded to %q, want %q", tt.name, b.String(), want)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+			if len(line) > maxLineOctets || !utf8.ValidString(line) {
+				t.Errorf("%s: line %q is %d octets", tt.name, line, len(line))
+			}
+		}
+		if unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", ""); unfolded != tt.content {
+			t.Errorf("%s: unfolds to %q", tt.name, unfolded)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"PIC stock", "PIC stock"},
+		{"a,b;c", `a\,b\;c`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+		{"windows\r\nlines", `windows\nlines`},
+		{"old mac\rlines", `old mac\nlines`},
+		{"blank\r\n\r\nline", `blank\n\nline`},
+		{`\n`, `\\n`},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.value); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParamValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Zoë", "Zoë"},
+		{"Doe, Jane", `"Doe, Jane"`},
+		{`Jane "JD" Doe`, "Jane 'JD' Doe"},
+	}
+	for _, tt := range tests {
+		if got := paramValue(tt.value); got != tt.want {
+			t.Errorf("paramValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestUIDIsStable(t *testing.T) {
+	base := turn("stock", "Alice", "alice@example.com", 20)
+	if got := base.UID(); got != turn("stock", "Alice", "Alice@Example.com", 20).UID() {
+		t.Errorf("UID changes with the case of the email")
+	}
+	if !strings.HasSuffix(base.UID(), "@seatalk-bot") || len(base.UID()) != 24+len("@seatalk-bot") {
+		t.Errorf("UID() = %q", base.UID())
+	}
+	for name, other := range map[string]Event{
+		"rotation": turn("release", "Alice", "alice@example.com", 20),
+		"date":     turn("stock", "Alice", "alice@example.com", 27),
+		"email":    turn("stock", "Alice", "alice@corp.example.com", 20),
+		"PIC":      turn("stock", "Alicia", "alice@example.com", 20),
+	} {
+		if other.UID() == base.UID() {
+			t.Errorf("UID does not change with the %s", name)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/roles"
+)
+
+// Context describes the message a command was invoked from
+type Context struct {
+	// GroupID is empty when the command was sent in a direct message
+	GroupID      string
+	EmployeeCode string
+	SeatalkID    string
+	MessageID    string
+	ThreadID     string
+	Locale       string
+	Name         string
+	Args         []string
+	// Text is the message text after the command name, keeping its line breaks
+	Text string
+	// Mentions holds the users mentioned after the command name
+	Mentions []request.MentionedUser
+	// Role is the role of the sender where the command was sent
+	Role roles.Role
+}
+
+// InGroup reports whether the command was sent in a group chat
+func (c *Context) InGroup() bool {
+	return c.GroupID != ""
+}
+
+// Handler executes a command and returns the reply text
+type Handler func(ctx context.Context, c *Context) (string, error)
+
+// Command is a bot command invoked as /<name> [args...]
+type Command struct {
+	Name  string
+	Usage string
+	Help  func(locale string) string
+	// Role is the least role allowed to run the command
+	Role    roles.Role
+	Handler Handler
+}
+
+// PermissionError reports a command refused because the sender lacks a role
+type PermissionError struct {
+	Command  string
+	Required roles.Role
+}
+
+func (e *PermissionError) Error() string {
+	return constants.ErrPermissionDenied + ": /" + e.Command + " requires the " + e.Required.String() + " role"
+}
+
+// Require returns a PermissionError unless the sender holds at least role
+func Require(c *Context, role roles.Role) error {
+	if c.Role.AtLeast(role) {
+		return nil
+	}
+	return &PermissionError{Command: c.Name, Required: role}
+}
+
+// Registry holds the commands understood by the bot
+type Registry struct {
+	commands map[string]Command
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]Command)}
+}
+
+// Register adds a command, replacing any command with the same name
+func (r *Registry) Register(cmd Command) {
+	r.commands[strings.ToLower(cmd.Name)] = cmd
+}
+
+// Lookup returns the command with the given name
+func (r *Registry) Lookup(name string) (Command, bool) {
+	cmd, ok := r.commands[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Commands returns the registered commands sorted by name
+func (r *Registry) Commands() []Command {
+	commands := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// Dispatch runs the command named in c
+func (r *Registry) Dispatch(ctx context.Context, c *Context) (string, error) {
+	cmd, ok := r.Lookup(c.Name)
+	if !ok {
+		return "", errors.New(constants.ErrUnknownCommand + ": " + c.Name)
+	}
+	if err := Require(c, cmd.Role); err != nil {
+		return "", err
+	}
+	return cmd.Handler(ctx, c)
+}
+
+// Parse extracts a command from message text. Leading mentions such as
+// "@Bot" are skipped, so "@Bot /lang id" yields ("lang", ["id"], true).
+func Parse(text string) (string, []string, bool) {
+	fields := strings.Fields(text)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") || len(fields[0]) == 1 {
+		return "", nil, false
+	}
+	return strings.ToLower(strings.TrimPrefix(fields[0], "/")), fields[1:], true
+}
+
+// Remainder returns the text following the command name in a message,
+// trimmed but otherwise unchanged
+func Remainder(text string) string {
+	start := strings.Index(text, "/")
+	if start < 0 {
+		return ""
+	}
+	rest := text[start:]
+	end := strings.IndexFunc(rest, unicode.IsSpace)
+	if end < 0 {
+		return ""
+	}
+	return strings.TrimSpace(rest[end:])
+}
+
+// ArgMentions returns the mentioned users appearing after the command name in
+// text, leaving out the mention of the bot that precedes the command
+func ArgMentions(text string, mentioned []request.MentionedUser) []request.MentionedUser {
+	start := strings.Index(text, "/")
+	if start < 0 {
+		return nil
+	}
+	var mentions []request.MentionedUser
+	for _, user := range mentioned {
+		if user.Username != "" && strings.Contains(text[start:], "@"+user.Username) {
+			mentions = append(mentions, user)
+		}
+	}
+	return mentions
+}
+
+// StripMentions removes "@name" mentions of the given users from text
+func StripMentions(text string, names []string) string {
+	for _, name := range names {
+		if name != "" {
+			text = strings.ReplaceAll(text, "@"+name, "")
+		}
+	}
+	return text
+}
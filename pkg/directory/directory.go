@@ -0,0 +1,331 @@
+package directory
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/mention"
+)
+
+// Entry describes an employee known to the bot
+type Entry struct {
+	EmployeeCode string `json:"employee_code,omitempty"`
+	SeatalkID    string `json:"seatalk_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Department   string `json:"department,omitempty"`
+	// UpdatedAt is when the profile was last fetched from SeaTalk
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// batchSize is the number of employees requested from SeaTalk at once
+const batchSize = 100
+
+// Mention renders a mention tag for the employee
+func (e Entry) Mention() string {
+	return mention.For(mention.Target{SeatalkID: e.SeatalkID, EmployeeCode: e.EmployeeCode, Email: e.Email})
+}
+
+// DisplayName returns the name of the employee, falling back to the email or
+// employee code when the name is unknown
+func (e Entry) DisplayName() string {
+	for _, value := range []string{e.Name, e.Email, e.EmployeeCode} {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Options configures where a Directory fetches profiles from
+type Options struct {
+	// Source fetches employee profiles. Without a source the directory only
+	// knows what it learned from callbacks and the persisted file.
+	Source Source
+	// TTL is how long fetched profiles are trusted before being fetched again
+	TTL   time.Duration
+	Clock clock.Clock
+}
+
+// Directory maps the employee codes, SeaTalk IDs and emails seen in callbacks
+// to employees, fetching their profiles from SeaTalk. Entries are persisted
+// as JSON and may be edited by hand to add names and emails.
+type Directory struct {
+	path    string
+	opts    Options
+	mu      sync.RWMutex
+	entries []*Entry
+}
+
+// Load loads the directory persisted at path
+func Load(path string, opts Options) (*Directory, error) {
+	if opts.Clock == nil {
+		opts.Clock = clock.Real{}
+	}
+	var entries []*Entry
+	if err := filestore.LoadJSON(path, &entries); err != nil {
+		return nil, err
+	}
+	return &Directory{path: path, opts: opts, entries: entries}, nil
+}
+
+// HasSource reports whether the directory can fetch profiles from SeaTalk
+func (d *Directory) HasSource() bool {
+	return d.opts.Source != nil
+}
+
+// Lookup returns the employee with the given employee code, fetching the
+// profile when it is unknown or older than the TTL. A stale entry is returned
+// when SeaTalk cannot be reached.
+func (d *Directory) Lookup(code string) (Entry, error) {
+	if code == "" {
+		return Entry{}, errors.New(constants.ErrEmployeeNotFound)
+	}
+
+	entry, found := d.ByEmployeeCode(code)
+	if found && d.fresh(entry) {
+		return entry, nil
+	}
+	if d.opts.Source != nil {
+		err := d.refresh([]string{code})
+		if updated, ok := d.ByEmployeeCode(code); ok && d.fresh(updated) {
+			return updated, nil
+		}
+		if err != nil && !found {
+			return Entry{}, err
+		}
+		if err != nil {
+			slog.Warn("using a stale employee profile", "employee_code", code, "error", err)
+		}
+	}
+	if found {
+		return entry, nil
+	}
+	return Entry{}, errors.New(constants.ErrEmployeeNotFound + ": " + code)
+}
+
+// LookupEmail returns the employee with the given email, resolving the email
+// to an employee code through SeaTalk when needed
+func (d *Directory) LookupEmail(email string) (Entry, error) {
+	entry, found := d.ByEmail(email)
+	if found && (d.fresh(entry) || d.opts.Source == nil) {
+		return entry, nil
+	}
+	if found && entry.EmployeeCode != "" {
+		return d.Lookup(entry.EmployeeCode)
+	}
+	if d.opts.Source == nil {
+		return Entry{}, errors.New(constants.ErrEmployeeNotFound + ": " + email)
+	}
+
+	codes, err := d.opts.Source.EmployeeCodes([]string{email})
+	if err != nil {
+		return Entry{}, err
+	}
+	code, ok := codes[strings.ToLower(email)]
+	if !ok {
+		return Entry{}, errors.New(constants.ErrEmployeeNotFound + ": " + email)
+	}
+	return d.Lookup(code)
+}
+
+// Sync fetches the profiles of the given emails that are not known yet and
+// refreshes every profile older than the TTL
+func (d *Directory) Sync(emails []string) error {
+	if d.opts.Source == nil {
+		return errors.New(constants.ErrNoDirectorySource)
+	}
+
+	var unknown []string
+	for _, email := range emails {
+		if _, found := d.ByEmail(email); !found && email != "" {
+			unknown = append(unknown, email)
+		}
+	}
+	var codes []string
+	for start := 0; start < len(unknown); start += batchSize {
+		resolved, err := d.opts.Source.EmployeeCodes(unknown[start:min(start+batchSize, len(unknown))])
+		if err != nil {
+			return err
+		}
+		for _, code := range resolved {
+			codes = append(codes, code)
+		}
+	}
+
+	for _, e := range d.Entries() {
+		if e.EmployeeCode != "" && !d.fresh(e) {
+			codes = append(codes, e.EmployeeCode)
+		}
+	}
+	return d.refresh(codes)
+}
+
+// refresh fetches the profiles of the given employee codes in batches
+func (d *Directory) refresh(codes []string) error {
+	for start := 0; start < len(codes); start += batchSize {
+		profiles, err := d.opts.Source.Profiles(codes[start:min(start+batchSize, len(codes))])
+		if err != nil {
+			return err
+		}
+		if err := d.store(profiles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// store replaces the stored profiles with freshly fetched ones
+func (d *Directory) store(profiles []Entry) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.opts.Clock.Now()
+	for _, profile := range profiles {
+		profile.UpdatedAt = now
+		existing := d.lookup(profile)
+		if existing == nil {
+			copied := profile
+			d.entries = append(d.entries, &copied)
+			continue
+		}
+		// Keep the name chosen by hand when SeaTalk has none
+		if profile.Name == "" {
+			profile.Name = existing.Name
+		}
+		*existing = profile
+	}
+	return filestore.SaveJSON(d.path, d.entries)
+}
+
+// fresh reports whether the entry was fetched within the TTL
+func (d *Directory) fresh(e Entry) bool {
+	return !e.UpdatedAt.IsZero() && d.opts.Clock.Now().Sub(e.UpdatedAt) < d.opts.TTL
+}
+
+// ByEmployeeCode returns the employee with the given employee code
+func (d *Directory) ByEmployeeCode(code string) (Entry, bool) {
+	return d.find(func(e *Entry) bool { return code != "" && e.EmployeeCode == code })
+}
+
+// BySeatalkID returns the employee with the given SeaTalk ID
+func (d *Directory) BySeatalkID(id string) (Entry, bool) {
+	return d.find(func(e *Entry) bool { return id != "" && e.SeatalkID == id })
+}
+
+// ByEmail returns the employee with the given email, ignoring case
+func (d *Directory) ByEmail(email string) (Entry, bool) {
+	return d.find(func(e *Entry) bool { return email != "" && strings.EqualFold(e.Email, email) })
+}
+
+// Resolve returns what is known about the user, merging the given identifiers
+// with the matching directory entry
+func (d *Directory) Resolve(user request.EventUser) Entry {
+	entry, err := d.Lookup(user.EmployeeCode)
+	if err != nil {
+		entry, _ = d.BySeatalkID(user.SeatalkID)
+	}
+	merge(&entry, Entry{EmployeeCode: user.EmployeeCode, SeatalkID: user.SeatalkID})
+	return entry
+}
+
+// Entries returns every employee, sorted by name
+func (d *Directory) Entries() []Entry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(d.entries))
+	for _, e := range d.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DisplayName() < entries[j].DisplayName()
+	})
+	return entries
+}
+
+// Learn records the identifiers and name of an employee seen in a callback,
+// filling in what the directory did not know yet
+func (d *Directory) Learn(e Entry) error {
+	if e.EmployeeCode == "" && e.SeatalkID == "" {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing := d.lookup(e)
+	if existing == nil {
+		copied := e
+		d.entries = append(d.entries, &copied)
+		return filestore.SaveJSON(d.path, d.entries)
+	}
+	if !merge(existing, e) {
+		return nil
+	}
+	return filestore.SaveJSON(d.path, d.entries)
+}
+
+// LearnMessage records the sender and the mentioned users of a message
+func (d *Directory) LearnMessage(msg request.EventMessage) error {
+	if err := d.Learn(Entry{EmployeeCode: msg.Sender.EmployeeCode, SeatalkID: msg.Sender.SeatalkID}); err != nil {
+		return err
+	}
+	for _, mentioned := range msg.Text.MentionedList {
+		if err := d.Learn(Entry{SeatalkID: mentioned.SeatalkID, Name: mentioned.Username}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns a copy of the first entry matching fn
+func (d *Directory) find(fn func(*Entry) bool) (Entry, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, e := range d.entries {
+		if fn(e) {
+			return *e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// lookup returns the stored entry sharing an identifier with e
+func (d *Directory) lookup(e Entry) *Entry {
+	for _, existing := range d.entries {
+		if (e.EmployeeCode != "" && existing.EmployeeCode == e.EmployeeCode) ||
+			(e.SeatalkID != "" && existing.SeatalkID == e.SeatalkID) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// merge fills the empty fields of dst from src and reports whether dst changed
+func merge(dst *Entry, src Entry) bool {
+	changed := false
+	fill := func(field *string, value string) {
+		if *field == "" && value != "" {
+			*field = value
+			changed = true
+		}
+	}
+	fill(&dst.EmployeeCode, src.EmployeeCode)
+	fill(&dst.SeatalkID, src.SeatalkID)
+	fill(&dst.Name, src.Name)
+	fill(&dst.Email, src.Email)
+	return changed
+}
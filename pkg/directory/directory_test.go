@@ -0,0 +1,246 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/models/request"
+)
+
+// fakeSource serves profiles from memory and records the batches requested
+type fakeSource struct {
+	profiles map[string]Entry
+	// down makes every request fail as if SeaTalk were unreachable
+	down          bool
+	profileCalls  [][]string
+	emailBatchLen []int
+}
+
+func (s *fakeSource) Profiles(codes []string) ([]Entry, error) {
+	if s.down {
+		return nil, errors.New("seatalk unreachable")
+	}
+	s.profileCalls = append(s.profileCalls, append([]string(nil), codes...))
+	var entries []Entry
+	for _, code := range codes {
+		if p, ok := s.profiles[code]; ok {
+			entries = append(entries, p)
+		}
+	}
+	return entries, nil
+}
+
+func (s *fakeSource) EmployeeCodes(emails []string) (map[string]string, error) {
+	if s.down {
+		return nil, errors.New("seatalk unreachable")
+	}
+	s.emailBatchLen = append(s.emailBatchLen, len(emails))
+	codes := make(map[string]string)
+	for _, email := range emails {
+		for _, p := range s.profiles {
+			if strings.EqualFold(p.Email, email) {
+				codes[strings.ToLower(email)] = p.EmployeeCode
+			}
+		}
+	}
+	return codes, nil
+}
+
+var start = time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+
+// newDirectory loads a directory from path, fetching from source with a one hour TTL
+func newDirectory(t *testing.T, path string, source Source, clk clock.Clock) *Directory {
+	t.Helper()
+	d, err := Load(path, Options{Source: source, TTL: time.Hour, Clock: clk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestLookupRefreshesAfterTTL(t *testing.T) {
+	clk := clock.NewFake(start)
+	source := &fakeSource{profiles: map[string]Entry{
+		"E001": {EmployeeCode: "E001", Name: "Jane", Email: "jane@example.com"},
+	}}
+	d := newDirectory(t, filepath.Join(t.TempDir(), "directory.json"), source, clk)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		rename  string
+		want    string
+		fetches int
+	}{
+		{"unknown employee is fetched", 0, "", "Jane", 1},
+		{"fresh entry is cached", 59 * time.Minute, "Jane Doe", "Jane", 1},
+		{"entry past the TTL is fetched again", time.Minute, "", "Jane Doe", 2},
+	}
+	for _, step := range steps {
+		clk.Advance(step.advance)
+		if step.rename != "" {
+			source.profiles["E001"] = Entry{EmployeeCode: "E001", Name: step.rename, Email: "jane@example.com"}
+		}
+		got, err := d.Lookup("E001")
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got.Name != step.want || len(source.profileCalls) != step.fetches {
+			t.Errorf("%s: got %q after %d fetches, want %q after %d", step.name, got.Name, len(source.profileCalls), step.want, step.fetches)
+		}
+	}
+}
+
+func TestLookupFallsBackToStaleEntry(t *testing.T) {
+	clk := clock.NewFake(start)
+	source := &fakeSource{profiles: map[string]Entry{"E001": {EmployeeCode: "E001", Name: "Jane"}}}
+	d := newDirectory(t, filepath.Join(t.TempDir(), "directory.json"), source, clk)
+	if _, err := d.Lookup("E001"); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(2 * time.Hour)
+	source.down = true
+	got, err := d.Lookup("E001")
+	if err != nil || got.Name != "Jane" {
+		t.Errorf("Lookup() with SeaTalk down = %+v, %v, want the stale entry", got, err)
+	}
+	if _, err := d.Lookup("E002"); err == nil {
+		t.Error("Lookup() of an unknown employee with SeaTalk down succeeded")
+	}
+}
+
+func TestLookupWithoutSource(t *testing.T) {
+	d := newDirectory(t, filepath.Join(t.TempDir(), "directory.json"), nil, clock.NewFake(start))
+	d.Learn(Entry{EmployeeCode: "E001", Name: "Jane"})
+
+	if got, err := d.Lookup("E001"); err != nil || got.Name != "Jane" {
+		t.Errorf("Lookup() = %+v, %v, want the learned entry", got, err)
+	}
+	if _, err := d.Lookup("E002"); err == nil {
+		t.Error("Lookup() of an unknown employee succeeded")
+	}
+	if err := d.Sync(nil); err == nil {
+		t.Error("Sync() without a source succeeded")
+	}
+}
+
+func TestSyncBatches(t *testing.T) {
+	clk := clock.NewFake(start)
+	source := &fakeSource{profiles: make(map[string]Entry)}
+	var emails []string
+	for i := 0; i < 250; i++ {
+		code := fmt.Sprintf("E%03d", i)
+		email := fmt.Sprintf("user%d@example.com", i)
+		source.profiles[code] = Entry{EmployeeCode: code, Email: email}
+		emails = append(emails, email)
+	}
+	d := newDirectory(t, filepath.Join(t.TempDir(), "directory.json"), source, clk)
+
+	if err := d.Sync(emails); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{100, 100, 50}; !reflect.DeepEqual(source.emailBatchLen, want) {
+		t.Errorf("email batches = %v, want %v", source.emailBatchLen, want)
+	}
+	var profileBatches []int
+	for _, call := range source.profileCalls {
+		profileBatches = append(profileBatches, len(call))
+	}
+	if want := []int{100, 100, 50}; !reflect.DeepEqual(profileBatches, want) {
+		t.Errorf("profile batches = %v, want %v", profileBatches, want)
+	}
+	if got := len(d.Entries()); got != 250 {
+		t.Errorf("got %d entries, want 250", got)
+	}
+
+	// A second sync within the TTL has nothing to fetch
+	source.profileCalls, source.emailBatchLen = nil, nil
+	if err := d.Sync(emails); err != nil {
+		t.Fatal(err)
+	}
+	if len(source.profileCalls) != 0 || len(source.emailBatchLen) != 0 {
+		t.Errorf("second sync fetched %d profile and %d email batches", len(source.profileCalls), len(source.emailBatchLen))
+	}
+}
+
+func TestLearnMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "directory.json")
+	d := newDirectory(t, path, nil, clock.NewFake(start))
+
+	err := d.LearnMessage(request.EventMessage{
+		Sender: request.EventUser{EmployeeCode: "E001", SeatalkID: "S1"},
+		Text: request.EventMessageText{
+			MentionedList: []request.MentionedUser{{SeatalkID: "S2", Username: "Bob"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Later callbacks fill in what was missing without overwriting
+	d.Learn(Entry{SeatalkID: "S1", Name: "Jane"})
+	d.Learn(Entry{SeatalkID: "S1", Name: "Someone else", Email: "jane@example.com"})
+	d.Learn(Entry{EmployeeCode: "E002", SeatalkID: "S2"})
+	d.Learn(Entry{Name: "No identifiers"})
+
+	want := []Entry{
+		{EmployeeCode: "E002", SeatalkID: "S2", Name: "Bob"},
+		{EmployeeCode: "E001", SeatalkID: "S1", Name: "Jane", Email: "jane@example.com"},
+	}
+	if got := d.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %+v, want %+v", got, want)
+	}
+
+	reloaded := newDirectory(t, path, nil, clock.NewFake(start))
+	if got := reloaded.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("reloaded entries = %+v, want %+v", got, want)
+	}
+	if got := reloaded.Resolve(request.EventUser{SeatalkID: "S2"}); got.Name != "Bob" || got.EmployeeCode != "E002" {
+		t.Errorf("Resolve() by SeaTalk ID = %+v", got)
+	}
+}
+
+func TestHandEditedNamesAreKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "directory.json")
+	edited := `[{"employee_code":"E001","name":"Jane (Ops)","email":"jane@example.com","updated_at":"0001-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := &fakeSource{profiles: map[string]Entry{
+		"E001": {EmployeeCode: "E001", SeatalkID: "S1", Email: "jane@example.com", Department: "Ops"},
+	}}
+	d := newDirectory(t, path, source, clock.NewFake(start))
+
+	got, err := d.Lookup("E001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Jane (Ops)" || got.SeatalkID != "S1" || got.Department != "Ops" {
+		t.Errorf("Lookup() = %+v, want the hand-edited name with the fetched profile", got)
+	}
+	if got, _ := newDirectory(t, path, nil, clock.NewFake(start)).ByEmployeeCode("E001"); got.Name != "Jane (Ops)" {
+		t.Errorf("stored name = %q, want the hand-edited one", got.Name)
+	}
+}
+
+func TestLookupEmail(t *testing.T) {
+	source := &fakeSource{profiles: map[string]Entry{
+		"E001": {EmployeeCode: "E001", Name: "Jane", Email: "jane@example.com"},
+	}}
+	d := newDirectory(t, filepath.Join(t.TempDir(), "directory.json"), source, clock.NewFake(start))
+
+	got, err := d.LookupEmail("Jane@Example.com")
+	if err != nil || got.EmployeeCode != "E001" {
+		t.Errorf("LookupEmail() = %+v, %v", got, err)
+	}
+	if _, err := d.LookupEmail("nobody@example.com"); err == nil {
+		t.Error("LookupEmail() of an unknown email succeeded")
+	}
+}
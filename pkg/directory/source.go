@@ -0,0 +1,65 @@
+package directory
+
+import (
+	"strings"
+
+	"seatalk-bot/pkg/seatalk"
+)
+
+// Source fetches employee profiles
+type Source interface {
+	// Profiles returns the profiles of the given employee codes
+	Profiles(codes []string) ([]Entry, error)
+	// EmployeeCodes maps the given emails, lower-cased, to employee codes
+	EmployeeCodes(emails []string) (map[string]string, error)
+}
+
+// contactsSource fetches profiles through the SeaTalk contacts API
+type contactsSource struct {
+	contacts seatalk.Contacts
+}
+
+// NewContactsSource creates a Source backed by the SeaTalk contacts API
+func NewContactsSource(contacts seatalk.Contacts) Source {
+	return &contactsSource{contacts: contacts}
+}
+
+// Profiles returns the profiles of the given employee codes
+func (s *contactsSource) Profiles(codes []string) ([]Entry, error) {
+	profiles, err := s.contacts.EmployeeProfiles(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(profiles))
+	for _, p := range profiles {
+		name := p.Name
+		if name == "" {
+			name = p.SeatalkNickname
+		}
+		entries = append(entries, Entry{
+			EmployeeCode: p.EmployeeCode,
+			SeatalkID:    p.SeatalkID,
+			Name:         name,
+			Email:        p.Email,
+			Department:   strings.Join(p.Departments, ", "),
+		})
+	}
+	return entries, nil
+}
+
+// EmployeeCodes maps the given emails, lower-cased, to employee codes
+func (s *contactsSource) EmployeeCodes(emails []string) (map[string]string, error) {
+	results, err := s.contacts.EmployeeCodesByEmail(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[string]string, len(results))
+	for _, r := range results {
+		if r.EmployeeCode != "" {
+			codes[strings.ToLower(r.Email)] = r.EmployeeCode
+		}
+	}
+	return codes, nil
+}
@@ -0,0 +1,69 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/answer"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/directory"
+)
+
+// answersEnabled reports whether free-form questions are answered where the
+// message was sent: always in direct messages, in groups that turned it on
+func (s *EventCallbackService) answersEnabled(c *command.Context) bool {
+	if s.answerer == nil {
+		return false
+	}
+	if !c.InGroup() {
+		return true
+	}
+	g, ok := s.groups.Get(c.GroupID)
+	return ok && g.Answers
+}
+
+// answerQuestion asks the answer backend about free text, with the earlier
+// turns of the conversation. It returns false when the backend failed.
+func (s *EventCallbackService) answerQuestion(ctx context.Context, c *command.Context, sender directory.Entry, text string) (string, bool) {
+	reply, err := s.answerer.Answer(ctx, answer.Question{
+		Text:    answer.Truncate(text, s.config.AnswerMaxChars),
+		Locale:  c.Locale,
+		History: s.conversations.Turns(conversationKey(c)),
+		Asker:   sender.DisplayName(),
+	})
+	if err == nil && strings.TrimSpace(reply) == "" {
+		err = errors.New(constants.ErrAnswerFailed + ": empty answer")
+	}
+	if err != nil {
+		slog.Warn("failed to answer question", "group_id", c.GroupID, "employee_code", c.EmployeeCode, "error", err)
+		return "", false
+	}
+	return answer.Truncate(strings.TrimSpace(reply), s.config.AnswerMaxChars), true
+}
+
+// remember records a message and the reply of the bot in the history of
+// their conversation
+func (s *EventCallbackService) remember(c *command.Context, text, reply string) {
+	sender := s.directory.Resolve(request.EventUser{SeatalkID: c.SeatalkID, EmployeeCode: c.EmployeeCode})
+	s.conversations.Add(conversationKey(c),
+		answer.Turn{Role: answer.RoleUser, Name: sender.DisplayName(), Text: answer.Truncate(text, s.config.AnswerMaxChars)},
+		answer.Turn{Role: answer.RoleAssistant, Text: answer.Truncate(reply, s.config.AnswerMaxChars)},
+	)
+}
+
+// conversationKey identifies the conversation of a message: its thread in a
+// group, or the direct chat with its sender
+func conversationKey(c *command.Context) string {
+	if !c.InGroup() {
+		return "dm/" + c.EmployeeCode
+	}
+	thread := c.ThreadID
+	if thread == "" {
+		thread = c.MessageID
+	}
+	return "group/" + c.GroupID + "/" + thread
+}
@@ -0,0 +1,147 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"seatalk-bot/pkg/answer"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/groups"
+)
+
+// failingAnswerer is an Answerer whose backend is down
+type failingAnswerer struct{}
+
+func (failingAnswerer) Answer(ctx context.Context, q answer.Question) (string, error) {
+	return "", errors.New("backend down")
+}
+
+const defaultReply = "Message received. How can I help?"
+
+func TestReplyFallsThroughToAnswerer(t *testing.T) {
+	svc, _, _ := newTestService(t, WithAnswerer(answer.Stub{}))
+	if _, err := svc.faq.Add([]string{"deploy staging"}, "Run the deploy pipeline.", "E001"); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		text string
+		want string
+	}{
+		{"/faq 1", "Run the deploy pipeline."},
+		{"how do I deploy staging?", "Run the deploy pipeline."},
+		// Both earlier messages and their replies are part of the conversation
+		{"what is for lunch?", `[stub en] "what is for lunch?" after 4 earlier messages`},
+	}
+	for _, step := range steps {
+		c := &command.Context{EmployeeCode: "E001", Locale: "en"}
+		got, err := svc.respond(context.Background(), c, step.text)
+		if err != nil {
+			t.Fatalf("respond(%q): %v", step.text, err)
+		}
+		if got != step.want {
+			t.Errorf("respond(%q) = %q, want %q", step.text, got, step.want)
+		}
+	}
+}
+
+func TestLooseFAQMatchesFollowTheAnswer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			"with a backend", []Option{WithAnswerer(answer.Stub{})},
+			"[stub en] \"how do I deploy production?\" after 0 earlier messages\n\n" +
+				"Related questions:\n#1 deploy staging\nSend /faq <number> to see an answer.",
+		},
+		{
+			"without a backend", nil,
+			"I am not sure what you mean. The closest questions are:\n#1 deploy staging\nSend /faq <number> to see an answer.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestService(t, tt.opts...)
+			if _, err := svc.faq.Add([]string{"deploy staging"}, "Run the deploy pipeline.", "E001"); err != nil {
+				t.Fatal(err)
+			}
+			c := &command.Context{EmployeeCode: "E001", Locale: "en"}
+			got, err := svc.reply(context.Background(), c, "how do I deploy production?")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("reply = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupAnswersNeedTheGroupFlag(t *testing.T) {
+	svc, _, _ := newTestService(t, WithAnswerer(answer.Stub{}))
+	if _, err := svc.groups.Ensure("emulator-group"); err != nil {
+		t.Fatal(err)
+	}
+	ask := func() string {
+		t.Helper()
+		c := &command.Context{GroupID: "emulator-group", EmployeeCode: "E001", MessageID: "m1", Locale: "en"}
+		got, err := svc.reply(context.Background(), c, "what is for lunch?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	if got := ask(); got != defaultReply {
+		t.Errorf("reply with answers off = %q, want the default reply", got)
+	}
+	err := svc.groups.Update("emulator-group", func(g *groups.Group) error {
+		g.Answers = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ask(), `[stub en] "what is for lunch?" after 0 earlier messages`; got != want {
+		t.Errorf("reply with answers on = %q, want %q", got, want)
+	}
+}
+
+func TestReplyWithoutWorkingAnswerer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no backend", nil},
+		{"failing backend", []Option{WithAnswerer(failingAnswerer{})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newTestService(t, tt.opts...)
+			c := &command.Context{EmployeeCode: "E001", Locale: "en"}
+			got, err := svc.reply(context.Background(), c, "what is for lunch?")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != defaultReply {
+				t.Errorf("reply = %q, want the default reply", got)
+			}
+		})
+	}
+}
+
+func TestAnswersAreTruncated(t *testing.T) {
+	svc, _, _ := newTestService(t, WithAnswerer(answer.Stub{}))
+	svc.config.AnswerMaxChars = 12
+	c := &command.Context{EmployeeCode: "E001", Locale: "en"}
+	got, err := svc.reply(context.Background(), c, "a long question that goes on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[stub en] "…`; got != want {
+		t.Errorf("reply = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package eventcallback
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+)
+
+// Number of entries /audit shows by default and at most
+const (
+	auditDefaultLimit = 10
+	auditMaxLimit     = 50
+)
+
+// cmdAudit shows the latest audit log entries, optionally of one action.
+// Group admins only see the entries of their group; global admins see all.
+func (s *EventCallbackService) cmdAudit(ctx context.Context, c *command.Context) (string, error) {
+	filter := audit.Filter{Limit: auditDefaultLimit}
+	for _, arg := range c.Args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 1 {
+				return i18n.T(c.Locale, i18n.MsgAuditUsage), nil
+			}
+			filter.Limit = min(n, auditMaxLimit)
+			continue
+		}
+		if filter.Action != "" {
+			return i18n.T(c.Locale, i18n.MsgAuditUsage), nil
+		}
+		filter.Action = strings.ToLower(arg)
+	}
+	if c.GroupID != "" && !s.roles.Bound(roles.Global, c.EmployeeCode).AtLeast(roles.Admin) {
+		filter.GroupID = c.GroupID
+	}
+
+	entries, err := s.audit.Query(filter)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return i18n.T(c.Locale, i18n.MsgAuditNone), nil
+	}
+
+	g, _ := s.groups.Get(c.GroupID)
+	loc := g.Location()
+	lines := []string{i18n.T(c.Locale, i18n.MsgAuditHeader, len(entries))}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgAuditEntry,
+			e.Time.In(loc).Format("2006-01-02 15:04"), e.Actor, e.Action, e.Target, e.Outcome))
+	}
+	return strings.Join(lines, "\n"), nil
+}
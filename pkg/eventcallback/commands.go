@@ -0,0 +1,119 @@
+package eventcallback
+
+import (
+	"context"
+	"strings"
+
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+)
+
+// registerCommands registers the bot commands
+func (s *EventCallbackService) registerCommands() {
+	s.commands.Register(command.Command{
+		Name:    "audit",
+		Usage:   "/audit [count] [action]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpAudit) },
+		Role:    roles.Admin,
+		Handler: s.cmdAudit,
+	})
+	s.commands.Register(command.Command{
+		Name:    "config",
+		Usage:   "/config [jobs add|remove <job>|timezone <zone>|language <en|id>|quiet <HH:MM-HH:MM|off>]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpConfig) },
+		Handler: s.cmdConfig,
+	})
+	s.commands.Register(command.Command{
+		Name:    "faq",
+		Usage:   "/faq [list|<number>|<question>|add <trigger>; <trigger> => <answer>|edit <number> [<triggers>] => [<answer>]|remove <number>]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpFAQ) },
+		Handler: s.cmdFAQ,
+	})
+	s.commands.Register(command.Command{
+		Name:    "help",
+		Usage:   "/help",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpHelp) },
+		Handler: s.cmdHelp,
+	})
+	s.commands.Register(command.Command{
+		Name:    "lang",
+		Usage:   "/lang [en|id]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpLang) },
+		Handler: s.cmdLang,
+	})
+	s.commands.Register(command.Command{
+		Name:    "pic",
+		Usage:   "/pic [history [count]|ical|undo|restore <version>]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpPIC) },
+		Handler: s.cmdPIC,
+	})
+	s.commands.Register(command.Command{
+		Name:    "role",
+		Usage:   "/role [list|grant <admin|owner> <@user>|revoke <@user>] [global]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpRole) },
+		Handler: s.cmdRole,
+	})
+	s.commands.Register(command.Command{
+		Name:    "subscribe",
+		Usage:   "/subscribe [pic [days]|reminders]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpSubscribe) },
+		Handler: s.cmdSubscribe,
+	})
+	s.commands.Register(command.Command{
+		Name:    "unsubscribe",
+		Usage:   "/unsubscribe [pic|reminders|all]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpUnsubscribe) },
+		Handler: s.cmdUnsubscribe,
+	})
+	s.commands.Register(command.Command{
+		Name:    "whois",
+		Usage:   "/whois [@user|email|employee code]",
+		Help:    func(locale string) string { return i18n.T(locale, i18n.MsgCommandHelpWhois) },
+		Handler: s.cmdWhois,
+	})
+}
+
+// cmdHelp lists the available commands
+func (s *EventCallbackService) cmdHelp(ctx context.Context, c *command.Context) (string, error) {
+	var b strings.Builder
+	b.WriteString(i18n.T(c.Locale, i18n.MsgHelpHeader))
+	for _, cmd := range s.commands.Commands() {
+		b.WriteString("\n" + cmd.Usage)
+		if cmd.Help != nil {
+			b.WriteString(" - " + cmd.Help(c.Locale))
+		}
+	}
+	return b.String(), nil
+}
+
+// cmdLang shows or changes the language of the group, or of the user in a
+// direct message
+func (s *EventCallbackService) cmdLang(ctx context.Context, c *command.Context) (string, error) {
+	if len(c.Args) == 0 {
+		return i18n.T(c.Locale, i18n.MsgLangCurrent, i18n.LanguageName(c.Locale, c.Locale)), nil
+	}
+	if len(c.Args) > 1 {
+		return i18n.T(c.Locale, i18n.MsgLangUsage), nil
+	}
+
+	locale, ok := i18n.Normalize(c.Args[0])
+	if !ok {
+		return i18n.T(c.Locale, i18n.MsgLangUnsupported, c.Args[0], strings.Join(i18n.Supported(), ", ")), nil
+	}
+
+	if c.InGroup() {
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		if err := s.locales.SetGroupLocale(c.GroupID, locale); err != nil {
+			return "", err
+		}
+		return i18n.T(locale, i18n.MsgLangSetGroup, i18n.LanguageName(locale, locale)), nil
+	}
+
+	if err := s.locales.SetUserLocale(c.EmployeeCode, locale); err != nil {
+		return "", err
+	}
+	return i18n.T(locale, i18n.MsgLangSetUser, i18n.LanguageName(locale, locale)), nil
+}
@@ -0,0 +1,95 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/directory"
+	"seatalk-bot/pkg/i18n"
+)
+
+// SyncDirectory fetches the profiles of every rotation member and refreshes
+// the stale profiles of the directory
+func (s *EventCallbackService) SyncDirectory() error {
+	var emails []string
+	for _, name := range s.schedules.Names() {
+		store, err := s.schedules.Get(name)
+		if err != nil {
+			return err
+		}
+		schedules, err := store.Read()
+		if err != nil {
+			return err
+		}
+		for _, sched := range schedules {
+			emails = append(emails, sched.Email)
+		}
+	}
+	return s.directory.Sync(emails)
+}
+
+// member resolves a user mentioned in a command to a directory entry with an email
+func (s *EventCallbackService) member(user request.MentionedUser) (directory.Entry, error) {
+	entry, found := s.directory.BySeatalkID(user.SeatalkID)
+	if found && entry.EmployeeCode != "" {
+		if fetched, err := s.directory.Lookup(entry.EmployeeCode); err == nil {
+			entry = fetched
+		}
+	}
+	if !found || entry.Email == "" {
+		return directory.Entry{}, errors.New(constants.ErrEmployeeNotFound + ": " + user.Username)
+	}
+	return entry, nil
+}
+
+// resolveMember resolves a command argument given as an email or an employee code
+func (s *EventCallbackService) resolveMember(arg string) (directory.Entry, error) {
+	if strings.Contains(arg, "@") {
+		return s.directory.LookupEmail(arg)
+	}
+	return s.directory.Lookup(arg)
+}
+
+// cmdWhois shows what the directory knows about the mentioned users, the
+// given emails or employee codes, or the sender
+func (s *EventCallbackService) cmdWhois(ctx context.Context, c *command.Context) (string, error) {
+	var lines []string
+	for _, user := range c.Mentions {
+		entry, err := s.member(user)
+		if err != nil {
+			lines = append(lines, i18n.T(c.Locale, i18n.MsgWhoisUnknown, user.Username))
+			continue
+		}
+		lines = append(lines, formatMember(c.Locale, entry))
+	}
+	for _, arg := range c.Args {
+		entry, err := s.resolveMember(arg)
+		if err != nil {
+			lines = append(lines, i18n.T(c.Locale, i18n.MsgWhoisUnknown, arg))
+			continue
+		}
+		lines = append(lines, formatMember(c.Locale, entry))
+	}
+
+	if len(lines) == 0 {
+		entry := s.directory.Resolve(request.EventUser{SeatalkID: c.SeatalkID, EmployeeCode: c.EmployeeCode})
+		lines = append(lines, formatMember(c.Locale, entry))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// formatMember describes a directory entry
+func formatMember(locale string, e directory.Entry) string {
+	unknown := i18n.T(locale, i18n.MsgUnknownValue)
+	value := func(v string) string {
+		if v == "" {
+			return unknown
+		}
+		return v
+	}
+	return i18n.T(locale, i18n.MsgWhoisEntry, value(e.DisplayName()), value(e.Email), value(e.EmployeeCode), value(e.Department))
+}
@@ -1,272 +1,412 @@
 package eventcallback
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"seatalk-bot/internal/clock"
 	"seatalk-bot/internal/config"
 	"seatalk-bot/internal/constants"
 	"seatalk-bot/models/request"
 	"seatalk-bot/models/response"
-
-	"github.com/robfig/cron/v3"
+	"seatalk-bot/pkg/answer"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/directory"
+	"seatalk-bot/pkg/eventrouter"
+	"seatalk-bot/pkg/faq"
+	"seatalk-bot/pkg/groups"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/jobs"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/rotation"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/seatalk"
+	"seatalk-bot/pkg/subscription"
+	"seatalk-bot/pkg/templates"
+	tokernservice "seatalk-bot/pkg/tokenservice"
 )
 
 // EventCallbackService handles event callbacks and scheduled tasks
 type EventCallbackService struct {
-	config *config.Config
-	cron   *cron.Cron
+	config        *config.Config
+	clock         clock.Clock
+	tokens        *tokernservice.TokenService
+	sender        seatalk.Sender
+	templates     *templates.Renderer
+	locales       *i18n.Preferences
+	directory     *directory.Directory
+	subscriptions *subscription.Store
+	groups        *groups.Registry
+	held          *groups.HeldQueue
+	roles         *roles.Store
+	audit         *audit.Log
+	commands      *command.Registry
+	jobs          *jobs.Runner
+	router        *eventrouter.EventRouter
+	schedules     *schedule.Registry
+	rotations     map[string]*rotation.Rotation
+	faq           *faq.Store
+	answerer      answer.Answerer
+	conversations *answer.History
+	// alerts holds the last schedule alert sent, per kind and rotation
+	alertsMu sync.Mutex
+	alerts   map[string]string
 }
 
-// NewEventCallbackService creates a new EventCallbackService
-func NewEventCallbackService(cfg *config.Config) *EventCallbackService {
-	service := &EventCallbackService{
-		config: cfg,
-		cron:   cron.New(),
+// NewEventCallbackService creates a new EventCallbackService, loading the
+// state persisted in the configured data directory without changing it
+func NewEventCallbackService(cfg *config.Config, opts ...Option) (*EventCallbackService, error) {
+	o := options{
+		clock:        clock.Real{},
+		scheduleFile: constants.StockInventoryScheduleFile,
+	}
+	for _, opt := range opts {
+		opt(&o)
 	}
 
-	// Schedule jobs
-	service.scheduleJobs()
-
-	return service
-}
+	tokens := tokernservice.NewTokenService(cfg)
+	if o.sender == nil {
+		o.sender = seatalk.NewSender(cfg, tokens)
+	}
+	if o.templates == nil {
+		renderer, err := templates.Load(cfg.TemplatesDir)
+		if err != nil {
+			return nil, err
+		}
+		o.templates = renderer
+	}
+	if o.answerer == nil {
+		answerer, err := answer.FromConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		o.answerer = answerer
+	}
 
-// scheduleJobs schedules periodic tasks
-func (s *EventCallbackService) scheduleJobs() {
-	// Schedule a job to run at 12 AM every Tuesday in Jakarta time
-	s.cron.AddFunc("25 14 * * 3", func() {
-		s.performScheduledPIC()
-	})
-	s.cron.AddFunc("0 0 * * 5", func() {
-		s.performScheduledReminderReturnRefund()
-	})
-	s.cron.Start()
-}
+	locales, err := i18n.LoadPreferences(filepath.Join(cfg.DataDir, constants.LocalesFile))
+	if err != nil {
+		return nil, err
+	}
+	directoryOpts := directory.Options{TTL: cfg.DirectoryTTL, Clock: o.clock}
+	if cfg.ProfileUrl != "" && cfg.EmployeeCodeUrl != "" {
+		directoryOpts.Source = directory.NewContactsSource(seatalk.NewClient(cfg, tokens))
+	}
+	dir, err := directory.Load(filepath.Join(cfg.DataDir, constants.DirectoryFile), directoryOpts)
+	if err != nil {
+		return nil, err
+	}
 
-// performScheduledReminderReturnRefund checks if it's 12 AM Friday in Jakarta and performs the task
-// for reminding test return and refund
-func (s *EventCallbackService) performScheduledReminderReturnRefund() {
-	req := request.SendMessageToBotGroupRequest{
-		GroupID: s.config.RegressionGroupID, // Replace with the actual group ID
-		Message: request.MessageGroup{
-			Tag: "Text",
-			Text: request.TextGroup{
-				Format:  1,
-				Content: constants.ReminderReturnRefund, // Use the constructed data as the message content
-			},
-		},
+	subscriptions, err := subscription.Load(filepath.Join(cfg.DataDir, constants.SubscriptionsFile))
+	if err != nil {
+		return nil, err
 	}
 
-	// Send the message to the group
-	if _, err := s.SendMessageToGroup(req); err != nil {
-		log.Println("Failed to send message to group:", err)
+	groupRegistry, err := groups.Load(filepath.Join(cfg.DataDir, constants.GroupsFile))
+	if err != nil {
+		return nil, err
+	}
+	held, err := groups.LoadHeld(filepath.Join(cfg.DataDir, constants.HeldMessagesFile))
+	if err != nil {
+		return nil, err
 	}
-}
 
-// performScheduledTask checks if it's 12 AM Tuesday in Jakarta and performs the task
-func (s *EventCallbackService) performScheduledPIC() {
-	filename := "stock_inventory_schedule.txt"
-	// Read schedules from file
-	schedules, err := ReadSchedules(filename)
+	roleStore, err := roles.Load(filepath.Join(cfg.DataDir, constants.RolesFile), cfg.Owners)
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-	// Get the start and end date of the current week
-	startOfWeek, endOfWeek := GetCurrentWeekRange()
 
-	// Display PICs for the current week
-	data := "PICs for this week: \n"
-	// Display PICs for the current week
-	data += DisplayPICsWithinRange(schedules, startOfWeek, endOfWeek)
-	schedules, err = ReadSchedules(filename)
+	auditLog := audit.Open(filepath.Join(cfg.DataDir, constants.AuditFile), o.clock)
+	stockInventory := schedule.NewStore(constants.StockInventoryRotation, o.scheduleFile, o.clock)
+	stockInventory.SetAudit(auditLog)
+	history, err := schedule.LoadHistory(filepath.Join(cfg.DataDir, constants.ScheduleHistoryDir, constants.StockInventoryRotation+".json"))
 	if err != nil {
-		log.Println(err)
-		return
+		return nil, err
 	}
-	// Display the full schedule
-	data += DisplayFullSchedule(schedules)
-
-	req := request.SendMessageToBotGroupRequest{
-		GroupID: s.config.RegressionGroupID, // Replace with the actual group ID
-		Message: request.MessageGroup{
-			Tag: "Text",
-			Text: request.TextGroup{
-				Format:  1,
-				Content: data, // Use the constructed data as the message content
-			},
-		},
+	stockInventory.SetHistory(history)
+	stockInventoryRotation, err := rotation.Load(filepath.Join(cfg.DataDir, constants.RotationsDir, constants.StockInventoryRotation+".json"))
+	if err != nil {
+		return nil, err
 	}
 
-	// Send the message to the group
-	if _, err := s.SendMessageToGroup(req); err != nil {
-		log.Println("Failed to send message to group:", err)
+	faqStore, err := faq.Load(filepath.Join(cfg.DataDir, constants.FAQFile), o.clock)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// HandleEventCallback is the HTTP handler for event callbacks
-func (s *EventCallbackService) HandleEventCallback(w http.ResponseWriter, r *http.Request) {
-	// Limit to POST requests
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	service := &EventCallbackService{
+		config:        cfg,
+		clock:         o.clock,
+		tokens:        tokens,
+		sender:        o.sender,
+		templates:     o.templates,
+		locales:       locales,
+		directory:     dir,
+		subscriptions: subscriptions,
+		groups:        groupRegistry,
+		held:          held,
+		roles:         roleStore,
+		audit:         auditLog,
+		commands:      command.NewRegistry(),
+		jobs:          jobs.NewRunner(o.clock),
+		router:        eventrouter.NewEventRouter(),
+		schedules:     schedule.NewRegistry(stockInventory),
+		rotations:     map[string]*rotation.Rotation{constants.StockInventoryRotation: stockInventoryRotation},
+		faq:           faqStore,
+		answerer:      o.answerer,
+		conversations: answer.NewHistory(cfg.AnswerHistory, o.clock),
+		alerts:        make(map[string]string),
 	}
 
-	// Decode the incoming request
-	var eventRequest request.EventCallbackRequest
-	if err := json.NewDecoder(r.Body).Decode(&eventRequest); err != nil {
-		http.Error(w, "Bad request", http.StatusBadRequest)
-		return
-	}
+	// Register event handlers and bot commands
+	service.registerHandlers()
+	service.registerCommands()
 
-	// Prepare the response message based on the event type
-	switch eventRequest.EventType {
-	case "message_from_bot_subscriber":
-		req := request.SendMessageToBotSubscriberRequest{
-			EmployeeCode: eventRequest.Event.EmployeeCode,
-			Message: request.MessageSingle{
-				Tag: "Text",
-				Text: request.TextSingle{
-					Format:  1,
-					Content: "Message received. How can I help?",
-				},
-			},
-		}
-		if _, err := s.SendMessageToSubscriber(req); err != nil {
-			http.Error(w, "Failed to send message", http.StatusInternalServerError)
-			return
-		}
-	case "new_mentioned_message_from_group_chat":
-		req := request.SendMessageToBotGroupRequest{
-			GroupID: eventRequest.Event.GroupID,
-			Message: request.MessageGroup{
-				Tag: "Text",
-				Text: request.TextGroup{
-					Format:  1,
-					Content: "Message received. How can I help?",
-				},
-			},
-		}
-		if _, err := s.SendMessageToGroup(req); err != nil {
-			http.Error(w, "Failed to send message", http.StatusInternalServerError)
-			return
-		}
-	default:
-		http.Error(w, "Unsupported event type", http.StatusBadRequest)
-		return
+	// Schedule jobs
+	service.scheduleJobs()
+
+	return service, nil
+}
+
+// Migrate upgrades the state saved by earlier versions of the bot. It writes
+// to the data directory, so it is left to the server rather than one-shot commands.
+func (s *EventCallbackService) Migrate() error {
+	if err := s.seedLegacyGroup(); err != nil {
+		return err
 	}
+	return s.migrateGroupAdmins()
+}
+
+// Tokens returns the service managing the app access token
+func (s *EventCallbackService) Tokens() *tokernservice.TokenService {
+	return s.tokens
+}
+
+// Directory returns the employees known to the bot
+func (s *EventCallbackService) Directory() *directory.Directory {
+	return s.directory
+}
 
-	// Prepare the response
-	response := response.EventCallbackResponse{
-		SeatalkChallenge: eventRequest.Event.SeaTalkChallenge,
+// Jobs returns the runner of the scheduled jobs
+func (s *EventCallbackService) Jobs() *jobs.Runner {
+	return s.jobs
+}
+
+// Groups returns the registry of groups and their settings
+func (s *EventCallbackService) Groups() *groups.Registry {
+	return s.groups
+}
+
+// Roles returns the roles bound to employees
+func (s *EventCallbackService) Roles() *roles.Store {
+	return s.roles
+}
+
+// Audit returns the audit log of schedule changes, jobs, messages and admin actions
+func (s *EventCallbackService) Audit() *audit.Log {
+	return s.audit
+}
+
+// Schedules returns the registry of rotation schedules
+func (s *EventCallbackService) Schedules() *schedule.Registry {
+	return s.schedules
+}
+
+// scheduleJobs schedules periodic tasks
+func (s *EventCallbackService) scheduleJobs() {
+	// Schedule a job to run at 12 AM every Tuesday in Jakarta time
+	s.addJob(constants.JobStockInventoryPIC, "25 14 * * 3", s.performScheduledPIC)
+	s.addJob(constants.JobReturnRefundReminder, "0 0 * * 5", s.performScheduledReminderReturnRefund)
+	s.addJob(constants.JobPICNotice, "0 9 * * *", s.performPICNotice)
+	// Runs every minute, so its runs are neither logged nor audited; the
+	// messages it releases are recorded one by one
+	if err := s.jobs.AddQuiet(constants.JobReleaseHeld, "* * * * *", s.releaseHeld); err != nil {
+		slog.Error("failed to schedule job", "job", constants.JobReleaseHeld, "error", err)
+	}
+	if s.directory.HasSource() {
+		s.addJob(constants.JobDirectorySync, "0 22 * * *", s.SyncDirectory)
 	}
+}
 
-	// Set the response header and send the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+// Start starts running the scheduled jobs
+func (s *EventCallbackService) Start() {
+	s.jobs.Start()
 }
 
-// SendMessageToSubscriber sends a message to a subscriber using the Seatalk API
-func (s *EventCallbackService) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
-	apiURL := s.config.SingleChatUrl
+// Stop stops the scheduler and waits for running jobs to finish
+func (s *EventCallbackService) Stop() {
+	<-s.jobs.Stop().Done()
+}
 
-	// Marshal the request into JSON
-	requestBody, err := json.Marshal(req)
-	if err != nil {
-		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToMarshalPayload)
+// addJob registers a job, logging specs that cannot be scheduled. Every run
+// is recorded in the audit log with its outcome and duration.
+func (s *EventCallbackService) addJob(name, spec string, fn func() error) {
+	run := func() error {
+		start := s.clock.Now()
+		err := fn()
+		s.audit.RecordResult(jobContext(name), audit.Entry{
+			Action:  audit.ActionJobRun,
+			Target:  name,
+			Details: map[string]string{"duration": s.clock.Now().Sub(start).Round(time.Millisecond).String()},
+		}, err)
+		return err
 	}
+	if err := s.jobs.Add(name, spec, run); err != nil {
+		slog.Error("failed to schedule job", "job", name, "error", err)
+	}
+}
 
-	// Create a new HTTP request
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
+// performScheduledReminderReturnRefund checks if it's 12 AM Friday in Jakarta and performs the task
+// for reminding test return and refund
+func (s *EventCallbackService) performScheduledReminderReturnRefund() error {
+	// Send the reminder to the groups, then to the subscribers
+	err := s.broadcast(constants.JobReturnRefundReminder, func(g groups.Group, locale string) (string, error) {
+		return s.templates.Render(locale, templates.ReturnRefundReminder, templates.ReminderData{Date: s.clock.Now().In(g.Location()), MentionAll: mentionAll(g)})
+	})
 	if err != nil {
-		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToCreateRequest)
+		return err
 	}
+	return s.notifySubscribers(subscription.TopicReminders, templates.ReturnRefundReminder, templates.ReminderData{Date: s.clock.Now()})
+}
 
-	// Set the request headers
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+// performScheduledTask checks if it's 12 AM Tuesday in Jakarta and performs the task
+func (s *EventCallbackService) performScheduledPIC() error {
+	store, err := s.schedules.Get(constants.StockInventoryRotation)
 	if err != nil {
-		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToExecuteRequest)
+		return err
 	}
-	defer resp.Body.Close()
+	s.checkSchedule(store)
 
-	// Check for a successful status code
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse struct {
-			Message string `json:"message"`
-			Code    int    `json:"code"`
+	// Read schedules from file
+	schedules, err := store.Read()
+	if err != nil {
+		return err
+	}
+	// Get the start and end date of the current week
+	startOfWeek, endOfWeek := store.CurrentWeek()
+	current := schedule.SchedulesWithinRange(schedules, startOfWeek, endOfWeek)
+
+	// Plan the coming periods with the rotation engine when the rotation has
+	// members configured, or rotate this week's PICs to the end of the schedule
+	ctx := jobContext(constants.JobStockInventoryPIC)
+	if rot := s.rotations[store.Name()]; rot != nil && rot.Configured() {
+		if err := rot.Record(current); err != nil {
+			slog.Warn("failed to record served turns", "rotation", store.Name(), "error", err)
+		}
+		if _, err := s.ExtendRotation(ctx, store.Name(), false); err != nil {
+			slog.Warn("failed to plan rotation", "rotation", store.Name(), "error", err)
 		}
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
+	} else {
+		for _, entry := range current {
+			if err := store.Advance(ctx, entry.PIC); err != nil {
+				slog.Warn("failed to rotate PIC", "rotation", store.Name(), "pic", entry.PIC, "error", err)
+			}
 		}
-		return response.SendMessageToBotSubscriberResponse{
-			Code: errorResponse.Code,
-		}, errors.New(constants.ErrApiError)
 	}
 
-	// Parse the response
-	var response response.SendMessageToBotSubscriberResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return response, errors.New(constants.ErrFailedToDecodeResponse)
+	schedules, err = store.Read()
+	if err != nil {
+		return err
 	}
 
-	return response, nil
+	// Announce this week's PICs and the full schedule to every group following the rotation
+	return s.broadcast(constants.JobStockInventoryPIC, func(g groups.Group, locale string) (string, error) {
+		return s.templates.Render(locale, templates.PICAnnouncement, templates.PICAnnouncementData{
+			Rotation:  store.Name(),
+			WeekStart: startOfWeek,
+			WeekEnd:   endOfWeek,
+			Current:   current,
+			Schedule:  schedules,
+		})
+	})
 }
 
-// SendMessageToGroup sends a message to a group
-func (s *EventCallbackService) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
-	apiURL := s.config.GroupChatUrl
+// HandleEventCallback is the HTTP handler for event callbacks
+func (s *EventCallbackService) HandleEventCallback(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
 
-	// Marshal the request into JSON
-	requestBody, err := json.Marshal(req)
-	if err != nil {
-		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToMarshalPayload)
-	}
+// redeliveryWindow is how long a callback is remembered so that SeaTalk
+// redelivering it, for instance while an answer is slow, is not answered twice
+const redeliveryWindow = 10 * time.Minute
 
-	// Create a new HTTP request
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToCreateRequest)
+// registerHandlers registers the handler of every supported event type
+func (s *EventCallbackService) registerHandlers() {
+	if s.config.SigningSecret == "" {
+		slog.Warn("SEATALK_SIGNING_SECRET is not set: callbacks are not verified and commands requiring admin or owner are refused")
 	}
+	s.router.Use(eventrouter.Recovery(), eventrouter.Logging(), eventrouter.Auth(s.config.SigningSecret),
+		eventrouter.Dedupe(redeliveryWindow))
+
+	s.router.Handle(request.EventTypeVerification, eventrouter.Typed(s.handleVerification))
+	s.router.Handle(request.EventTypeMessageFromBotSubscriber, eventrouter.Typed(s.handleSubscriberMessage))
+	s.router.Handle(request.EventTypeNewMentionedMessageFromGroupChat, eventrouter.Typed(s.handleGroupMention))
+	s.router.Handle(request.EventTypeBotAddedToGroupChat, eventrouter.Typed(s.handleBotAdded))
+	s.router.Handle(request.EventTypeBotRemovedFromGroupChat, eventrouter.Typed(s.handleBotRemoved))
+}
 
-	// Set the request headers
-	httpReq.Header.Set("Content-Type", "application/json")
+// handleVerification answers the callback URL verification challenge
+func (s *EventCallbackService) handleVerification(ctx context.Context, evt *eventrouter.Event, event request.VerificationEvent) (interface{}, error) {
+	return response.EventCallbackResponse{
+		SeatalkChallenge: event.SeaTalkChallenge,
+	}, nil
+}
 
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToExecuteRequest)
-	}
-	defer resp.Body.Close()
+// SendMessageToSubscriber sends a message to a subscriber through the configured sender
+func (s *EventCallbackService) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	resp, err := s.sender.SendMessageToSubscriber(req)
+	s.audit.RecordResult(context.Background(), audit.Entry{
+		Action:       audit.ActionMessageSent,
+		Target:       "employee",
+		EmployeeCode: req.EmployeeCode,
+		Details:      s.messageDetails(resp.Code, req.Message.Text.Content),
+	}, err)
+	return resp, err
+}
 
-	// Check for a successful status code
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse struct {
-			Message string `json:"message"`
-			Code    int    `json:"code"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
-		}
-		return response.SendMessageToBotGroupResponse{
-			Code:      errorResponse.Code,
-			MessegeId: "0",
-		}, errors.New(constants.ErrApiError)
+// SendMessageToGroup sends a message to a group through the configured sender
+func (s *EventCallbackService) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	resp, err := s.sender.SendMessageToGroup(req)
+	details := s.messageDetails(resp.Code, req.Message.Text.Content)
+	if req.Message.ThreadID != "" {
+		details["thread_id"] = req.Message.ThreadID
 	}
+	s.audit.RecordResult(context.Background(), audit.Entry{
+		Action:    audit.ActionMessageSent,
+		Target:    "group",
+		GroupID:   req.GroupID,
+		MessageID: resp.MessegeId,
+		Details:   details,
+	}, err)
+	return resp, err
+}
 
-	// Parse the response
-	var groupResponse response.SendMessageToBotGroupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&groupResponse); err != nil {
-		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
+// messageDetails returns the audit details of a sent message, marking the
+// messages only rendered in dry-run mode
+func (s *EventCallbackService) messageDetails(code int, content string) map[string]string {
+	details := map[string]string{"code": strconv.Itoa(code), "text": preview(content)}
+	if s.config.DryRun {
+		details["dry_run"] = "true"
 	}
+	return details
+}
 
-	return groupResponse, nil
+// jobContext attributes the actions of a job run to the scheduler
+func jobContext(name string) context.Context {
+	return audit.WithReason(audit.WithActor(context.Background(), audit.ActorScheduler), "job "+name)
+}
+
+// preview shortens a message to the length kept in the audit log
+func preview(text string) string {
+	const max = 120
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "…"
 }
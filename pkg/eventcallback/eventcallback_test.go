@@ -0,0 +1,201 @@
+package eventcallback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/eventrouter"
+	"seatalk-bot/pkg/seatalkemu"
+)
+
+// newTestService starts a SeaTalk emulator and a bot configured against it,
+// with its state in a temporary directory
+func newTestService(t *testing.T, opts ...Option) (*EventCallbackService, *seatalkemu.Server, string) {
+	t.Helper()
+	emu := seatalkemu.NewServer(seatalkemu.Options{AppID: "app", AppSecret: "secret", SigningSecret: "signing"})
+	t.Cleanup(emu.Close)
+
+	cfg := emu.Config()
+	cfg.DataDir = t.TempDir()
+	opts = append([]Option{WithScheduleFile(filepath.Join(cfg.DataDir, "schedule.csv"))}, opts...)
+	svc, err := NewEventCallbackService(cfg, opts...)
+	if err != nil {
+		t.Fatalf("NewEventCallbackService: %v", err)
+	}
+	if err := svc.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	bot := httptest.NewServer(http.HandlerFunc(svc.HandleEventCallback))
+	t.Cleanup(bot.Close)
+	return svc, emu, bot.URL
+}
+
+// groupMention builds a callback of a message mentioning the bot in the emulator group
+func groupMention(employeeCode, text string) request.NewMentionedMessageFromGroupChatEvent {
+	return request.NewMentionedMessageFromGroupChatEvent{
+		GroupID: "emulator-group",
+		Message: request.EventMessage{
+			MessageID: "msg-1",
+			Sender:    request.EventUser{EmployeeCode: employeeCode},
+			Text:      request.EventMessageText{PlainText: text},
+		},
+	}
+}
+
+func TestGroupMentionRepliesThroughEmulator(t *testing.T) {
+	_, emu, botURL := newTestService(t)
+
+	result, err := emu.FireCallback(context.Background(), botURL, request.EventTypeNewMentionedMessageFromGroupChat, groupMention("E001", "hello"))
+	if err != nil {
+		t.Fatalf("FireCallback: %v", err)
+	}
+	if result.Status != http.StatusOK {
+		t.Fatalf("callback status = %d, want %d", result.Status, http.StatusOK)
+	}
+
+	messages := emu.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1: %+v", len(messages), messages)
+	}
+	msg := messages[0]
+	if msg.Kind != seatalkemu.KindGroupChat || msg.GroupID != "emulator-group" {
+		t.Errorf("message sent to %s %q, want %s %q", msg.Kind, msg.GroupID, seatalkemu.KindGroupChat, "emulator-group")
+	}
+	if msg.Content != "Message received. How can I help?" {
+		t.Errorf("content = %q", msg.Content)
+	}
+	token, ok := strings.CutPrefix(msg.Authorization, "Bearer ")
+	if !ok || token == "" {
+		t.Errorf("Authorization = %q, want a bearer token", msg.Authorization)
+	}
+}
+
+func TestCallbackWithWrongSignatureIsRejected(t *testing.T) {
+	_, emu, botURL := newTestService(t)
+	forger := seatalkemu.New(seatalkemu.Options{AppID: "app", SigningSecret: "wrong"})
+
+	result, err := forger.FireCallback(context.Background(), botURL, request.EventTypeNewMentionedMessageFromGroupChat, groupMention("E001", "hello"))
+	if err != nil {
+		t.Fatalf("FireCallback: %v", err)
+	}
+	if result.Status == http.StatusOK {
+		t.Errorf("callback status = %d, want a rejection", result.Status)
+	}
+	if messages := emu.Messages(); len(messages) != 0 {
+		t.Errorf("got %d messages, want none", len(messages))
+	}
+}
+
+func TestRedeliveredCallbackIsAnsweredOnce(t *testing.T) {
+	_, emu, botURL := newTestService(t)
+	payload, err := json.Marshal(groupMention("E001", "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(request.EventCallbackEnvelope{
+		EventID:   "event-1",
+		EventType: request.EventTypeNewMentionedMessageFromGroupChat,
+		AppID:     "app",
+		Event:     payload,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// SeaTalk delivers the callback again when the first reply is late
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodPost, botURL, bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(eventrouter.SignatureHeader, eventrouter.Sign(body, "signing"))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("delivery %d status = %d, want %d", i+1, resp.StatusCode, http.StatusOK)
+		}
+	}
+	if messages := emu.Messages(); len(messages) != 1 {
+		t.Errorf("got %d messages, want 1: %+v", len(messages), messages)
+	}
+}
+
+func TestDryRunMessagesAreTaggedInAuditLog(t *testing.T) {
+	tests := []struct {
+		dryRun bool
+		want   string
+	}{
+		{false, ""},
+		{true, "true"},
+	}
+	for _, tt := range tests {
+		svc, _, _ := newTestService(t)
+		svc.config.DryRun = tt.dryRun
+		svc.SendMessageToGroup(request.SendMessageToBotGroupRequest{
+			GroupID: "g1",
+			Message: request.MessageGroup{Tag: "Text", Text: request.TextGroup{Format: 1, Content: "hello"}},
+		})
+		svc.SendMessageToSubscriber(request.SendMessageToBotSubscriberRequest{
+			EmployeeCode: "E001",
+			Message:      request.MessageSingle{Tag: "Text", Text: request.TextSingle{Format: 1, Content: "hello"}},
+		})
+
+		entries, err := svc.audit.Query(audit.Filter{Action: audit.ActionMessageSent})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("dry run %v: recorded %d messages, want 2", tt.dryRun, len(entries))
+		}
+		for _, e := range entries {
+			if got := e.Details["dry_run"]; got != tt.want {
+				t.Errorf("dry run %v: %s entry dry_run = %q, want %q", tt.dryRun, e.Target, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestTemplatesDirIsLoaded(t *testing.T) {
+	emu := seatalkemu.NewServer(seatalkemu.Options{AppID: "app", AppSecret: "secret", SigningSecret: "signing"})
+	t.Cleanup(emu.Close)
+	cfg := emu.Config()
+	cfg.DataDir = t.TempDir()
+	cfg.TemplatesDir = t.TempDir()
+	override := filepath.Join(cfg.TemplatesDir, "default_reply.tmpl")
+	if err := os.WriteFile(override, []byte("Hi from the override"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := NewEventCallbackService(cfg, WithScheduleFile(filepath.Join(cfg.DataDir, "schedule.csv")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot := httptest.NewServer(http.HandlerFunc(svc.HandleEventCallback))
+	t.Cleanup(bot.Close)
+	if _, err := emu.FireCallback(context.Background(), bot.URL, request.EventTypeNewMentionedMessageFromGroupChat, groupMention("E001", "hello")); err != nil {
+		t.Fatal(err)
+	}
+	if messages := emu.Messages(); len(messages) != 1 || messages[0].Content != "Hi from the override" {
+		t.Errorf("messages = %+v, want the reply of the override", messages)
+	}
+
+	// Invalid templates keep the service from starting
+	if err := os.WriteFile(override, []byte("{{.Nonexistent}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEventCallbackService(cfg); err == nil {
+		t.Error("NewEventCallbackService() with an invalid template succeeded")
+	}
+}
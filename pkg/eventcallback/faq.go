@@ -0,0 +1,158 @@
+package eventcallback
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/faq"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+)
+
+// faqSuggestions is how many close entries are suggested when no entry
+// answers a question
+const faqSuggestions = 3
+
+// faqSeparator separates the triggers of an entry from its answer
+const faqSeparator = "=>"
+
+// cmdFAQ answers recurring questions, and lets admins manage the entries
+func (s *EventCallbackService) cmdFAQ(ctx context.Context, c *command.Context) (string, error) {
+	if len(c.Args) == 0 || (strings.EqualFold(c.Args[0], "list") && len(c.Args) == 1) {
+		return s.describeFAQ(c.Locale), nil
+	}
+
+	switch action := strings.ToLower(c.Args[0]); action {
+	case "add":
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		triggers, answer, ok := splitFAQ(skipFields(c.Text, 1))
+		if !ok || len(triggers) == 0 || answer == "" {
+			return i18n.T(c.Locale, i18n.MsgFAQUsage), nil
+		}
+		e, err := s.faq.Add(triggers, answer, c.EmployeeCode)
+		if err != nil {
+			return s.faqError(c.Locale, err)
+		}
+		return i18n.T(c.Locale, i18n.MsgFAQAdded, e.ID, strings.Join(e.Triggers, "; ")), nil
+	case "edit", "remove":
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		if len(c.Args) < 2 {
+			return i18n.T(c.Locale, i18n.MsgFAQUsage), nil
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(c.Args[1], "#"))
+		if err != nil {
+			return i18n.T(c.Locale, i18n.MsgFAQUsage), nil
+		}
+		if action == "remove" {
+			if err := s.faq.Remove(id); err != nil {
+				return s.faqError(c.Locale, err)
+			}
+			return i18n.T(c.Locale, i18n.MsgFAQRemoved, id), nil
+		}
+		triggers, answer, ok := splitFAQ(skipFields(c.Text, 2))
+		if !ok {
+			return i18n.T(c.Locale, i18n.MsgFAQUsage), nil
+		}
+		e, err := s.faq.Edit(id, triggers, answer, c.EmployeeCode)
+		if err != nil {
+			return s.faqError(c.Locale, err)
+		}
+		return i18n.T(c.Locale, i18n.MsgFAQUpdated, e.ID, strings.Join(e.Triggers, "; ")), nil
+	}
+
+	// "/faq 3" shows an entry, anything else is a question
+	if id, err := strconv.Atoi(strings.TrimPrefix(c.Args[0], "#")); err == nil && len(c.Args) == 1 {
+		e, found := s.faq.Get(id)
+		if !found {
+			return i18n.T(c.Locale, i18n.MsgFAQNotFound, id), nil
+		}
+		return e.Answer, nil
+	}
+	answer, matches := s.answerFAQ(c.Text)
+	switch {
+	case answer != "":
+		return answer, nil
+	case len(matches) > 0:
+		return s.suggestFAQ(c.Locale, i18n.MsgFAQSuggest, matches), nil
+	}
+	return i18n.T(c.Locale, i18n.MsgFAQNoMatch, c.Text), nil
+}
+
+// answerFAQ returns the answer of the entry matching a question, or the
+// closest entries when none matches well enough
+func (s *EventCallbackService) answerFAQ(question string) (string, []faq.Match) {
+	matches := s.faq.Search(question, faq.SuggestScore)
+	if len(matches) > 0 && matches[0].Score >= faq.AnswerScore {
+		return matches[0].Entry.Answer, nil
+	}
+	return "", matches
+}
+
+// suggestFAQ lists the closest entries under the given header
+func (s *EventCallbackService) suggestFAQ(locale, header string, matches []faq.Match) string {
+	lines := []string{i18n.T(locale, header)}
+	for i, m := range matches {
+		if i == faqSuggestions {
+			break
+		}
+		lines = append(lines, i18n.T(locale, i18n.MsgFAQEntry, m.Entry.ID, m.Entry.Triggers[0]))
+	}
+	lines = append(lines, i18n.T(locale, i18n.MsgFAQSuggestFooter))
+	return strings.Join(lines, "\n")
+}
+
+// describeFAQ lists the entries with their triggers
+func (s *EventCallbackService) describeFAQ(locale string) string {
+	entries := s.faq.Entries()
+	if len(entries) == 0 {
+		return i18n.T(locale, i18n.MsgFAQEmpty)
+	}
+	lines := []string{i18n.T(locale, i18n.MsgFAQHeader)}
+	for _, e := range entries {
+		lines = append(lines, i18n.T(locale, i18n.MsgFAQEntry, e.ID, strings.Join(e.Triggers, "; ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// faqError turns the errors caused by the sender into replies
+func (s *EventCallbackService) faqError(locale string, err error) (string, error) {
+	switch {
+	case strings.HasPrefix(err.Error(), constants.ErrFAQNotFound):
+		return i18n.T(locale, i18n.MsgFAQNotFound, strings.TrimPrefix(err.Error(), constants.ErrFAQNotFound+": ")), nil
+	case strings.HasPrefix(err.Error(), constants.ErrInvalidFAQ):
+		return i18n.T(locale, i18n.MsgFAQInvalid, strings.TrimPrefix(err.Error(), constants.ErrInvalidFAQ+": ")), nil
+	}
+	return "", err
+}
+
+// splitFAQ splits "trigger; trigger => answer" into the triggers and the
+// answer, either of which may be empty
+func splitFAQ(text string) ([]string, string, bool) {
+	triggers, answer, ok := strings.Cut(text, faqSeparator)
+	if !ok {
+		return nil, "", false
+	}
+	return faq.ParseTriggers(triggers), strings.TrimSpace(answer), true
+}
+
+// skipFields returns text after its first n whitespace-separated fields,
+// keeping the line breaks of the rest
+func skipFields(text string, n int) string {
+	for i := 0; i < n; i++ {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		end := strings.IndexFunc(text, unicode.IsSpace)
+		if end < 0 {
+			return ""
+		}
+		text = text[end:]
+	}
+	return strings.TrimSpace(text)
+}
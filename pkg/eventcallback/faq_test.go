@@ -0,0 +1,89 @@
+package eventcallback
+
+import (
+	"reflect"
+	"testing"
+
+	"seatalk-bot/pkg/command"
+)
+
+func TestParseFAQCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		skip     int
+		triggers []string
+		answer   string
+		ok       bool
+	}{
+		{
+			name:     "add",
+			text:     "/faq add deploy staging; staging deployment => Run the deploy pipeline",
+			skip:     1,
+			triggers: []string{"deploy staging", "staging deployment"},
+			answer:   "Run the deploy pipeline",
+			ok:       true,
+		},
+		{
+			name:     "multi-line answer",
+			text:     "/faq add  re:^where .*docs  =>  See the wiki:\n- onboarding\n- runbooks",
+			skip:     1,
+			triggers: []string{"re:^where .*docs"},
+			answer:   "See the wiki:\n- onboarding\n- runbooks",
+			ok:       true,
+		},
+		{
+			name:     "edit keeping the triggers",
+			text:     "/faq edit 3 => New answer",
+			skip:     2,
+			triggers: nil,
+			answer:   "New answer",
+			ok:       true,
+		},
+		{
+			name:     "edit keeping the answer",
+			text:     "/faq edit #3 a; b =>",
+			skip:     2,
+			triggers: []string{"a", "b"},
+			answer:   "",
+			ok:       true,
+		},
+		{
+			name: "no separator",
+			text: "/faq add deploy staging",
+			skip: 1,
+		},
+		{
+			name: "nothing after the action",
+			text: "/faq add",
+			skip: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggers, answer, ok := splitFAQ(skipFields(command.Remainder(tt.text), tt.skip))
+			if ok != tt.ok || answer != tt.answer || !reflect.DeepEqual(triggers, tt.triggers) {
+				t.Errorf("got %q, %q, %v; want %q, %q, %v", triggers, answer, ok, tt.triggers, tt.answer, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSkipFields(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"add a => b", 1, "a => b"},
+		{"  edit\t12   a\nb", 2, "a\nb"},
+		{"add", 1, ""},
+		{"add ", 1, ""},
+		{"a b", 0, "a b"},
+	}
+	for _, tt := range tests {
+		if got := skipFields(tt.text, tt.n); got != tt.want {
+			t.Errorf("skipFields(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/eventrouter"
+	"seatalk-bot/pkg/groups"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/mention"
+	"seatalk-bot/pkg/roles"
+)
+
+// groupJobs lists the scheduled jobs that post to groups
+var groupJobs = []string{constants.JobStockInventoryPIC, constants.JobReturnRefundReminder}
+
+// seedLegacyGroup makes the group of REGRESSION_GROUP_ID receive every group
+// job, as it did before groups had their own settings, until some group is
+// configured to receive jobs
+func (s *EventCallbackService) seedLegacyGroup() error {
+	if s.config.RegressionGroupID == "" {
+		return nil
+	}
+	for _, g := range s.groups.List() {
+		if len(g.Jobs) > 0 {
+			return nil
+		}
+	}
+	return s.groups.Update(s.config.RegressionGroupID, func(g *groups.Group) error {
+		g.Active = true
+		g.Jobs = append([]string(nil), groupJobs...)
+		return nil
+	})
+}
+
+// migrateGroupAdmins turns the group admins saved before roles existed into
+// admin role bindings
+func (s *EventCallbackService) migrateGroupAdmins() error {
+	for _, g := range s.groups.List() {
+		if len(g.Admins) == 0 {
+			continue
+		}
+		for _, code := range g.Admins {
+			if s.roles.Bound(g.GroupID, code).AtLeast(roles.Admin) {
+				continue
+			}
+			if err := s.roles.Grant(g.GroupID, code, roles.Admin); err != nil {
+				return err
+			}
+		}
+		err := s.groups.Update(g.GroupID, func(g *groups.Group) error {
+			g.Admins = nil
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// broadcast posts the output of render to every group the job posts to. The
+// message is held back for groups in their quiet hours.
+func (s *EventCallbackService) broadcast(job string, render func(g groups.Group, locale string) (string, error)) error {
+	return s.broadcastTo(job, s.groups.Subscribed(job), render)
+}
+
+// broadcastTo posts the output of render to the given groups on behalf of
+// the job, holding the message back for groups in their quiet hours
+func (s *EventCallbackService) broadcastTo(job string, targets []groups.Group, render func(g groups.Group, locale string) (string, error)) error {
+	var errs []error
+	for _, g := range targets {
+		content, err := render(g, s.locales.GroupLocale(g.GroupID))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if g.Quiet(s.clock.Now()) {
+			slog.Info("holding message during quiet hours", "job", job, "group_id", g.GroupID)
+			errs = append(errs, s.held.Hold(groups.HeldMessage{GroupID: g.GroupID, Job: job, Content: content, HeldAt: s.clock.Now()}))
+			continue
+		}
+		errs = append(errs, s.sendToGroup(g.GroupID, content))
+	}
+	return errors.Join(errs...)
+}
+
+// releaseHeld sends the held messages of groups whose quiet hours are over
+func (s *EventCallbackService) releaseHeld() error {
+	now := s.clock.Now()
+	ready, err := s.held.Take(func(msg groups.HeldMessage) bool {
+		g, ok := s.groups.Get(msg.GroupID)
+		return !ok || !g.Active || !g.Quiet(now)
+	})
+	if err != nil {
+		return err
+	}
+	if len(ready) > 0 {
+		slog.Info("releasing held messages", "count", len(ready))
+	}
+
+	var errs []error
+	for _, msg := range ready {
+		if g, ok := s.groups.Get(msg.GroupID); !ok || !g.Active {
+			slog.Warn("dropping held message for a group the bot left", "job", msg.Job, "group_id", msg.GroupID)
+			continue
+		}
+		if err := s.sendToGroup(msg.GroupID, msg.Content); err != nil {
+			errs = append(errs, err, s.held.Hold(msg))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendToGroup posts a text message to a group
+func (s *EventCallbackService) sendToGroup(groupID, content string) error {
+	_, err := s.SendMessageToGroup(request.SendMessageToBotGroupRequest{
+		GroupID: groupID,
+		Message: request.MessageGroup{
+			Tag: "Text",
+			Text: request.TextGroup{
+				Format:  1,
+				Content: content,
+			},
+		},
+	})
+	return err
+}
+
+// handleBotAdded registers a group the bot joined, making the inviter its
+// first admin
+func (s *EventCallbackService) handleBotAdded(ctx context.Context, evt *eventrouter.Event, event request.BotAddedToGroupChatEvent) (interface{}, error) {
+	err := s.groups.Update(event.Group.GroupID, func(g *groups.Group) error {
+		g.Active = true
+		g.Name = event.Group.GroupName
+		g.CanNotifyWithAtAll = event.Group.GroupSettings.CanNotifyWithAtAll
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Inviter.EmployeeCode != "" && !s.roles.HasAdmins(event.Group.GroupID) {
+		return nil, s.roles.Grant(event.Group.GroupID, event.Inviter.EmployeeCode, roles.Admin)
+	}
+	return nil, nil
+}
+
+// handleBotRemoved deactivates a group the bot left, keeping its settings
+func (s *EventCallbackService) handleBotRemoved(ctx context.Context, evt *eventrouter.Event, event request.BotRemovedFromGroupChatEvent) (interface{}, error) {
+	err := s.groups.Update(event.Group.GroupID, func(g *groups.Group) error {
+		g.Active = false
+		return nil
+	})
+	return nil, err
+}
+
+// cmdConfig shows the settings of the group, or changes them for admins
+func (s *EventCallbackService) cmdConfig(ctx context.Context, c *command.Context) (string, error) {
+	if !c.InGroup() {
+		return i18n.T(c.Locale, i18n.MsgConfigGroupOnly), nil
+	}
+	g, err := s.groups.Ensure(c.GroupID)
+	if err != nil {
+		return "", err
+	}
+	if len(c.Args) == 0 {
+		return s.describeGroup(c.Locale, g), nil
+	}
+	if err := command.Require(c, roles.Admin); err != nil {
+		return "", err
+	}
+
+	locale := c.Locale
+	var apply func(g *groups.Group) error
+	setting, args := strings.ToLower(c.Args[0]), c.Args[1:]
+	switch {
+	case setting == "jobs" && len(args) == 2 && (args[0] == "add" || args[0] == "remove"):
+		job := args[1]
+		if !contains(groupJobs, job) {
+			return i18n.T(c.Locale, i18n.MsgConfigUnknownJob, job, strings.Join(groupJobs, ", ")), nil
+		}
+		apply = func(g *groups.Group) error {
+			if args[0] == "add" {
+				g.Jobs = groups.AddItem(g.Jobs, job)
+			} else {
+				g.Jobs = groups.RemoveItem(g.Jobs, job)
+			}
+			return nil
+		}
+
+	case setting == "timezone" && len(args) == 1:
+		if _, err := time.LoadLocation(args[0]); err != nil || args[0] == "" || strings.EqualFold(args[0], "local") {
+			return i18n.T(c.Locale, i18n.MsgConfigInvalidTimezone, args[0]), nil
+		}
+		apply = func(g *groups.Group) error {
+			g.Timezone = args[0]
+			return nil
+		}
+
+	case setting == "quiet" && len(args) == 1:
+		quiet := args[0]
+		if strings.EqualFold(quiet, "off") {
+			quiet = ""
+		} else if _, err := groups.ParseQuietHours(quiet); err != nil {
+			return i18n.T(c.Locale, i18n.MsgConfigInvalidQuietHours, quiet), nil
+		}
+		apply = func(g *groups.Group) error {
+			g.QuietHours = quiet
+			return nil
+		}
+
+	case setting == "language" && len(args) == 1:
+		code, ok := i18n.Normalize(args[0])
+		if !ok {
+			return i18n.T(c.Locale, i18n.MsgLangUnsupported, args[0], strings.Join(i18n.Supported(), ", ")), nil
+		}
+		if err := s.locales.SetGroupLocale(c.GroupID, code); err != nil {
+			return "", err
+		}
+		locale = code
+		apply = func(g *groups.Group) error { return nil }
+
+	case setting == "answers" && len(args) == 1 && (strings.EqualFold(args[0], "on") || strings.EqualFold(args[0], "off")):
+		enable := strings.EqualFold(args[0], "on")
+		if enable && s.answerer == nil {
+			return i18n.T(c.Locale, i18n.MsgConfigAnswersUnavailable), nil
+		}
+		apply = func(g *groups.Group) error {
+			g.Answers = enable
+			return nil
+		}
+
+	default:
+		return i18n.T(c.Locale, i18n.MsgConfigUsage), nil
+	}
+
+	if err := s.groups.Update(c.GroupID, apply); err != nil {
+		return "", err
+	}
+
+	g, _ = s.groups.Get(c.GroupID)
+	return i18n.T(locale, i18n.MsgConfigUpdated) + "\n" + s.describeGroup(locale, g), nil
+}
+
+// describeGroup lists the settings of a group
+func (s *EventCallbackService) describeGroup(locale string, g groups.Group) string {
+	list := func(items []string) string {
+		if len(items) == 0 {
+			return i18n.T(locale, i18n.MsgNone)
+		}
+		return strings.Join(items, ", ")
+	}
+
+	var admins []string
+	for _, b := range s.roles.Bindings(g.GroupID) {
+		admins = append(admins, s.displayEmployee(b.EmployeeCode)+" - "+b.Role.String())
+	}
+
+	quiet := g.QuietHours
+	if quiet == "" {
+		quiet = i18n.T(locale, i18n.MsgNone)
+	}
+	answers := i18n.T(locale, i18n.MsgOff)
+	if g.Answers && s.answerer != nil {
+		answers = i18n.T(locale, i18n.MsgOn)
+	}
+	return i18n.T(locale, i18n.MsgConfigSummary,
+		list(g.Jobs),
+		g.Location().String(),
+		i18n.LanguageName(locale, s.locales.GroupLocale(g.GroupID)),
+		quiet,
+		answers,
+		list(admins),
+	)
+}
+
+// displayEmployee returns the name and employee code of an employee
+func (s *EventCallbackService) displayEmployee(code string) string {
+	entry, err := s.directory.Lookup(code)
+	if err != nil || entry.Name == "" {
+		return code
+	}
+	return entry.Name + " (" + code + ")"
+}
+
+// employeeCodes resolves mentioned users and employee code arguments to
+// employee codes, returning the first user that could not be resolved
+func (s *EventCallbackService) employeeCodes(mentions []request.MentionedUser, args []string) ([]string, string) {
+	var codes []string
+	for _, user := range mentions {
+		entry, found := s.directory.BySeatalkID(user.SeatalkID)
+		if !found || entry.EmployeeCode == "" {
+			return nil, user.Username
+		}
+		codes = append(codes, entry.EmployeeCode)
+	}
+	for _, arg := range args {
+		entry, err := s.resolveMember(arg)
+		if err != nil || entry.EmployeeCode == "" {
+			return nil, arg
+		}
+		codes = append(codes, entry.EmployeeCode)
+	}
+	return codes, ""
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionAll returns the @all mention tag of a group, or an empty string when
+// the group does not let the bot notify everyone
+func mentionAll(g groups.Group) string {
+	tag, err := mention.All(g.CanNotifyWithAtAll)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
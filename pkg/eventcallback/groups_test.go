@@ -0,0 +1,117 @@
+package eventcallback
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/groups"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/seatalkemu"
+)
+
+func TestQuietHoursHoldMessages(t *testing.T) {
+	// 23:00 in Jakarta
+	clk := clock.NewFake(time.Date(2026, time.October, 20, 16, 0, 0, 0, time.UTC))
+	svc, emu, _ := newTestService(t, WithClock(clk))
+	for _, id := range []string{"emulator-group", "left-group"} {
+		err := svc.groups.Update(id, func(g *groups.Group) error {
+			g.Active = true
+			g.Jobs = []string{"announce"}
+			g.QuietHours = "22:00-07:00"
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	render := func(g groups.Group, locale string) (string, error) {
+		return "hello " + g.GroupID, nil
+	}
+
+	if err := svc.broadcast("announce", render); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(emu.Messages()); got != 0 {
+		t.Fatalf("sent %d messages during quiet hours", got)
+	}
+	if got := svc.held.Len(); got != 2 {
+		t.Fatalf("held %d messages, want 2", got)
+	}
+
+	// The bot is removed from a group before its messages are released
+	svc.groups.Update("left-group", func(g *groups.Group) error {
+		g.Active = false
+		return nil
+	})
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		sent    int
+		held    int
+	}{
+		{"still quiet", 7*time.Hour + 59*time.Minute, 0, 1},
+		{"quiet hours over", time.Minute, 1, 0},
+	}
+	for _, step := range steps {
+		clk.Advance(step.advance)
+		if err := svc.releaseHeld(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := len(emu.Messages()); got != step.sent {
+			t.Errorf("%s: sent %d messages, want %d", step.name, got, step.sent)
+		}
+		if got := svc.held.Len(); got != step.held {
+			t.Errorf("%s: %d messages held, want %d", step.name, got, step.held)
+		}
+	}
+	if messages := emu.Messages(); len(messages) == 1 && (messages[0].GroupID != "emulator-group" || messages[0].Content != "hello emulator-group") {
+		t.Errorf("released %+v", messages[0])
+	}
+}
+
+func TestMigrationsOnlyRunWhenAsked(t *testing.T) {
+	emu := seatalkemu.NewServer(seatalkemu.Options{AppID: "app", AppSecret: "secret", SigningSecret: "signing"})
+	t.Cleanup(emu.Close)
+	cfg := emu.Config()
+	cfg.DataDir = t.TempDir()
+	groupsFile := filepath.Join(cfg.DataDir, constants.GroupsFile)
+	legacy := []byte(`[{"group_id":"g1","active":true,"admins":["E001"]}]`)
+	if err := os.WriteFile(groupsFile, legacy, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := NewEventCallbackService(cfg, WithScheduleFile(filepath.Join(cfg.DataDir, "schedule.csv")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(cfg.DataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("creating the service wrote %d files, want none besides %s", len(entries)-1, constants.GroupsFile)
+	}
+	if data, _ := os.ReadFile(groupsFile); string(data) != string(legacy) {
+		t.Errorf("creating the service rewrote %s: %s", constants.GroupsFile, data)
+	}
+
+	if err := svc.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.roles.Bound("g1", "E001"); got != roles.Admin {
+		t.Errorf("legacy group admin bound as %s, want %s", got, roles.Admin)
+	}
+	if g, _ := svc.groups.Get("g1"); g.Admins != nil {
+		t.Errorf("legacy admins kept: %q", g.Admins)
+	}
+	// The legacy group receives the group jobs while no group is configured for any
+	if g, _ := svc.groups.Get(cfg.RegressionGroupID); !g.Active || !reflect.DeepEqual(g.Jobs, groupJobs) {
+		t.Errorf("legacy group = %+v, want it active with every group job", g)
+	}
+}
@@ -0,0 +1,156 @@
+package eventcallback
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"seatalk-bot/internal/config"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/groups"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/templates"
+)
+
+// Kinds of schedule alerts, remembered separately so that each is only sent
+// once per distinct report
+const (
+	alertLint   = "lint"
+	alertRunway = "runway"
+)
+
+// runwayPeriods returns how many periods after the current week should be scheduled
+func (s *EventCallbackService) runwayPeriods() int {
+	if s.config.RunwayPeriods > 0 {
+		return s.config.RunwayPeriods
+	}
+	return schedule.DefaultRunway
+}
+
+// lintOptions returns the lint options of a rotation, following its period
+// and slots when the rotation engine is configured
+func (s *EventCallbackService) lintOptions(name string) schedule.LintOptions {
+	opts := schedule.LintOptions{Runway: s.runwayPeriods()}
+	if rot := s.rotations[name]; rot != nil && rot.Configured() {
+		cfg := rot.Config()
+		opts.PeriodDays = cfg.Period()
+		opts.PerPeriod = cfg.Slots()
+	}
+	return opts
+}
+
+// checkSchedule lints the schedule of a rotation before a job uses it. The
+// global admins are told when the schedule is invalid, and admins or the
+// groups following the rotation when it is about to run out.
+func (s *EventCallbackService) checkSchedule(store *schedule.Store) {
+	issues, err := store.Lint(s.lintOptions(store.Name()))
+	if err != nil {
+		slog.Warn("failed to lint schedule", "rotation", store.Name(), "error", err)
+		return
+	}
+	s.alertLint(store, issues)
+	s.alertRunway(store)
+}
+
+// alertLint tells the global admins about the errors of a schedule file
+func (s *EventCallbackService) alertLint(store *schedule.Store, issues []schedule.Issue) {
+	data := templates.ScheduleIssuesData{
+		Rotation: store.Name(),
+		File:     filepath.Base(store.Filename()),
+		Issues:   issues,
+	}
+	invalid := false
+	var report strings.Builder
+	for _, issue := range issues {
+		report.WriteString(issue.String() + "\n")
+		if issue.Severity == schedule.SeverityError {
+			data.Errors++
+			// A schedule that ran out is reported by the runway alert
+			invalid = invalid || issue.Code != schedule.CodePast
+		}
+	}
+	if !s.shouldAlert(alertLint, store.Name(), invalid, report.String()) {
+		return
+	}
+
+	slog.Warn("schedule is invalid", "rotation", store.Name(), "errors", data.Errors, "issues", len(issues))
+	s.notifyAdmins(templates.ScheduleIssues, data)
+}
+
+// alertRunway warns when no PIC is scheduled this week or fewer periods than
+// the runway are scheduled after it, suggesting to extend the rotation
+func (s *EventCallbackService) alertRunway(store *schedule.Store) {
+	schedules, err := store.Read()
+	if err != nil {
+		// Unreadable schedules are reported by the lint alert
+		return
+	}
+	cov := schedule.CoverageOf(schedules, s.clock.Now())
+	required := s.runwayPeriods()
+	rot := s.rotations[store.Name()]
+	data := templates.ScheduleRunwayData{
+		Rotation:   store.Name(),
+		Current:    cov.Current,
+		Periods:    cov.Periods,
+		Required:   required,
+		Last:       cov.Last,
+		Configured: rot != nil && rot.Configured(),
+	}
+	// Sent again when the number of periods left drops, not when entries are
+	// added beyond the runway
+	short := cov.Periods < required
+	report := fmt.Sprintf("current=%t", cov.Current > 0)
+	if short {
+		report += fmt.Sprintf(" periods=%d", cov.Periods)
+	}
+	if !s.shouldAlert(alertRunway, store.Name(), short || cov.Current == 0, report) {
+		return
+	}
+
+	slog.Warn("schedule is running out", "rotation", store.Name(), "current", cov.Current, "periods", cov.Periods, "required", required)
+	if s.config.RunwayAlert != config.RunwayAlertGroup {
+		s.notifyAdmins(templates.ScheduleRunway, data)
+	}
+	if s.config.RunwayAlert == config.RunwayAlertGroup || s.config.RunwayAlert == config.RunwayAlertBoth {
+		err := s.broadcast(constants.JobStockInventoryPIC, func(g groups.Group, locale string) (string, error) {
+			return s.templates.Render(locale, templates.ScheduleRunway, data)
+		})
+		if err != nil {
+			slog.Warn("failed to post runway warning", "rotation", store.Name(), "error", err)
+		}
+	}
+}
+
+// shouldAlert reports whether an alert is due: active and different from the
+// last report sent for the rotation. Inactive alerts are forgotten so that
+// they are sent again when the problem comes back.
+func (s *EventCallbackService) shouldAlert(kind, rotation string, active bool, report string) bool {
+	key := kind + ":" + rotation
+	s.alertsMu.Lock()
+	defer s.alertsMu.Unlock()
+	if !active {
+		delete(s.alerts, key)
+		return false
+	}
+	if s.alerts[key] == report {
+		return false
+	}
+	s.alerts[key] = report
+	return true
+}
+
+// notifyAdmins sends a template to every global admin and owner
+func (s *EventCallbackService) notifyAdmins(name string, data interface{}) {
+	notified := false
+	for _, b := range s.roles.Bindings(roles.Global) {
+		if b.Role.AtLeast(roles.Admin) {
+			notified = true
+			s.notify(b.EmployeeCode, name, data)
+		}
+	}
+	if !notified {
+		slog.Warn("no admin to notify", "template", name)
+	}
+}
@@ -0,0 +1,95 @@
+package eventcallback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/seatalkemu"
+)
+
+func TestShouldAlert(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	steps := []struct {
+		name     string
+		kind     string
+		rotation string
+		active   bool
+		report   string
+		want     bool
+	}{
+		{"first report", alertRunway, "stock", true, "periods=1", true},
+		{"same report", alertRunway, "stock", true, "periods=1", false},
+		{"other kind", alertLint, "stock", true, "periods=1", true},
+		{"other rotation", alertRunway, "release", true, "periods=1", true},
+		{"report changes", alertRunway, "stock", true, "periods=0", true},
+		{"problem solved", alertRunway, "stock", false, "", false},
+		{"problem comes back", alertRunway, "stock", true, "periods=0", true},
+		{"unchanged after it came back", alertRunway, "stock", true, "periods=0", false},
+	}
+	for _, step := range steps {
+		if got := svc.shouldAlert(step.kind, step.rotation, step.active, step.report); got != step.want {
+			t.Errorf("%s: shouldAlert() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestRunwayAlertIsSentOnce(t *testing.T) {
+	// Wednesday 21 October in Jakarta
+	clk := clock.NewFake(time.Date(2026, time.October, 21, 2, 0, 0, 0, time.UTC))
+	svc, emu, _ := newTestService(t, WithClock(clk))
+	if err := svc.roles.Grant(roles.Global, "E001", roles.Admin); err != nil {
+		t.Fatal(err)
+	}
+	store, err := svc.schedules.Get(constants.StockInventoryRotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// turns returns one turn a week starting on Tuesday 20 October
+	turns := func(weeks int) []schedule.Schedule {
+		var schedules []schedule.Schedule
+		for i := 0; i < weeks; i++ {
+			date := time.Date(2026, time.October, 20+7*i, 0, 0, 0, 0, time.UTC)
+			schedules = append(schedules, schedule.Schedule{PIC: "PIC", Date: date, Email: "pic@example.com"})
+		}
+		return schedules
+	}
+
+	steps := []struct {
+		name      string
+		schedules []schedule.Schedule
+		advance   time.Duration
+		// alerts is how many runway warnings the admin has received after the step
+		alerts int
+	}{
+		{"one period left", turns(2), 0, 1},
+		{"checked again", nil, 0, 1},
+		{"extended beyond the runway", turns(3), 0, 1},
+		{"a week later", nil, 7 * 24 * time.Hour, 2},
+		{"checked again later", nil, time.Hour, 2},
+		{"no PIC this week", nil, 14 * 24 * time.Hour, 3},
+	}
+	for _, step := range steps {
+		if step.schedules != nil {
+			if err := store.Replace(context.Background(), step.schedules); err != nil {
+				t.Fatal(err)
+			}
+		}
+		clk.Advance(step.advance)
+		svc.checkSchedule(store)
+
+		alerts := 0
+		for _, msg := range emu.Messages() {
+			if msg.Kind == seatalkemu.KindSingleChat && msg.EmployeeCode == "E001" {
+				alerts++
+			}
+		}
+		if alerts != step.alerts {
+			t.Errorf("%s: sent %d alerts, want %d", step.name, alerts, step.alerts)
+		}
+	}
+}
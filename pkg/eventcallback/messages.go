@@ -0,0 +1,190 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/eventrouter"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/templates"
+)
+
+// handleSubscriberMessage replies to a message sent to the bot directly
+func (s *EventCallbackService) handleSubscriberMessage(ctx context.Context, evt *eventrouter.Event, event request.MessageFromBotSubscriberEvent) (interface{}, error) {
+	c := &command.Context{
+		EmployeeCode: event.EmployeeCode,
+		SeatalkID:    event.SeatalkID,
+		MessageID:    event.Message.MessageID,
+		Locale:       s.locales.UserLocale(event.EmployeeCode),
+	}
+	// Direct messages carry the sender next to the message rather than in it
+	msg := event.Message
+	msg.Sender = request.EventUser{SeatalkID: event.SeatalkID, EmployeeCode: event.EmployeeCode}
+	s.learn(msg)
+
+	content, err := s.respond(ctx, c, messageText(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.SendMessageToBotSubscriberRequest{
+		EmployeeCode: event.EmployeeCode,
+		Message: request.MessageSingle{
+			Tag: "Text",
+			Text: request.TextSingle{
+				Format:  1,
+				Content: content,
+			},
+		},
+	}
+	if _, err := s.SendMessageToSubscriber(req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// handleGroupMention replies to a message mentioning the bot in a group
+func (s *EventCallbackService) handleGroupMention(ctx context.Context, evt *eventrouter.Event, event request.NewMentionedMessageFromGroupChatEvent) (interface{}, error) {
+	c := &command.Context{
+		GroupID:      event.GroupID,
+		EmployeeCode: event.Message.Sender.EmployeeCode,
+		SeatalkID:    event.Message.Sender.SeatalkID,
+		MessageID:    event.Message.MessageID,
+		ThreadID:     event.Message.ThreadID,
+		Locale:       s.locales.GroupLocale(event.GroupID),
+		Mentions:     command.ArgMentions(messageText(event.Message), event.Message.Text.MentionedList),
+	}
+	s.learn(event.Message)
+	if _, err := s.groups.Ensure(event.GroupID); err != nil {
+		slog.Warn("failed to register group", "group_id", event.GroupID, "error", err)
+	}
+
+	content, err := s.respond(ctx, c, command.StripMentions(messageText(event.Message), mentionedNames(event.Message)))
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.SendMessageToBotGroupRequest{
+		GroupID: event.GroupID,
+		Message: request.MessageGroup{
+			Tag: "Text",
+			Text: request.TextGroup{
+				Format:  1,
+				Content: content,
+			},
+			ThreadID: event.Message.ThreadID,
+		},
+	}
+	if _, err := s.SendMessageToGroup(req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// respond builds the reply to a message and records both in the history of
+// their conversation
+func (s *EventCallbackService) respond(ctx context.Context, c *command.Context, text string) (string, error) {
+	content, err := s.reply(ctx, c, text)
+	if err != nil {
+		return "", err
+	}
+	s.remember(c, text, content)
+	return content, nil
+}
+
+// reply builds the reply to a message: the output of the command it invokes,
+// the FAQ entry answering free text, the answer of the answer backend
+// followed by the closest FAQ entries, those entries alone, or the default
+// reply
+func (s *EventCallbackService) reply(ctx context.Context, c *command.Context, text string) (string, error) {
+	name, args, ok := command.Parse(text)
+	if !ok {
+		answer, matches := s.answerFAQ(text)
+		if answer != "" {
+			return answer, nil
+		}
+		sender := s.directory.Resolve(request.EventUser{SeatalkID: c.SeatalkID, EmployeeCode: c.EmployeeCode})
+		if s.answersEnabled(c) {
+			if reply, answered := s.answerQuestion(ctx, c, sender, text); answered {
+				// Loosely matching entries follow the answer rather than replace it
+				if len(matches) > 0 {
+					reply += "\n\n" + s.suggestFAQ(c.Locale, i18n.MsgFAQRelated, matches)
+				}
+				return reply, nil
+			}
+		}
+		if len(matches) > 0 {
+			return s.suggestFAQ(c.Locale, i18n.MsgFAQSuggest, matches), nil
+		}
+		return s.templates.Render(c.Locale, templates.DefaultReply, templates.ReplyData{Text: text, Sender: sender})
+	}
+	c.Name, c.Args, c.Text = name, args, command.Remainder(text)
+	c.Role = s.roles.Effective(c.GroupID, c.EmployeeCode)
+	if s.config.SigningSecret == "" {
+		// Unsigned callbacks can claim any sender, so they get no privileges
+		c.Role = roles.Member
+	}
+
+	if _, found := s.commands.Lookup(name); !found {
+		return i18n.T(c.Locale, i18n.MsgUnknownCommand, name), nil
+	}
+
+	// Attribute the changes made by the command to its sender
+	ctx = audit.WithReason(audit.WithActor(ctx, audit.Employee(c.EmployeeCode)), "/"+strings.TrimSpace(name+" "+strings.Join(args, " ")))
+	entry := audit.Entry{
+		Action:       audit.ActionCommand,
+		Target:       name,
+		GroupID:      c.GroupID,
+		EmployeeCode: c.EmployeeCode,
+		MessageID:    c.MessageID,
+		Details:      map[string]string{"role": c.Role.String()},
+	}
+
+	reply, err := s.commands.Dispatch(ctx, c)
+	var denied *command.PermissionError
+	if errors.As(err, &denied) {
+		entry.Action, entry.Outcome = audit.ActionCommandDenied, audit.OutcomeDenied
+		entry.Details["required_role"] = denied.Required.String()
+		s.audit.Record(ctx, entry)
+		slog.Warn("command denied", "command", name, "group_id", c.GroupID, "employee_code", c.EmployeeCode,
+			"role", c.Role.String(), "required_role", denied.Required.String())
+		return i18n.T(c.Locale, i18n.MsgPermissionDenied, denied.Command, denied.Required.String()), nil
+	}
+	s.audit.RecordResult(ctx, entry, err)
+	if err != nil {
+		slog.Warn("command failed", "command", name, "group_id", c.GroupID, "employee_code", c.EmployeeCode, "error", err)
+		return i18n.T(c.Locale, i18n.MsgCommandFailed, name, err), nil
+	}
+	return reply, nil
+}
+
+// learn records the sender and the mentioned users of a message in the
+// directory. Failing to persist them does not prevent the reply.
+func (s *EventCallbackService) learn(msg request.EventMessage) {
+	if err := s.directory.LearnMessage(msg); err != nil {
+		slog.Warn("failed to update the directory", "message_id", msg.MessageID, "error", err)
+	}
+}
+
+// messageText returns the plain text of a callback message
+func messageText(msg request.EventMessage) string {
+	if msg.Text.PlainText != "" {
+		return msg.Text.PlainText
+	}
+	return msg.Text.Content
+}
+
+// mentionedNames returns the usernames mentioned in a callback message
+func mentionedNames(msg request.EventMessage) []string {
+	names := make([]string, 0, len(msg.Text.MentionedList))
+	for _, mentioned := range msg.Text.MentionedList {
+		names = append(names, mentioned.Username)
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package eventcallback
+
+import (
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/pkg/answer"
+	"seatalk-bot/pkg/seatalk"
+	"seatalk-bot/pkg/templates"
+)
+
+// options holds the dependencies that can be replaced when creating the service
+type options struct {
+	clock        clock.Clock
+	sender       seatalk.Sender
+	scheduleFile string
+	templates    *templates.Renderer
+	answerer     answer.Answerer
+}
+
+// Option customizes an EventCallbackService
+type Option func(*options)
+
+// WithClock makes the service and its schedules read the time from clk
+func WithClock(clk clock.Clock) Option {
+	return func(o *options) { o.clock = clk }
+}
+
+// WithSender replaces the sender selected by the configuration
+func WithSender(sender seatalk.Sender) Option {
+	return func(o *options) { o.sender = sender }
+}
+
+// WithScheduleFile backs the stock inventory rotation with another file
+func WithScheduleFile(filename string) Option {
+	return func(o *options) { o.scheduleFile = filename }
+}
+
+// WithTemplates renders messages with the given templates instead of those
+// loaded from the configured templates directory
+func WithTemplates(renderer *templates.Renderer) Option {
+	return func(o *options) { o.templates = renderer }
+}
+
+// WithAnswerer answers free-form questions with a instead of the configured backend
+func WithAnswerer(a answer.Answerer) Option {
+	return func(o *options) { o.answerer = a }
+}
@@ -0,0 +1,218 @@
+package eventcallback
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+	"seatalk-bot/pkg/calendar"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/groups"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/templates"
+)
+
+// picHistoryLimit is how many versions /pic history shows by default
+const picHistoryLimit = 10
+
+// cmdPIC shows this week's PICs and the rotation history, and lets admins
+// extend the rotation, undo rotation changes or restore an earlier version
+func (s *EventCallbackService) cmdPIC(ctx context.Context, c *command.Context) (string, error) {
+	store, err := s.schedules.Get(constants.StockInventoryRotation)
+	if err != nil {
+		return "", err
+	}
+
+	if len(c.Args) == 0 {
+		return s.describePICs(c.Locale, store)
+	}
+
+	switch action := strings.ToLower(c.Args[0]); {
+	case action == "history" && len(c.Args) <= 2:
+		limit := picHistoryLimit
+		if len(c.Args) == 2 {
+			if limit, err = strconv.Atoi(c.Args[1]); err != nil || limit < 1 {
+				return i18n.T(c.Locale, i18n.MsgPICUsage), nil
+			}
+		}
+		return s.describeHistory(c, store, limit), nil
+	case action == "ical" && len(c.Args) == 1:
+		return s.describeCalendar(c, store.Name()), nil
+	case action == "extend" && len(c.Args) == 1:
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		return s.extendPICs(ctx, c, store)
+	case action == "undo" && len(c.Args) == 1:
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		restored, err := store.Undo(ctx)
+		if err != nil && strings.HasPrefix(err.Error(), constants.ErrNothingToUndo) {
+			return i18n.T(c.Locale, i18n.MsgPICNothingToUndo), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return s.announceRestore(c, store.Name(), restored)
+	case action == "restore" && len(c.Args) == 2:
+		if err := command.Require(c, roles.Admin); err != nil {
+			return "", err
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(c.Args[1]), "v"))
+		if err != nil {
+			return i18n.T(c.Locale, i18n.MsgPICUsage), nil
+		}
+		restored, err := store.Restore(ctx, version)
+		if err != nil && strings.HasPrefix(err.Error(), constants.ErrVersionNotFound) {
+			return i18n.T(c.Locale, i18n.MsgPICVersionNotFound, c.Args[1]), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return s.announceRestore(c, store.Name(), restored)
+	}
+	return i18n.T(c.Locale, i18n.MsgPICUsage), nil
+}
+
+// describePICs renders this week's PICs and the full rotation
+func (s *EventCallbackService) describePICs(locale string, store *schedule.Store) (string, error) {
+	schedules, err := store.Read()
+	if err != nil {
+		return "", err
+	}
+	start, end := store.CurrentWeek()
+	return s.templates.Render(locale, templates.PICAnnouncement, templates.PICAnnouncementData{
+		Rotation:  store.Name(),
+		WeekStart: start,
+		WeekEnd:   end,
+		Current:   schedule.SchedulesWithinRange(schedules, start, end),
+		Schedule:  schedules,
+	})
+}
+
+// extendPICs plans the coming periods of the rotation, first setting up the
+// rotation engine from the current schedule when it is not configured
+func (s *EventCallbackService) extendPICs(ctx context.Context, c *command.Context, store *schedule.Store) (string, error) {
+	rot, err := s.Rotation(store.Name())
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	if !rot.Configured() {
+		schedules, err := store.Read()
+		if err != nil {
+			return "", err
+		}
+		if err := rot.Init(schedules); err != nil {
+			return "", err
+		}
+		if !rot.Configured() {
+			return i18n.T(c.Locale, i18n.MsgPICExtendNoMembers, store.Name()), nil
+		}
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgPICExtendInitialized, store.Name(), len(rot.Config().Members)))
+	}
+
+	plan, err := s.ExtendRotation(ctx, store.Name(), false)
+	if err != nil {
+		return "", err
+	}
+	if len(plan.Assignments) == 0 {
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgPICExtendNothing, store.Name(), rot.Config().Ahead()))
+		return strings.Join(lines, "\n"), nil
+	}
+	lines = append(lines, i18n.T(c.Locale, i18n.MsgPICExtended, len(plan.Assignments), store.Name()))
+	for _, a := range plan.Assignments {
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgPICExtendedEntry, i18n.FormatDate(c.Locale, "Mon 2 Jan 2006", a.Date), a.PIC))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// describeHistory lists the latest versions of a rotation, newest first
+func (s *EventCallbackService) describeHistory(c *command.Context, store *schedule.Store, limit int) string {
+	versions := store.History(limit)
+	if len(versions) == 0 {
+		return i18n.T(c.Locale, i18n.MsgPICHistoryEmpty, store.Name())
+	}
+
+	g, _ := s.groups.Get(c.GroupID)
+	loc := g.Location()
+	lines := []string{i18n.T(c.Locale, i18n.MsgPICHistoryHeader, store.Name())}
+	for _, v := range versions {
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgPICHistoryEntry,
+			v.Version, v.Time.In(loc).Format("2006-01-02 15:04"),
+			versionAction(c.Locale, v), s.displayActor(c.Locale, v.Actor), len(v.Entries)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeCalendar links the calendar feed of the rotation and, when the
+// sender's email is known, the feed of their own turns
+func (s *EventCallbackService) describeCalendar(c *command.Context, rotation string) string {
+	if s.config.PublicURL == "" {
+		return i18n.T(c.Locale, i18n.MsgPICCalendarDisabled)
+	}
+	lines := []string{i18n.T(c.Locale, i18n.MsgPICCalendar, rotation, calendar.RotationURL(s.config.PublicURL, rotation, s.config.CalendarToken))}
+	if entry, err := s.directory.Lookup(c.EmployeeCode); err == nil && entry.Email != "" {
+		lines = append(lines, i18n.T(c.Locale, i18n.MsgPICCalendarPersonal, calendar.PersonURL(s.config.PublicURL, entry.Email, s.config.CalendarToken)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// announceRestore tells the groups following the rotation that it was
+// restored, replying with the same announcement
+func (s *EventCallbackService) announceRestore(c *command.Context, rotation string, restored schedule.Version) (string, error) {
+	render := func(locale string) (string, error) {
+		return s.templates.Render(locale, templates.ScheduleRestored, templates.ScheduleRestoredData{
+			Rotation:     rotation,
+			Version:      restored.Version,
+			RestoredFrom: restored.RestoredFrom,
+			By:           s.displayEmployee(c.EmployeeCode),
+			Schedule:     restored.Entries,
+		})
+	}
+
+	var others []groups.Group
+	for _, g := range s.groups.Subscribed(constants.JobStockInventoryPIC) {
+		if g.GroupID != c.GroupID {
+			others = append(others, g)
+		}
+	}
+	err := s.broadcastTo(constants.JobStockInventoryPIC, others, func(g groups.Group, locale string) (string, error) {
+		return render(locale)
+	})
+	if err != nil {
+		slog.Warn("failed to announce the restored rotation", "rotation", rotation, "version", restored.Version, "error", err)
+	}
+	return render(c.Locale)
+}
+
+// versionAction describes the change that produced a version
+func versionAction(locale string, v schedule.Version) string {
+	switch v.Action {
+	case audit.ActionScheduleReplace:
+		return i18n.T(locale, i18n.MsgPICActionReplace)
+	case audit.ActionScheduleAdvance:
+		return i18n.T(locale, i18n.MsgPICActionAdvance)
+	case audit.ActionScheduleRestore:
+		return i18n.T(locale, i18n.MsgPICActionRestore, v.RestoredFrom)
+	}
+	return i18n.T(locale, i18n.MsgPICActionEdit)
+}
+
+// displayActor names the actor of an audited change
+func (s *EventCallbackService) displayActor(locale, actor string) string {
+	if actor == "" {
+		return i18n.T(locale, i18n.MsgPICActorUnknown)
+	}
+	if code, ok := audit.EmployeeOf(actor); ok {
+		return s.displayEmployee(code)
+	}
+	return actor
+}
@@ -0,0 +1,115 @@
+package eventcallback
+
+import (
+	"context"
+	"strings"
+
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/roles"
+)
+
+// cmdRole shows the sender's role, lists the roles of a scope, or grants and
+// revokes roles. Roles apply to the group the command is sent in, or
+// everywhere when "global" is given or the command is sent directly.
+func (s *EventCallbackService) cmdRole(ctx context.Context, c *command.Context) (string, error) {
+	args := c.Args
+	scope := c.GroupID
+	if n := len(args); n > 0 && strings.EqualFold(args[n-1], "global") {
+		scope, args = roles.Global, args[:n-1]
+	}
+
+	if len(args) == 0 {
+		return i18n.T(c.Locale, i18n.MsgRoleCurrent, c.Role.String()), nil
+	}
+
+	action := strings.ToLower(args[0])
+	switch {
+	case action == "list" && len(args) == 1:
+		return s.describeRoles(c.Locale, scope), nil
+	case action == "grant" && len(args) >= 2:
+		role, err := roles.Parse(args[1])
+		if err != nil || role == roles.Member {
+			return i18n.T(c.Locale, i18n.MsgRoleUsage), nil
+		}
+		return s.changeRoles(c, scope, role, args[2:])
+	case action == "revoke":
+		return s.changeRoles(c, scope, roles.Member, args[1:])
+	}
+	return i18n.T(c.Locale, i18n.MsgRoleUsage), nil
+}
+
+// changeRoles binds role to the mentioned users and the given employee codes
+// in scope, Member meaning a revocation
+func (s *EventCallbackService) changeRoles(c *command.Context, scope string, role roles.Role, args []string) (string, error) {
+	codes, unknown := s.employeeCodes(c.Mentions, args)
+	if unknown != "" {
+		return i18n.T(c.Locale, i18n.MsgWhoisUnknown, unknown), nil
+	}
+	if len(codes) == 0 {
+		return i18n.T(c.Locale, i18n.MsgRoleUsage), nil
+	}
+
+	// Global roles are managed with global roles only
+	actor := c.Role
+	if scope == roles.Global {
+		actor = s.roles.Bound(roles.Global, c.EmployeeCode)
+	}
+	if !actor.AtLeast(roles.Admin) {
+		return "", &command.PermissionError{Command: c.Name, Required: roles.Admin}
+	}
+
+	var lines []string
+	for _, code := range codes {
+		current := s.roles.Bound(scope, code)
+		// Nobody may grant or take away more than they hold
+		if !actor.AtLeast(max(role, current)) {
+			return "", &command.PermissionError{Command: c.Name, Required: max(role, current)}
+		}
+		if scope == roles.Global && s.roles.Configured(code) {
+			lines = append(lines, i18n.T(c.Locale, i18n.MsgRoleConfigured, s.displayEmployee(code)))
+			continue
+		}
+
+		key := i18n.MsgRoleGrantedGroup
+		var err error
+		switch {
+		case role == roles.Member && scope == roles.Global:
+			key, err = i18n.MsgRoleRevokedGlobal, s.roles.Revoke(scope, code)
+		case role == roles.Member:
+			key, err = i18n.MsgRoleRevokedGroup, s.roles.Revoke(scope, code)
+		case scope == roles.Global:
+			key, err = i18n.MsgRoleGrantedGlobal, s.roles.Grant(scope, code, role)
+		default:
+			err = s.roles.Grant(scope, code, role)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		shown := role
+		if role == roles.Member {
+			shown = current
+		}
+		lines = append(lines, i18n.T(c.Locale, key, s.displayEmployee(code), shown.String()))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// describeRoles lists the roles bound in a scope
+func (s *EventCallbackService) describeRoles(locale, scope string) string {
+	bindings := s.roles.Bindings(scope)
+	if len(bindings) == 0 {
+		return i18n.T(locale, i18n.MsgRoleNone)
+	}
+
+	header := i18n.MsgRoleListGroup
+	if scope == roles.Global {
+		header = i18n.MsgRoleListGlobal
+	}
+	lines := []string{i18n.T(locale, header)}
+	for _, b := range bindings {
+		lines = append(lines, "- "+s.displayEmployee(b.EmployeeCode)+": "+b.Role.String())
+	}
+	return strings.Join(lines, "\n")
+}
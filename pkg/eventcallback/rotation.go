@@ -0,0 +1,113 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/rotation"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Rotation returns the configuration and turn counts of the named rotation
+func (s *EventCallbackService) Rotation(name string) (*rotation.Rotation, error) {
+	rot, ok := s.rotations[name]
+	if !ok {
+		return nil, errors.New(constants.ErrRotationNotFound + ": " + name)
+	}
+	return rot, nil
+}
+
+// PlanRotation returns the schedule entries of the named rotation that are
+// kept, and the plan filling the periods after them. Past entries are
+// dropped; with replan, so are the planned entries after the current week.
+func (s *EventCallbackService) PlanRotation(name string, replan bool) ([]schedule.Schedule, rotation.Plan, error) {
+	store, err := s.schedules.Get(name)
+	if err != nil {
+		return nil, rotation.Plan{}, err
+	}
+	rot, err := s.Rotation(name)
+	if err != nil {
+		return nil, rotation.Plan{}, err
+	}
+	if !rot.Configured() {
+		return nil, rotation.Plan{}, errors.New(constants.ErrRotationNotConfigured + ": " + name)
+	}
+	schedules, err := store.Read()
+	if err != nil {
+		return nil, rotation.Plan{}, err
+	}
+
+	cfg := rot.Config()
+	start, end := store.CurrentWeek()
+	// The new periods follow on from the latest entry that is not replaced,
+	// so that they stay on the same weekday
+	var kept []schedule.Schedule
+	var last time.Time
+	planned := make(map[string]bool)
+	for _, entry := range schedules {
+		if replan && entry.Date.After(end) {
+			continue
+		}
+		if entry.Date.After(last) {
+			last = entry.Date
+		}
+		if entry.Date.Before(start) {
+			continue
+		}
+		kept = append(kept, entry)
+		if entry.Date.After(end) {
+			planned[entry.Date.Format(constants.DateFormat)] = true
+		}
+	}
+	if last.IsZero() {
+		last = start
+	}
+
+	// Fill the periods up to the configured horizon, counting the periods
+	// already planned after the current week
+	periods := cfg.Ahead() - len(planned)
+	if periods <= 0 {
+		return kept, rotation.Plan{}, nil
+	}
+	next := nextPeriod(last, end, cfg)
+	return kept, rot.Plan(next, periods, kept), nil
+}
+
+// ExtendRotation plans the named rotation ahead and writes the result to its
+// schedule, returning the plan
+func (s *EventCallbackService) ExtendRotation(ctx context.Context, name string, replan bool) (rotation.Plan, error) {
+	kept, plan, err := s.PlanRotation(name, replan)
+	if err != nil {
+		return plan, err
+	}
+	for _, w := range plan.Warnings {
+		slog.Warn("rotation plan warning", "rotation", name, "date", w.Date.Format("2006-01-02"), "reason", w.Reason)
+	}
+	if len(plan.Assignments) == 0 && !replan {
+		return plan, nil
+	}
+
+	store, err := s.schedules.Get(name)
+	if err != nil {
+		return plan, err
+	}
+	schedules := append(kept, plan.Assignments...)
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].Date.Before(schedules[j].Date)
+	})
+	return plan, store.Replace(ctx, schedules)
+}
+
+// nextPeriod returns the first period day after end, stepping from last by
+// the period length of the rotation
+func nextPeriod(last, end time.Time, cfg rotation.Config) time.Time {
+	next := last.AddDate(0, 0, cfg.Period())
+	for !next.After(end) {
+		next = next.AddDate(0, 0, cfg.Period())
+	}
+	return next
+}
@@ -0,0 +1,178 @@
+package eventcallback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/command"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/schedule"
+	"seatalk-bot/pkg/subscription"
+	"seatalk-bot/pkg/templates"
+)
+
+// cmdSubscribe subscribes the sender to direct message notifications, or
+// lists their subscriptions when no topic is given
+func (s *EventCallbackService) cmdSubscribe(ctx context.Context, c *command.Context) (string, error) {
+	if c.InGroup() {
+		return i18n.T(c.Locale, i18n.MsgSubscribeDirectOnly), nil
+	}
+	if len(c.Args) == 0 {
+		return s.describeSubscription(c), nil
+	}
+
+	topic := strings.ToLower(c.Args[0])
+	days := 0
+	switch {
+	case !subscription.ValidTopic(topic), len(c.Args) > 2, len(c.Args) == 2 && topic != subscription.TopicPIC:
+		return i18n.T(c.Locale, i18n.MsgSubscribeUsage), nil
+	case len(c.Args) == 2:
+		var err error
+		if days, err = strconv.Atoi(c.Args[1]); err != nil || days < 1 {
+			return i18n.T(c.Locale, i18n.MsgSubscribeUsage), nil
+		}
+	}
+
+	if err := s.subscriptions.Subscribe(c.EmployeeCode, topic, days); err != nil {
+		return "", err
+	}
+	if topic == subscription.TopicPIC {
+		return i18n.T(c.Locale, i18n.MsgSubscribedPIC, s.noticeDays(c.EmployeeCode)), nil
+	}
+	return i18n.T(c.Locale, i18n.MsgSubscribedReminders), nil
+}
+
+// cmdUnsubscribe removes one or every topic from the sender's subscriptions
+func (s *EventCallbackService) cmdUnsubscribe(ctx context.Context, c *command.Context) (string, error) {
+	if c.InGroup() {
+		return i18n.T(c.Locale, i18n.MsgSubscribeDirectOnly), nil
+	}
+
+	topic := ""
+	switch {
+	case len(c.Args) > 1:
+		return i18n.T(c.Locale, i18n.MsgUnsubscribeUsage), nil
+	case len(c.Args) == 1 && strings.ToLower(c.Args[0]) != "all":
+		topic = strings.ToLower(c.Args[0])
+		if !subscription.ValidTopic(topic) {
+			return i18n.T(c.Locale, i18n.MsgUnsubscribeUsage), nil
+		}
+	}
+
+	if err := s.subscriptions.Unsubscribe(c.EmployeeCode, topic); err != nil {
+		return "", err
+	}
+	return s.describeSubscription(c), nil
+}
+
+// describeSubscription lists the topics the sender is subscribed to
+func (s *EventCallbackService) describeSubscription(c *command.Context) string {
+	sub := s.subscriptions.Get(c.EmployeeCode)
+	if len(sub.Topics) == 0 {
+		return i18n.T(c.Locale, i18n.MsgNoSubscriptions)
+	}
+	lines := []string{i18n.T(c.Locale, i18n.MsgSubscriptionsHeader)}
+	for _, topic := range sub.Topics {
+		if topic == subscription.TopicPIC {
+			lines = append(lines, "- "+i18n.T(c.Locale, i18n.MsgSubscribedPIC, s.noticeDays(c.EmployeeCode)))
+			continue
+		}
+		lines = append(lines, "- "+i18n.T(c.Locale, i18n.MsgSubscribedReminders))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// noticeDays returns how many days ahead an employee is told they are PIC
+func (s *EventCallbackService) noticeDays(employeeCode string) int {
+	if days := s.subscriptions.Get(employeeCode).PICNoticeDays; days > 0 {
+		return days
+	}
+	if s.config.PICNoticeDays > 0 {
+		return s.config.PICNoticeDays
+	}
+	return 1
+}
+
+// performPICNotice tells the subscribers of TopicPIC who are PIC exactly
+// their notice period from today
+func (s *EventCallbackService) performPICNotice() error {
+	today := schedule.Day(s.clock.Now())
+	for _, name := range s.schedules.Names() {
+		store, err := s.schedules.Get(name)
+		if err != nil {
+			return err
+		}
+		s.checkSchedule(store)
+	}
+
+	var errs []error
+	for _, code := range s.subscriptions.Subscribers(subscription.TopicPIC) {
+		recipient, err := s.directory.Lookup(code)
+		if err != nil || recipient.Email == "" {
+			slog.Warn("cannot match a PIC subscriber to the rotations", "employee_code", code, "error", err)
+			continue
+		}
+
+		days := s.noticeDays(code)
+		target := today.AddDate(0, 0, days)
+		for _, name := range s.schedules.Names() {
+			store, err := s.schedules.Get(name)
+			if err != nil {
+				return err
+			}
+			schedules, err := store.Read()
+			if err != nil {
+				return err
+			}
+			for _, sched := range schedules {
+				if !sched.Date.Equal(target) || !strings.EqualFold(sched.Email, recipient.Email) {
+					continue
+				}
+				errs = append(errs, s.notify(code, templates.PICNotice, templates.PICNoticeData{
+					Rotation:  name,
+					Date:      sched.Date,
+					Days:      days,
+					Recipient: recipient,
+				}))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notifySubscribers sends a template to every subscriber of a topic, each in
+// their own language
+func (s *EventCallbackService) notifySubscribers(topic, name string, data interface{}) error {
+	var errs []error
+	for _, code := range s.subscriptions.Subscribers(topic) {
+		errs = append(errs, s.notify(code, name, data))
+	}
+	return errors.Join(errs...)
+}
+
+// notify renders a template in the employee's language and sends it to them
+func (s *EventCallbackService) notify(employeeCode, name string, data interface{}) error {
+	content, err := s.templates.Render(s.locales.UserLocale(employeeCode), name, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.SendMessageToSubscriber(request.SendMessageToBotSubscriberRequest{
+		EmployeeCode: employeeCode,
+		Message: request.MessageSingle{
+			Tag: "Text",
+			Text: request.TextSingle{
+				Format:  1,
+				Content: content,
+			},
+		},
+	})
+	if err != nil {
+		slog.Warn("failed to notify subscriber", "employee_code", employeeCode, "template", name, "error", err)
+	}
+	return err
+}
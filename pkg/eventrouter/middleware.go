@@ -0,0 +1,127 @@
+package eventrouter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/logging"
+)
+
+// SignatureHeader is the header SeaTalk uses to sign callback requests
+const SignatureHeader = "Signature"
+
+// Logging logs every dispatched event with its outcome and duration
+func Logging() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt *Event) (interface{}, error) {
+			logger := eventLogger(ctx, evt)
+			start := time.Now()
+			reply, err := next(ctx, evt)
+			if err != nil {
+				logger.Error("event failed", "duration", time.Since(start), "error", err)
+			} else {
+				logger.Info("event handled", "duration", time.Since(start))
+			}
+			return reply, err
+		}
+	}
+}
+
+// Recovery turns a panicking handler into an error so the server keeps running
+func Recovery() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt *Event) (reply interface{}, err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					eventLogger(ctx, evt).Error("panic handling event", "panic", rec, "stack", string(debug.Stack()))
+					reply, err = nil, fmt.Errorf("%w: %v", ErrPanic, rec)
+				}
+			}()
+			return next(ctx, evt)
+		}
+	}
+}
+
+// Auth rejects callbacks whose signature does not match sha256(body + signingSecret).
+// Verification is skipped when no signing secret is configured.
+func Auth(signingSecret string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt *Event) (interface{}, error) {
+			if signingSecret == "" {
+				return next(ctx, evt)
+			}
+			if !ValidSignature(evt.Body, signingSecret, evt.Header.Get(SignatureHeader)) {
+				return nil, ErrUnauthorized
+			}
+			return next(ctx, evt)
+		}
+	}
+}
+
+// Dedupe drops the events whose ID was seen within window, so that a callback
+// SeaTalk redelivers while the first delivery is slow is handled once. An
+// event whose handler failed may be delivered again.
+func Dedupe(window time.Duration) Middleware {
+	var mu sync.Mutex
+	seen := make(map[string]time.Time)
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, evt *Event) (interface{}, error) {
+			id := evt.Envelope.EventID
+			if id == "" {
+				return next(ctx, evt)
+			}
+
+			now := time.Now()
+			mu.Lock()
+			for seenID, at := range seen {
+				if now.Sub(at) >= window {
+					delete(seen, seenID)
+				}
+			}
+			_, duplicate := seen[id]
+			if !duplicate {
+				seen[id] = now
+			}
+			mu.Unlock()
+			if duplicate {
+				eventLogger(ctx, evt).Info("ignoring redelivered event")
+				return nil, nil
+			}
+
+			reply, err := next(ctx, evt)
+			if err != nil {
+				mu.Lock()
+				delete(seen, id)
+				mu.Unlock()
+			}
+			return reply, err
+		}
+	}
+}
+
+// eventLogger returns a logger annotated with the request and event identifiers
+func eventLogger(ctx context.Context, evt *Event) *slog.Logger {
+	return logging.FromContext(ctx).With(
+		"event_id", evt.Envelope.EventID,
+		"event_type", evt.Envelope.EventType,
+	)
+}
+
+// Sign computes the callback signature of body for the given signing secret
+func Sign(body []byte, signingSecret string) string {
+	sum := sha256.Sum256(append(append([]byte{}, body...), signingSecret...))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidSignature reports whether signature matches the body and signing secret
+func ValidSignature(body []byte, signingSecret, signature string) bool {
+	expected := Sign(body, signingSecret)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
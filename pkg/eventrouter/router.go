@@ -0,0 +1,150 @@
+package eventrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/metrics"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// ErrUnauthorized is returned by handlers when the callback cannot be trusted
+var ErrUnauthorized = errors.New(constants.ErrInvalidSignature)
+
+// ErrInvalidPayload is returned when the event cannot be decoded into its type
+var ErrInvalidPayload = errors.New(constants.ErrInvalidEventPayload)
+
+// ErrPanic is returned when a handler panicked and was recovered
+var ErrPanic = errors.New(constants.ErrHandlerPanic)
+
+// Event is a decoded callback envelope together with the raw HTTP request details
+type Event struct {
+	Envelope request.EventCallbackEnvelope
+	Body     []byte
+	Header   http.Header
+}
+
+// HandlerFunc handles a callback event and returns the body to reply with.
+// A nil reply is answered with an empty EventCallbackResponse.
+type HandlerFunc func(ctx context.Context, evt *Event) (interface{}, error)
+
+// Middleware wraps a HandlerFunc with additional behaviour
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// EventRouter dispatches callback events to the handler registered for their type
+type EventRouter struct {
+	handlers   map[string]HandlerFunc
+	middleware []Middleware
+}
+
+// NewEventRouter creates an empty EventRouter
+func NewEventRouter() *EventRouter {
+	return &EventRouter{handlers: make(map[string]HandlerFunc)}
+}
+
+// Use appends middleware applied to every dispatched event, in the order given
+func (r *EventRouter) Use(mw ...Middleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+// Handle registers the handler for an event type, replacing any previous one
+func (r *EventRouter) Handle(eventType string, h HandlerFunc) {
+	r.handlers[eventType] = h
+}
+
+// Typed adapts a handler taking a concrete event struct into a HandlerFunc
+func Typed[T any](fn func(ctx context.Context, evt *Event, payload T) (interface{}, error)) HandlerFunc {
+	return func(ctx context.Context, evt *Event) (interface{}, error) {
+		var payload T
+		if err := json.Unmarshal(evt.Envelope.Event, &payload); err != nil {
+			return nil, ErrInvalidPayload
+		}
+		return fn(ctx, evt, payload)
+	}
+}
+
+// Dispatch runs the event through the middleware chain and its registered handler.
+// Events without a handler are logged and acknowledged.
+func (r *EventRouter) Dispatch(ctx context.Context, evt *Event) (interface{}, error) {
+	h := HandlerFunc(func(ctx context.Context, evt *Event) (interface{}, error) {
+		handler, ok := r.handlers[evt.Envelope.EventType]
+		if !ok {
+			eventLogger(ctx, evt).Warn("ignoring unsupported event type")
+			return nil, nil
+		}
+		return handler(ctx, evt)
+	})
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	return h(ctx, evt)
+}
+
+// ServeHTTP decodes the callback envelope and dispatches it
+func (r *EventRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Limit to POST requests
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	evt := &Event{Body: body, Header: req.Header}
+	if err := json.Unmarshal(body, &evt.Envelope); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	reply, err := r.Dispatch(req.Context(), evt)
+	metrics.ObserveCallback(evt.Envelope.EventType, r.outcome(evt, err), time.Since(start))
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrInvalidPayload):
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, "Failed to handle event", http.StatusInternalServerError)
+		return
+	}
+
+	if reply == nil {
+		reply = response.EventCallbackResponse{}
+	}
+
+	// Set the response header and send the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reply)
+}
+
+// outcome classifies a dispatched event for the callback metrics
+func (r *EventRouter) outcome(evt *Event, err error) string {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return metrics.OutcomeUnauthorized
+	case errors.Is(err, ErrInvalidPayload):
+		return metrics.OutcomeBadRequest
+	case errors.Is(err, ErrPanic):
+		return metrics.OutcomePanic
+	case err != nil:
+		return metrics.OutcomeError
+	}
+	if _, ok := r.handlers[evt.Envelope.EventType]; !ok {
+		return metrics.OutcomeIgnored
+	}
+	return metrics.OutcomeOK
+}
@@ -0,0 +1,234 @@
+package eventrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/models/request"
+)
+
+// newEvent builds an event of the given type carrying payload
+func newEvent(eventType, payload string) *Event {
+	return &Event{
+		Envelope: request.EventCallbackEnvelope{EventID: "e1", EventType: eventType, Event: json.RawMessage(payload)},
+		Header:   http.Header{},
+	}
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestDispatch(t *testing.T) {
+	r := NewEventRouter()
+	r.Handle("greet", Typed(func(ctx context.Context, evt *Event, g greeting) (interface{}, error) {
+		return "hello " + g.Name, nil
+	}))
+	r.Handle("fail", func(ctx context.Context, evt *Event) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   string
+		want      interface{}
+		err       string
+	}{
+		{"typed handler", "greet", `{"name":"Jane"}`, "hello Jane", ""},
+		{"invalid payload", "greet", `{"name":1}`, nil, ErrInvalidPayload.Error()},
+		{"handler error", "fail", `{}`, nil, "boom"},
+		{"unsupported type is acknowledged", "unknown", `{}`, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Dispatch(context.Background(), newEvent(tt.eventType, tt.payload))
+			if (err == nil && tt.err != "") || (err != nil && err.Error() != tt.err) {
+				t.Fatalf("Dispatch() error = %v, want %q", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Dispatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, evt *Event) (interface{}, error) {
+				calls = append(calls, name+" before")
+				reply, err := next(ctx, evt)
+				calls = append(calls, name+" after")
+				return reply, err
+			}
+		}
+	}
+
+	r := NewEventRouter()
+	r.Use(trace("outer"), trace("inner"))
+	r.Handle("greet", func(ctx context.Context, evt *Event) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	})
+	if _, err := r.Dispatch(context.Background(), newEvent("greet", `{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"outer before", "inner before", "handler", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	r := NewEventRouter()
+	r.Use(Recovery())
+	r.Handle("panic", func(ctx context.Context, evt *Event) (interface{}, error) {
+		panic("bad handler")
+	})
+
+	_, err := r.Dispatch(context.Background(), newEvent("panic", `{}`))
+	if err == nil || !strings.Contains(err.Error(), "bad handler") {
+		t.Errorf("Dispatch() error = %v, want the recovered panic", err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	body := []byte(`{"event_type":"greet"}`)
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		wantErr   bool
+	}{
+		{"valid signature", "secret", Sign(body, "secret"), false},
+		{"signed with another secret", "secret", Sign(body, "other"), true},
+		{"missing signature", "secret", "", true},
+		{"verification disabled", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := Auth(tt.secret)(func(ctx context.Context, evt *Event) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+			evt := newEvent("greet", `{}`)
+			evt.Body = body
+			evt.Header.Set(SignatureHeader, tt.signature)
+
+			_, err := h(context.Background(), evt)
+			if tt.wantErr != errors.Is(err, ErrUnauthorized) || called == tt.wantErr {
+				t.Errorf("Auth() error = %v, handler called = %v", err, called)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	calls := 0
+	fail := false
+	h := Dedupe(time.Minute)(func(ctx context.Context, evt *Event) (interface{}, error) {
+		calls++
+		if fail {
+			return nil, errors.New("failed")
+		}
+		return nil, nil
+	})
+
+	steps := []struct {
+		name  string
+		id    string
+		fail  bool
+		calls int
+	}{
+		{"first delivery", "e1", false, 1},
+		{"redelivery", "e1", false, 1},
+		{"other event", "e2", false, 2},
+		{"event without an ID", "", false, 3},
+		{"same event without an ID", "", false, 4},
+		{"failed delivery", "e3", true, 5},
+		{"retry after a failure", "e3", false, 6},
+		{"redelivery after the retry", "e3", false, 6},
+	}
+	for _, step := range steps {
+		fail = step.fail
+		evt := newEvent("greet", `{}`)
+		evt.Envelope.EventID = step.id
+		h(context.Background(), evt)
+		if calls != step.calls {
+			t.Errorf("%s: handler called %d times, want %d", step.name, calls, step.calls)
+		}
+	}
+}
+
+func TestDedupeForgetsAfterWindow(t *testing.T) {
+	calls := 0
+	h := Dedupe(time.Millisecond)(func(ctx context.Context, evt *Event) (interface{}, error) {
+		calls++
+		return nil, nil
+	})
+	h(context.Background(), newEvent("greet", `{}`))
+	time.Sleep(5 * time.Millisecond)
+	h(context.Background(), newEvent("greet", `{}`))
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewEventRouter()
+	r.Use(Auth("secret"))
+	r.Handle("greet", Typed(func(ctx context.Context, evt *Event, g greeting) (interface{}, error) {
+		return map[string]string{"reply": "hello " + g.Name}, nil
+	}))
+	r.Handle("quiet", func(ctx context.Context, evt *Event) (interface{}, error) {
+		return nil, nil
+	})
+	r.Handle("fail", func(ctx context.Context, evt *Event) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		signed bool
+		status int
+		reply  string
+	}{
+		{"reply", http.MethodPost, `{"event_type":"greet","event":{"name":"Jane"}}`, true, http.StatusOK, `{"reply":"hello Jane"}`},
+		{"empty reply", http.MethodPost, `{"event_type":"quiet","event":{}}`, true, http.StatusOK, `{"seatalk_challenge":""}`},
+		{"unsigned", http.MethodPost, `{"event_type":"greet","event":{"name":"Jane"}}`, false, http.StatusUnauthorized, ""},
+		{"invalid payload", http.MethodPost, `{"event_type":"greet","event":[]}`, true, http.StatusBadRequest, ""},
+		{"invalid envelope", http.MethodPost, `not json`, true, http.StatusBadRequest, ""},
+		{"handler error", http.MethodPost, `{"event_type":"fail","event":{}}`, true, http.StatusInternalServerError, ""},
+		{"wrong method", http.MethodGet, ``, true, http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/callback", strings.NewReader(tt.body))
+			if tt.signed {
+				req.Header.Set(SignatureHeader, Sign([]byte(tt.body), "secret"))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.reply != "" && strings.TrimSpace(w.Body.String()) != tt.reply {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.reply)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package faq
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+)
+
+// RegexPrefix marks a trigger matched as a case-insensitive regular
+// expression rather than as keywords
+const RegexPrefix = "re:"
+
+// Entry is a recurring question: the triggers that recognize it and its answer
+type Entry struct {
+	ID       int      `json:"id"`
+	Triggers []string `json:"triggers"`
+	Answer   string   `json:"answer"`
+	// UpdatedBy is the employee code of whoever last changed the entry
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// faqData is the persisted form of the FAQ
+type faqData struct {
+	NextID  int     `json:"next_id"`
+	Entries []Entry `json:"entries"`
+}
+
+// Store persists the FAQ entries and matches questions against them
+type Store struct {
+	path  string
+	clock clock.Clock
+	mu    sync.RWMutex
+	data  faqData
+	// patterns holds the compiled regular expression triggers
+	patterns map[string]*regexp.Regexp
+}
+
+// Load loads the FAQ persisted at path
+func Load(path string, clk clock.Clock) (*Store, error) {
+	s := &Store{path: path, clock: clk, patterns: make(map[string]*regexp.Regexp)}
+	if err := filestore.LoadJSON(path, &s.data); err != nil {
+		return nil, err
+	}
+	for _, e := range s.data.Entries {
+		if err := s.compile(e.Triggers); err != nil {
+			return nil, err
+		}
+		if e.ID >= s.data.NextID {
+			s.data.NextID = e.ID + 1
+		}
+	}
+	if s.data.NextID == 0 {
+		s.data.NextID = 1
+	}
+	return s, nil
+}
+
+// ParseTriggers splits a list of triggers separated by semicolons, dropping
+// empty ones
+func ParseTriggers(list string) []string {
+	var triggers []string
+	for _, t := range strings.Split(list, ";") {
+		if t = strings.TrimSpace(t); t != "" {
+			triggers = append(triggers, t)
+		}
+	}
+	return triggers
+}
+
+// Entries returns every entry, by ID
+func (s *Store) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]Entry, len(s.data.Entries))
+	copy(entries, s.data.Entries)
+	return entries
+}
+
+// Get returns the entry with the given ID
+func (s *Store) Get(id int) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.index(id)
+	if i < 0 {
+		return Entry{}, false
+	}
+	return s.data.Entries[i], true
+}
+
+// Add creates an entry and returns it
+func (s *Store) Add(triggers []string, answer, by string) (Entry, error) {
+	if len(triggers) == 0 || strings.TrimSpace(answer) == "" {
+		return Entry{}, errors.New(constants.ErrInvalidFAQ + ": triggers and answer are required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.compile(triggers); err != nil {
+		return Entry{}, err
+	}
+	e := Entry{ID: s.data.NextID, Triggers: triggers, Answer: strings.TrimSpace(answer), UpdatedBy: by, UpdatedAt: s.clock.Now()}
+	s.data.NextID++
+	s.data.Entries = append(s.data.Entries, e)
+	return e, filestore.SaveJSON(s.path, s.data)
+}
+
+// Edit changes the triggers and the answer of an entry. Empty triggers or an
+// empty answer keep the current ones.
+func (s *Store) Edit(id int, triggers []string, answer, by string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return Entry{}, errors.New(constants.ErrFAQNotFound + ": " + strconv.Itoa(id))
+	}
+	if err := s.compile(triggers); err != nil {
+		return Entry{}, err
+	}
+
+	e := &s.data.Entries[i]
+	if len(triggers) > 0 {
+		e.Triggers = triggers
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		e.Answer = answer
+	}
+	e.UpdatedBy, e.UpdatedAt = by, s.clock.Now()
+	return *e, filestore.SaveJSON(s.path, s.data)
+}
+
+// Remove deletes an entry
+func (s *Store) Remove(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return errors.New(constants.ErrFAQNotFound + ": " + strconv.Itoa(id))
+	}
+	s.data.Entries = append(s.data.Entries[:i], s.data.Entries[i+1:]...)
+	return filestore.SaveJSON(s.path, s.data)
+}
+
+// Search scores every entry against a question and returns those scoring at
+// least min, best first
+func (s *Store) Search(question string, min float64) []Match {
+	words := tokenize(question)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matches []Match
+	for _, e := range s.data.Entries {
+		best := Match{Entry: e}
+		for _, t := range e.Triggers {
+			var score float64
+			if pattern, ok := strings.CutPrefix(t, RegexPrefix); ok {
+				if s.patterns[pattern].MatchString(question) {
+					score = 1
+				}
+			} else {
+				score = scoreKeywords(tokenize(t), words)
+			}
+			if score > best.Score {
+				best.Score, best.Trigger = score, t
+			}
+		}
+		if best.Score >= min && best.Score > 0 {
+			matches = append(matches, best)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// index returns the position of the entry with the given ID, or -1; the
+// caller holds the lock
+func (s *Store) index(id int) int {
+	for i, e := range s.data.Entries {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// compile checks and caches the regular expression triggers; the caller
+// holds the lock
+func (s *Store) compile(triggers []string) error {
+	for _, t := range triggers {
+		pattern, ok := strings.CutPrefix(t, RegexPrefix)
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return errors.New(constants.ErrInvalidFAQ + ": " + t + ": " + err.Error())
+		}
+		s.patterns[pattern] = re
+	}
+	return nil
+}
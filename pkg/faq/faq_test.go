@@ -0,0 +1,175 @@
+package faq
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+)
+
+// newStore loads an empty FAQ in a temporary directory and adds entries
+// with the given triggers, separated by semicolons
+func newStore(t *testing.T, triggers ...string) *Store {
+	t.Helper()
+	s, err := Load(filepath.Join(t.TempDir(), "faq.json"), clock.NewFake(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, list := range triggers {
+		if _, err := s.Add(ParseTriggers(list), "answer to "+list, "E001"); err != nil {
+			t.Fatalf("Add(%q): %v", list, err)
+		}
+	}
+	return s
+}
+
+func TestSearch(t *testing.T) {
+	s := newStore(t,
+		"deploy staging; staging deployment",
+		"return refund test",
+		"re:^where .*(docs|documentation)",
+		"how to",
+	)
+
+	tests := []struct {
+		name     string
+		question string
+		// ids lists the matching entries, best first
+		ids    []int
+		answer bool
+	}{
+		{"exact", "How do I deploy staging?", []int{1}, true},
+		{"case and punctuation", "DEPLOY, STAGING!", []int{1}, true},
+		{"typo", "deploy stagign", []int{1}, true},
+		{"transposition", "when is the retrun refund test", []int{2}, true},
+		{"partial match is suggested", "is staging down", []int{1}, false},
+		{"regular expression", "Where are the docs?", []int{3}, true},
+		{"regular expression not matching", "docs, where are they", nil, false},
+		{"stop words only never match", "how to", nil, false},
+		{"unrelated", "what is for lunch", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := s.Search(tt.question, SuggestScore)
+			var ids []int
+			for _, m := range matches {
+				ids = append(ids, m.Entry.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Fatalf("Search(%q) = %v, want entries %v", tt.question, matches, tt.ids)
+			}
+			if len(matches) > 0 && (matches[0].Score >= AnswerScore) != tt.answer {
+				t.Errorf("Search(%q) scored %.2f, answered = %v", tt.question, matches[0].Score, tt.answer)
+			}
+		})
+	}
+}
+
+func TestScoreKeywords(t *testing.T) {
+	tests := []struct {
+		trigger  string
+		question string
+		want     float64
+	}{
+		{"deploy staging", "deploy staging now", 1},
+		{"deploy staging", "deploy stagign", (1 + fuzzyPenalty) / 2},
+		{"deploy staging", "staging", 0.5},
+		{"fix bug", "fix bag", 0.5},
+		{"the of", "the of", 0},
+	}
+	for _, tt := range tests {
+		if got := scoreKeywords(tokenize(tt.trigger), tokenize(tt.question)); got != tt.want {
+			t.Errorf("scoreKeywords(%q, %q) = %v, want %v", tt.trigger, tt.question, got, tt.want)
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"bug", "bag", false},
+		{"test", "tset", true},
+		{"return", "retrun", true},
+		{"staging", "stagging", true},
+		{"staging", "stgaign", false},
+		{"regression", "regresion", true},
+		{"regression", "rgeresion", true},
+		{"deploy", "destroy", false},
+	}
+	for _, tt := range tests {
+		if got := similar(tt.a, tt.b); got != tt.want {
+			t.Errorf("similar(%q, %q) = %v, want %v (distance %d)", tt.a, tt.b, got, tt.want, distance(tt.a, tt.b))
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"ab", "ba", 1},
+		{"return", "retrun", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseTriggers(t *testing.T) {
+	got := ParseTriggers(" deploy staging ;; re:^where ; ")
+	want := []string{"deploy staging", "re:^where"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTriggers() = %q, want %q", got, want)
+	}
+}
+
+func TestStoreChanges(t *testing.T) {
+	s := newStore(t, "deploy staging")
+
+	if _, err := s.Add([]string{"re:("}, "broken", "E001"); err == nil {
+		t.Error("Add accepted an invalid regular expression")
+	}
+	if _, err := s.Add(nil, "no triggers", "E001"); err == nil {
+		t.Error("Add accepted an entry without triggers")
+	}
+
+	e, err := s.Edit(1, nil, "new answer", "E002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(e.Triggers, []string{"deploy staging"}) || e.Answer != "new answer" || e.UpdatedBy != "E002" {
+		t.Errorf("Edit kept %+v", e)
+	}
+
+	reloaded, err := Load(s.path, s.clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := reloaded.Get(1); got.Answer != "new answer" {
+		t.Errorf("reloaded answer = %q", got.Answer)
+	}
+	if err := reloaded.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Remove(1); err == nil {
+		t.Error("Remove of a missing entry succeeded")
+	}
+	added, err := reloaded.Add([]string{"other"}, "answer", "E001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.ID != 2 {
+		t.Errorf("new entry ID = %d, want IDs not to be reused", added.ID)
+	}
+}
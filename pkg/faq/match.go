@@ -0,0 +1,109 @@
+package faq
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Thresholds of match scores
+const (
+	// AnswerScore is the least score of an entry answered directly
+	AnswerScore = 0.8
+	// SuggestScore is the least score of an entry suggested as a close match
+	SuggestScore = 0.3
+)
+
+// fuzzyPenalty lowers the score of keywords matched with a typo
+const fuzzyPenalty = 0.9
+
+// Match is an entry scored against a question, between 0 and 1
+type Match struct {
+	Entry Entry
+	Score float64
+	// Trigger is the trigger of the entry that scored best
+	Trigger string
+}
+
+// stopWords are left out of keyword matching
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "to": true, "of": true, "in": true,
+	"on": true, "for": true, "do": true, "i": true, "we": true, "how": true, "what": true, "where": true,
+	"yang": true, "di": true, "ke": true, "dan": true, "apa": true, "bagaimana": true, "mana": true,
+}
+
+// tokenize lowercases text and splits it into words, leaving out punctuation
+// and stop words
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if !stopWords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// scoreKeywords scores the share of trigger keywords found in the question.
+// Keywords match words with a small typo, at a lower score.
+func scoreKeywords(keywords, words []string) float64 {
+	if len(keywords) == 0 {
+		return 0
+	}
+	var total float64
+	for _, k := range keywords {
+		best := 0.0
+		for _, w := range words {
+			switch {
+			case w == k:
+				best = 1
+			case best < fuzzyPenalty && similar(k, w):
+				best = fuzzyPenalty
+			}
+		}
+		total += best
+	}
+	return total / float64(len(keywords))
+}
+
+// similar reports whether two words differ by a typo: one edit for words of
+// four letters or more, two edits from eight letters
+func similar(a, b string) bool {
+	n := min(len([]rune(a)), len([]rune(b)))
+	switch {
+	case n >= 8:
+		return distance(a, b) <= 2
+	case n >= 4:
+		return distance(a, b) <= 1
+	}
+	return false
+}
+
+// distance returns the edit distance between two words, counting the swap of
+// two adjacent letters as a single edit
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,205 @@
+package groups
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+	// Embed the timezone database so group timezones resolve on minimal images
+	_ "time/tzdata"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+)
+
+// DefaultTimezone is the timezone of groups that did not choose one
+const DefaultTimezone = "Asia/Jakarta"
+
+// Group holds the settings of a group chat the bot is in
+type Group struct {
+	GroupID string `json:"group_id"`
+	Name    string `json:"name,omitempty"`
+	// Active is false once the bot was removed from the group
+	Active bool `json:"active"`
+	// Jobs lists the scheduled jobs that post to the group
+	Jobs []string `json:"jobs,omitempty"`
+	// Timezone is the IANA name of the timezone dates and quiet hours follow
+	Timezone string `json:"timezone,omitempty"`
+	// QuietHours holds back scheduled messages, e.g. "22:00-07:00"
+	QuietHours string `json:"quiet_hours,omitempty"`
+	// Answers lets the bot answer free-form questions asked in the group
+	Answers bool `json:"answers,omitempty"`
+	// Admins lists the group admins saved before admins became role bindings.
+	// It is only read to migrate them.
+	Admins             []string `json:"admins,omitempty"`
+	CanNotifyWithAtAll bool     `json:"can_notify_with_at_all,omitempty"`
+}
+
+// Location returns the timezone of the group
+func (g Group) Location() *time.Location {
+	name := g.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(DefaultTimezone, 7*60*60)
+	}
+	return loc
+}
+
+// HasJob reports whether the job posts to the group
+func (g Group) HasJob(job string) bool {
+	return contains(g.Jobs, job)
+}
+
+// Quiet reports whether scheduled messages are held back at t
+func (g Group) Quiet(t time.Time) bool {
+	if g.QuietHours == "" {
+		return false
+	}
+	quiet, err := ParseQuietHours(g.QuietHours)
+	if err != nil {
+		return false
+	}
+	return quiet.Contains(t.In(g.Location()))
+}
+
+// Registry persists the groups the bot is in and their settings
+type Registry struct {
+	path   string
+	mu     sync.RWMutex
+	groups map[string]*Group
+}
+
+// Load loads the group registry persisted at path
+func Load(path string) (*Registry, error) {
+	var list []*Group
+	if err := filestore.LoadJSON(path, &list); err != nil {
+		return nil, err
+	}
+	r := &Registry{path: path, groups: make(map[string]*Group)}
+	for _, g := range list {
+		r.groups[g.GroupID] = g
+	}
+	return r, nil
+}
+
+// Get returns the settings of a group
+func (r *Registry) Get(groupID string) (Group, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	g, ok := r.groups[groupID]
+	if !ok {
+		return Group{}, false
+	}
+	return clone(g), true
+}
+
+// List returns every known group, sorted by ID
+func (r *Registry) List() []Group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		list = append(list, clone(g))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].GroupID < list[j].GroupID })
+	return list
+}
+
+// Subscribed returns the active groups the job posts to
+func (r *Registry) Subscribed(job string) []Group {
+	var subscribed []Group
+	for _, g := range r.List() {
+		if g.Active && g.HasJob(job) {
+			subscribed = append(subscribed, g)
+		}
+	}
+	return subscribed
+}
+
+// Ensure registers a group the bot is in, reactivating it when needed, and
+// returns its settings
+func (r *Registry) Ensure(groupID string) (Group, error) {
+	if g, ok := r.Get(groupID); ok && g.Active {
+		return g, nil
+	}
+	var result Group
+	err := r.Update(groupID, func(g *Group) error {
+		g.Active = true
+		result = clone(g)
+		return nil
+	})
+	return result, err
+}
+
+// Update applies fn to the settings of a group, creating it when unknown,
+// and persists the registry
+func (r *Registry) Update(groupID string, fn func(g *Group) error) error {
+	if groupID == "" {
+		return errors.New(constants.ErrGroupNotFound)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.groups[groupID]
+	updated := Group{GroupID: groupID}
+	if ok {
+		updated = clone(g)
+	}
+	if err := fn(&updated); err != nil {
+		return err
+	}
+	r.groups[groupID] = &updated
+	return r.save()
+}
+
+// save persists the registry; the caller holds the lock
+func (r *Registry) save() error {
+	list := make([]*Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].GroupID < list[j].GroupID })
+	return filestore.SaveJSON(r.path, list)
+}
+
+// AddItem adds value to a list setting unless already present
+func AddItem(list []string, value string) []string {
+	if contains(list, value) {
+		return list
+	}
+	list = append(list, value)
+	sort.Strings(list)
+	return list
+}
+
+// RemoveItem removes value from a list setting
+func RemoveItem(list []string, value string) []string {
+	var kept []string
+	for _, item := range list {
+		if item != value {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// clone copies a group so callers cannot modify the registry
+func clone(g *Group) Group {
+	c := *g
+	c.Jobs = append([]string(nil), g.Jobs...)
+	c.Admins = append([]string(nil), g.Admins...)
+	return c
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package groups
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		value string
+		want  QuietHours
+		ok    bool
+	}{
+		{"22:00-07:00", QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour}, true},
+		{" 12:30 - 13:15 ", QuietHours{Start: 12*time.Hour + 30*time.Minute, End: 13*time.Hour + 15*time.Minute}, true},
+		{"22:00", QuietHours{}, false},
+		{"22:00-25:00", QuietHours{}, false},
+		{"10pm-7am", QuietHours{}, false},
+		{"07:00-07:00", QuietHours{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuietHours(tt.value)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseQuietHours(%q) = %+v, %v, want %+v, ok %v", tt.value, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	overnight := QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour}
+	lunch := QuietHours{Start: 12 * time.Hour, End: 13 * time.Hour}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 20, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{"before overnight", overnight, at(21, 59), false},
+		{"overnight start", overnight, at(22, 0), true},
+		{"after midnight", overnight, at(3, 0), true},
+		{"overnight end", overnight, at(7, 0), false},
+		{"before lunch", lunch, at(11, 59), false},
+		{"lunch", lunch, at(12, 30), true},
+		{"lunch end", lunch, at(13, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Contains(tt.t); got != tt.want {
+			t.Errorf("%s: Contains(%s) = %v, want %v", tt.name, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestGroupQuietFollowsTimezone(t *testing.T) {
+	// 16:00 UTC is 23:00 in Jakarta and 18:00 in Amsterdam
+	now := time.Date(2026, time.October, 20, 16, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		group Group
+		want  bool
+	}{
+		{"default timezone", Group{QuietHours: "22:00-07:00"}, true},
+		{"own timezone", Group{QuietHours: "22:00-07:00", Timezone: "Europe/Amsterdam"}, false},
+		{"unknown timezone falls back to Jakarta", Group{QuietHours: "22:00-07:00", Timezone: "Mars/Olympus"}, true},
+		{"no quiet hours", Group{}, false},
+		{"invalid quiet hours", Group{QuietHours: "late"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.group.Quiet(now); got != tt.want {
+			t.Errorf("%s: Quiet() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groups.json")
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Ensure("g2"); err != nil {
+		t.Fatal(err)
+	}
+	err = r.Update("g1", func(g *Group) error {
+		g.Active = true
+		g.Jobs = AddItem(AddItem(g.Jobs, "remind"), "announce")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Update("g3", func(g *Group) error {
+		g.Jobs = []string{"announce"}
+		return nil
+	})
+	if err := r.Update("", func(g *Group) error { return nil }); err == nil {
+		t.Error("Update() of a group without an ID succeeded")
+	}
+
+	// Groups returned are copies
+	g, _ := r.Get("g1")
+	g.Jobs[0] = "changed"
+	if got, _ := r.Get("g1"); !reflect.DeepEqual(got.Jobs, []string{"announce", "remind"}) {
+		t.Errorf("jobs = %q, want a copy to be changed", got.Jobs)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, g := range reloaded.List() {
+		ids = append(ids, g.GroupID)
+	}
+	if want := []string{"g1", "g2", "g3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("groups = %q, want %q", ids, want)
+	}
+	subscribed := reloaded.Subscribed("announce")
+	if len(subscribed) != 1 || subscribed[0].GroupID != "g1" {
+		t.Errorf("Subscribed(announce) = %+v, want only the active g1", subscribed)
+	}
+}
+
+func TestListItems(t *testing.T) {
+	list := AddItem(AddItem(AddItem(nil, "b"), "a"), "b")
+	if !reflect.DeepEqual(list, []string{"a", "b"}) {
+		t.Errorf("AddItem() = %q", list)
+	}
+	if got := RemoveItem(list, "a"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("RemoveItem() = %q", got)
+	}
+}
+
+func TestHeldQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "held.json")
+	q, err := LoadHeld(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []HeldMessage{
+		{GroupID: "g1", Job: "announce", Content: "first"},
+		{GroupID: "g2", Job: "announce", Content: "second"},
+		{GroupID: "g1", Job: "remind", Content: "third"},
+	} {
+		if err := q.Hold(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	taken, err := q.Take(func(msg HeldMessage) bool { return msg.GroupID == "g1" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, msg := range taken {
+		contents = append(contents, msg.Content)
+	}
+	if want := []string{"first", "third"}; !reflect.DeepEqual(contents, want) {
+		t.Errorf("taken = %q, want %q in the order held", contents, want)
+	}
+
+	reloaded, err := LoadHeld(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Len(); got != 1 {
+		t.Errorf("%d messages still held, want 1", got)
+	}
+	if taken, _ := reloaded.Take(func(HeldMessage) bool { return false }); taken != nil {
+		t.Errorf("took %v, want nothing", taken)
+	}
+}
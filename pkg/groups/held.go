@@ -0,0 +1,67 @@
+package groups
+
+import (
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/filestore"
+)
+
+// HeldMessage is a scheduled message held back during the quiet hours of a group
+type HeldMessage struct {
+	GroupID string    `json:"group_id"`
+	Job     string    `json:"job"`
+	Content string    `json:"content"`
+	HeldAt  time.Time `json:"held_at"`
+}
+
+// HeldQueue persists the held messages until they can be sent
+type HeldQueue struct {
+	path     string
+	mu       sync.Mutex
+	messages []HeldMessage
+}
+
+// LoadHeld loads the held messages persisted at path
+func LoadHeld(path string) (*HeldQueue, error) {
+	var messages []HeldMessage
+	if err := filestore.LoadJSON(path, &messages); err != nil {
+		return nil, err
+	}
+	return &HeldQueue{path: path, messages: messages}, nil
+}
+
+// Hold queues a message
+func (q *HeldQueue) Hold(msg HeldMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.messages = append(q.messages, msg)
+	return filestore.SaveJSON(q.path, q.messages)
+}
+
+// Take removes and returns the held messages ready reports as sendable
+func (q *HeldQueue) Take(ready func(HeldMessage) bool) ([]HeldMessage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var taken, kept []HeldMessage
+	for _, msg := range q.messages {
+		if ready(msg) {
+			taken = append(taken, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	if len(taken) == 0 {
+		return nil, nil
+	}
+	q.messages = kept
+	return taken, filestore.SaveJSON(q.path, q.messages)
+}
+
+// Len returns the number of held messages
+func (q *HeldQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
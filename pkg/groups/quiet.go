@@ -0,0 +1,51 @@
+package groups
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+)
+
+// QuietHours is a daily period, possibly spanning midnight, during which
+// scheduled messages are held back
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseQuietHours parses a period such as "22:00-07:00"
+func ParseQuietHours(value string) (QuietHours, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return QuietHours{}, errors.New(constants.ErrInvalidQuietHours + ": " + value)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, errors.New(constants.ErrInvalidQuietHours + ": " + value)
+	}
+	end, err := parseClock(to)
+	if err != nil || start == end {
+		return QuietHours{}, errors.New(constants.ErrInvalidQuietHours + ": " + value)
+	}
+	return QuietHours{Start: start, End: end}, nil
+}
+
+// Contains reports whether the wall clock time of t falls in the period
+func (q QuietHours) Contains(t time.Time) bool {
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// parseClock parses a "15:04" time of day
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
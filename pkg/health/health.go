@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/response"
+)
+
+// Check statuses
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc reports whether a dependency of the bot is ready
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker serves the liveness and readiness endpoints
+type Checker struct {
+	checks  []check
+	timeout time.Duration
+}
+
+// NewChecker creates a Checker whose checks are each bounded by timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a named readiness check
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// HandleHealthz reports that the process is alive
+func (c *Checker) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, response.HealthResponse{Status: StatusOK})
+}
+
+// HandleReadyz runs every readiness check and reports the breakdown.
+// It answers 503 when any check fails.
+func (c *Checker) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result := c.Run(r.Context())
+	status := http.StatusOK
+	if result.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, result)
+}
+
+// Run executes all checks concurrently and aggregates their results
+func (c *Checker) Run(ctx context.Context) response.HealthResponse {
+	result := response.HealthResponse{
+		Status: StatusOK,
+		Checks: make(map[string]response.CheckResult, len(c.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			start := time.Now()
+			err := c.runCheck(ctx, chk)
+			res := response.CheckResult{
+				Status:     StatusOK,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				res.Status = StatusFail
+				res.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[chk.name] = res
+			if err != nil {
+				result.Status = StatusFail
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// runCheck runs a single check, giving up once the timeout elapses
+func (c *Checker) runCheck(ctx context.Context, chk check) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- chk.fn(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New(constants.ErrCheckTimedOut)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
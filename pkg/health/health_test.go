@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/response"
+)
+
+func TestRun(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("token expired") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		checks map[string]CheckFunc
+		status string
+		errors map[string]string
+	}{
+		{"no checks", nil, StatusOK, nil},
+		{"all pass", map[string]CheckFunc{"config": ok, "token": ok}, StatusOK, nil},
+		{"one fails", map[string]CheckFunc{"config": ok, "token": failing}, StatusFail, map[string]string{"token": "token expired"}},
+		{"timeout", map[string]CheckFunc{"schedule": hanging}, StatusFail, map[string]string{"schedule": constants.ErrCheckTimedOut}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(20 * time.Millisecond)
+			for name, fn := range tt.checks {
+				c.Add(name, fn)
+			}
+			result := c.Run(context.Background())
+			if result.Status != tt.status {
+				t.Errorf("status = %q, want %q", result.Status, tt.status)
+			}
+			if len(result.Checks) != len(tt.checks) {
+				t.Errorf("got %d check results, want %d", len(result.Checks), len(tt.checks))
+			}
+			for name, res := range result.Checks {
+				if res.Error != tt.errors[name] {
+					t.Errorf("check %s error = %q, want %q", name, res.Error, tt.errors[name])
+				}
+				if (res.Status == StatusOK) != (tt.errors[name] == "") {
+					t.Errorf("check %s status = %q", name, res.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	healthy := NewChecker(time.Second)
+	healthy.Add("config", func(ctx context.Context) error { return nil })
+	unhealthy := NewChecker(time.Second)
+	unhealthy.Add("token", func(ctx context.Context) error { return errors.New("down") })
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		status  int
+		body    string
+	}{
+		{"healthz", healthy.HandleHealthz, http.MethodGet, http.StatusOK, StatusOK},
+		{"healthz ignores checks", unhealthy.HandleHealthz, http.MethodGet, http.StatusOK, StatusOK},
+		{"readyz", healthy.HandleReadyz, http.MethodGet, http.StatusOK, StatusOK},
+		{"readyz failing", unhealthy.HandleReadyz, http.MethodGet, http.StatusServiceUnavailable, StatusFail},
+		{"wrong method", healthy.HandleReadyz, http.MethodPost, http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(tt.method, "/", nil))
+			if w.Code != tt.status {
+				t.Fatalf("status code = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body == "" {
+				return
+			}
+			var got response.HealthResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.body {
+				t.Errorf("status = %q, want %q", got.Status, tt.body)
+			}
+		})
+	}
+}
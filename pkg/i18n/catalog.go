@@ -0,0 +1,297 @@
+package i18n
+
+// Catalog keys
+const (
+	MsgUnknownCommand     = "command.unknown"
+	MsgCommandFailed      = "command.failed"
+	MsgHelpHeader         = "command.help.header"
+	MsgLangUsage          = "lang.usage"
+	MsgLangCurrent        = "lang.current"
+	MsgLangUnsupported    = "lang.unsupported"
+	MsgLangSetGroup       = "lang.set.group"
+	MsgLangSetUser        = "lang.set.user"
+	MsgCommandHelpLang    = "command.help.lang"
+	MsgCommandHelpHelp    = "command.help.help"
+	MsgLanguageEnglish    = "language.en"
+	MsgLanguageIndonesian = "language.id"
+	MsgCommandHelpWhois   = "command.help.whois"
+	MsgWhoisEntry         = "whois.entry"
+	MsgWhoisUnknown       = "whois.unknown"
+	MsgUnknownValue       = "value.unknown"
+
+	MsgCommandHelpSubscribe   = "command.help.subscribe"
+	MsgCommandHelpUnsubscribe = "command.help.unsubscribe"
+	MsgSubscribeUsage         = "subscribe.usage"
+	MsgUnsubscribeUsage       = "unsubscribe.usage"
+	MsgSubscribeDirectOnly    = "subscribe.direct_only"
+	MsgSubscribedPIC          = "subscribe.pic"
+	MsgSubscribedReminders    = "subscribe.reminders"
+	MsgSubscriptionsHeader    = "subscribe.header"
+	MsgNoSubscriptions        = "subscribe.none"
+
+	MsgCommandHelpConfig        = "command.help.config"
+	MsgConfigUsage              = "config.usage"
+	MsgConfigGroupOnly          = "config.group_only"
+	MsgConfigUnknownJob         = "config.unknown_job"
+	MsgConfigInvalidTimezone    = "config.invalid_timezone"
+	MsgConfigInvalidQuietHours  = "config.invalid_quiet_hours"
+	MsgConfigUpdated            = "config.updated"
+	MsgConfigSummary            = "config.summary"
+	MsgConfigAnswersUnavailable = "config.answers_unavailable"
+	MsgNone                     = "value.none"
+	MsgOn                       = "value.on"
+	MsgOff                      = "value.off"
+
+	MsgPermissionDenied  = "permission.denied"
+	MsgCommandHelpRole   = "command.help.role"
+	MsgRoleUsage         = "role.usage"
+	MsgRoleCurrent       = "role.current"
+	MsgRoleListGroup     = "role.list.group"
+	MsgRoleListGlobal    = "role.list.global"
+	MsgRoleNone          = "role.none"
+	MsgRoleGrantedGroup  = "role.granted.group"
+	MsgRoleGrantedGlobal = "role.granted.global"
+	MsgRoleRevokedGroup  = "role.revoked.group"
+	MsgRoleRevokedGlobal = "role.revoked.global"
+	MsgRoleConfigured    = "role.configured"
+
+	MsgCommandHelpAudit = "command.help.audit"
+	MsgAuditUsage       = "audit.usage"
+	MsgAuditHeader      = "audit.header"
+	MsgAuditEntry       = "audit.entry"
+	MsgAuditNone        = "audit.none"
+
+	MsgCommandHelpPIC       = "command.help.pic"
+	MsgPICUsage             = "pic.usage"
+	MsgPICHistoryHeader     = "pic.history.header"
+	MsgPICHistoryEntry      = "pic.history.entry"
+	MsgPICHistoryEmpty      = "pic.history.empty"
+	MsgPICVersionNotFound   = "pic.version.not_found"
+	MsgPICNothingToUndo     = "pic.undo.nothing"
+	MsgPICActionReplace     = "pic.action.replace"
+	MsgPICActionAdvance     = "pic.action.advance"
+	MsgPICActionRestore     = "pic.action.restore"
+	MsgPICActionEdit        = "pic.action.edit"
+	MsgPICActorUnknown      = "pic.actor.unknown"
+	MsgPICCalendar          = "pic.calendar"
+	MsgPICCalendarPersonal  = "pic.calendar.personal"
+	MsgPICCalendarDisabled  = "pic.calendar.disabled"
+	MsgPICExtended          = "pic.extend.done"
+	MsgPICExtendedEntry     = "pic.extend.entry"
+	MsgPICExtendNothing     = "pic.extend.nothing"
+	MsgPICExtendInitialized = "pic.extend.initialized"
+	MsgPICExtendNoMembers   = "pic.extend.no_members"
+	MsgCommandHelpFAQ       = "command.help.faq"
+	MsgFAQUsage             = "faq.usage"
+	MsgFAQHeader            = "faq.header"
+	MsgFAQEntry             = "faq.entry"
+	MsgFAQEmpty             = "faq.empty"
+	MsgFAQAdded             = "faq.added"
+	MsgFAQUpdated           = "faq.updated"
+	MsgFAQRemoved           = "faq.removed"
+	MsgFAQNotFound          = "faq.not_found"
+	MsgFAQInvalid           = "faq.invalid"
+	MsgFAQNoMatch           = "faq.no_match"
+	MsgFAQSuggest           = "faq.suggest"
+	MsgFAQSuggestFooter     = "faq.suggest.footer"
+	MsgFAQRelated           = "faq.related"
+)
+
+// catalog holds the bot messages of every locale
+var catalog = map[string]map[string]string{
+	English: {
+		MsgUnknownCommand:     "Unknown command /%s. Send /help to see what I can do.",
+		MsgCommandFailed:      "Sorry, /%s failed: %v",
+		MsgHelpHeader:         "Available commands:",
+		MsgLangUsage:          "Usage: /lang <en|id>",
+		MsgLangCurrent:        "Current language: %s. Change it with /lang <en|id>.",
+		MsgLangUnsupported:    "Unsupported language %q. Choose one of: %s.",
+		MsgLangSetGroup:       "This group will now receive messages in %s.",
+		MsgLangSetUser:        "I will now talk to you in %s.",
+		MsgCommandHelpLang:    "show or change the language",
+		MsgCommandHelpHelp:    "list the available commands",
+		MsgLanguageEnglish:    "English",
+		MsgLanguageIndonesian: "Bahasa Indonesia",
+		MsgCommandHelpWhois:   "show the profile of the mentioned users, emails or employee codes",
+		MsgWhoisEntry:         "%s - email: %s, employee code: %s, department: %s",
+		MsgWhoisUnknown:       "I could not find %s in the directory.",
+		MsgUnknownValue:       "unknown",
+
+		MsgCommandHelpSubscribe:   "get personal notifications in a direct message",
+		MsgCommandHelpUnsubscribe: "stop personal notifications",
+		MsgSubscribeUsage:         "Usage: /subscribe pic [days] or /subscribe reminders",
+		MsgUnsubscribeUsage:       "Usage: /unsubscribe pic, /unsubscribe reminders or /unsubscribe all",
+		MsgSubscribeDirectOnly:    "Notifications are sent in direct messages. Send me this command in a private chat.",
+		MsgSubscribedPIC:          "I will tell you %d day(s) before you are PIC.",
+		MsgSubscribedReminders:    "I will forward the scheduled reminders to you.",
+		MsgSubscriptionsHeader:    "Your notifications:",
+		MsgNoSubscriptions:        "You have no notifications. Send /subscribe pic or /subscribe reminders to get some.",
+
+		MsgCommandHelpConfig:        "show or change the settings of this group",
+		MsgConfigUsage:              "Usage: /config, /config jobs add|remove <job>, /config timezone <zone>, /config language <en|id>, /config quiet <HH:MM-HH:MM|off> or /config answers <on|off>",
+		MsgConfigGroupOnly:          "Group settings can only be viewed and changed from the group itself.",
+		MsgConfigUnknownJob:         "Unknown job %q. Jobs that can post to a group: %s.",
+		MsgConfigInvalidTimezone:    "Unknown timezone %q. Use a name such as Asia/Jakarta.",
+		MsgConfigInvalidQuietHours:  "Invalid quiet hours %q. Use HH:MM-HH:MM, e.g. 22:00-07:00, or off.",
+		MsgConfigUpdated:            "Settings updated.",
+		MsgConfigSummary:            "Settings of this group:\nJobs: %s\nTimezone: %s\nLanguage: %s\nQuiet hours: %s\nAnswers to questions: %s\nRoles: %s",
+		MsgConfigAnswersUnavailable: "Answers to free-form questions are not available: no answer backend is configured.",
+		MsgNone:                     "none",
+		MsgOn:                       "on",
+		MsgOff:                      "off",
+
+		MsgPermissionDenied:  "Sorry, /%s requires the %s role. This attempt was recorded.",
+		MsgCommandHelpRole:   "show, grant or revoke roles",
+		MsgRoleUsage:         "Usage: /role, /role list [global], /role grant <admin|owner> <@user|employee code> [global] or /role revoke <@user|employee code> [global]",
+		MsgRoleCurrent:       "Your role here is %s.",
+		MsgRoleListGroup:     "Roles in this group:",
+		MsgRoleListGlobal:    "Global roles:",
+		MsgRoleNone:          "Nobody holds a role here.",
+		MsgRoleGrantedGroup:  "%s is now %s in this group.",
+		MsgRoleGrantedGlobal: "%s is now %s everywhere.",
+		MsgRoleRevokedGroup:  "%s is no longer %s in this group.",
+		MsgRoleRevokedGlobal: "%s is no longer %s everywhere.",
+		MsgRoleConfigured:    "%s is an owner through the bot configuration, which cannot be changed from chat.",
+
+		MsgCommandHelpAudit: "show the latest audit log entries",
+		MsgAuditUsage:       "Usage: /audit [count] [action], e.g. /audit 20 schedule",
+		MsgAuditHeader:      "Latest %d audit entries:",
+		MsgAuditEntry:       "%s %s %s %s (%s)",
+		MsgAuditNone:        "No audit entries found.",
+
+		MsgCommandHelpPIC:       "show this week's PICs, the rotation history and calendar links, or extend, undo and restore the rotation",
+		MsgPICUsage:             "Usage: /pic, /pic history [count], /pic ical, /pic extend, /pic undo or /pic restore <version>",
+		MsgPICHistoryHeader:     "Latest versions of the %s rotation:",
+		MsgPICHistoryEntry:      "v%d %s: %s by %s, %d entries",
+		MsgPICHistoryEmpty:      "No changes to the %s rotation were recorded yet.",
+		MsgPICVersionNotFound:   "Version %s of the rotation is unknown. Send /pic history to see the versions kept.",
+		MsgPICNothingToUndo:     "There is no rotation change to undo.",
+		MsgPICActionReplace:     "replaced",
+		MsgPICActionAdvance:     "rotated",
+		MsgPICActionRestore:     "restored v%d",
+		MsgPICActionEdit:        "changed outside the bot",
+		MsgPICActorUnknown:      "unknown",
+		MsgPICCalendar:          "Subscribe to the %s rotation in your calendar app: %s",
+		MsgPICCalendarPersonal:  "Only your own turns: %s",
+		MsgPICCalendarDisabled:  "Calendar links are not available because PUBLIC_URL is not configured.",
+		MsgPICExtended:          "Planned %d new turns of the %s rotation:",
+		MsgPICExtendedEntry:     "%s - PIC: %s",
+		MsgPICExtendNothing:     "The %s rotation is already planned %d periods ahead.",
+		MsgPICExtendInitialized: "The %s rotation is now planned automatically every week, with %d members taken from the schedule.",
+		MsgPICExtendNoMembers:   "The %s rotation has no entries to take its members from. Import a schedule first.",
+		MsgCommandHelpFAQ:       "answer recurring questions, or add, edit and remove the answers",
+		MsgFAQUsage:             "Usage: /faq, /faq <number>, /faq <question>, /faq add <trigger>; <trigger> => <answer>, /faq edit <number> [<triggers>] => [<answer>] or /faq remove <number>. Start a trigger with re: to match a regular expression.",
+		MsgFAQHeader:            "Frequently asked questions:",
+		MsgFAQEntry:             "#%d %s",
+		MsgFAQEmpty:             "No FAQ entries yet. Admins can add one with /faq add <trigger> => <answer>.",
+		MsgFAQAdded:             "FAQ #%d added, answering: %s",
+		MsgFAQUpdated:           "FAQ #%d updated, answering: %s",
+		MsgFAQRemoved:           "FAQ #%d removed.",
+		MsgFAQNotFound:          "FAQ #%v does not exist. Send /faq to see the entries.",
+		MsgFAQInvalid:           "The FAQ entry is invalid: %s",
+		MsgFAQNoMatch:           "No FAQ entry matches \"%s\". Send /faq to see the entries.",
+		MsgFAQSuggest:           "I am not sure what you mean. The closest questions are:",
+		MsgFAQSuggestFooter:     "Send /faq <number> to see an answer.",
+		MsgFAQRelated:           "Related questions:",
+	},
+	Indonesian: {
+		MsgUnknownCommand:     "Perintah /%s tidak dikenal. Kirim /help untuk melihat apa yang bisa saya lakukan.",
+		MsgCommandFailed:      "Maaf, /%s gagal: %v",
+		MsgHelpHeader:         "Perintah yang tersedia:",
+		MsgLangUsage:          "Penggunaan: /lang <en|id>",
+		MsgLangCurrent:        "Bahasa saat ini: %s. Ubah dengan /lang <en|id>.",
+		MsgLangUnsupported:    "Bahasa %q tidak didukung. Pilih salah satu: %s.",
+		MsgLangSetGroup:       "Grup ini sekarang akan menerima pesan dalam %s.",
+		MsgLangSetUser:        "Saya sekarang akan berbicara dengan Anda dalam %s.",
+		MsgCommandHelpLang:    "tampilkan atau ubah bahasa",
+		MsgCommandHelpHelp:    "tampilkan daftar perintah",
+		MsgLanguageEnglish:    "Bahasa Inggris",
+		MsgLanguageIndonesian: "Bahasa Indonesia",
+		MsgCommandHelpWhois:   "tampilkan profil pengguna yang di-mention, email, atau kode karyawan",
+		MsgWhoisEntry:         "%s - email: %s, kode karyawan: %s, departemen: %s",
+		MsgWhoisUnknown:       "Saya tidak dapat menemukan %s di direktori.",
+		MsgUnknownValue:       "tidak diketahui",
+
+		MsgCommandHelpSubscribe:   "dapatkan notifikasi pribadi lewat pesan langsung",
+		MsgCommandHelpUnsubscribe: "hentikan notifikasi pribadi",
+		MsgSubscribeUsage:         "Penggunaan: /subscribe pic [hari] atau /subscribe reminders",
+		MsgUnsubscribeUsage:       "Penggunaan: /unsubscribe pic, /unsubscribe reminders atau /unsubscribe all",
+		MsgSubscribeDirectOnly:    "Notifikasi dikirim lewat pesan langsung. Kirim perintah ini kepada saya di chat pribadi.",
+		MsgSubscribedPIC:          "Saya akan memberi tahu Anda %d hari sebelum Anda menjadi PIC.",
+		MsgSubscribedReminders:    "Saya akan meneruskan pengingat terjadwal kepada Anda.",
+		MsgSubscriptionsHeader:    "Notifikasi Anda:",
+		MsgNoSubscriptions:        "Anda tidak memiliki notifikasi. Kirim /subscribe pic atau /subscribe reminders untuk mendapatkannya.",
+
+		MsgCommandHelpConfig:        "tampilkan atau ubah pengaturan grup ini",
+		MsgConfigUsage:              "Penggunaan: /config, /config jobs add|remove <job>, /config timezone <zona>, /config language <en|id>, /config quiet <HH:MM-HH:MM|off> atau /config answers <on|off>",
+		MsgConfigGroupOnly:          "Pengaturan grup hanya dapat dilihat dan diubah dari grup itu sendiri.",
+		MsgConfigUnknownJob:         "Job %q tidak dikenal. Job yang dapat mengirim ke grup: %s.",
+		MsgConfigInvalidTimezone:    "Zona waktu %q tidak dikenal. Gunakan nama seperti Asia/Jakarta.",
+		MsgConfigInvalidQuietHours:  "Jam tenang %q tidak valid. Gunakan HH:MM-HH:MM, misalnya 22:00-07:00, atau off.",
+		MsgConfigUpdated:            "Pengaturan diperbarui.",
+		MsgConfigSummary:            "Pengaturan grup ini:\nJob: %s\nZona waktu: %s\nBahasa: %s\nJam tenang: %s\nJawaban pertanyaan: %s\nPeran: %s",
+		MsgConfigAnswersUnavailable: "Jawaban untuk pertanyaan bebas tidak tersedia: belum ada backend jawaban yang dikonfigurasi.",
+		MsgNone:                     "tidak ada",
+		MsgOn:                       "aktif",
+		MsgOff:                      "nonaktif",
+
+		MsgPermissionDenied:  "Maaf, /%s membutuhkan peran %s. Percobaan ini telah dicatat.",
+		MsgCommandHelpRole:   "tampilkan, berikan, atau cabut peran",
+		MsgRoleUsage:         "Penggunaan: /role, /role list [global], /role grant <admin|owner> <@pengguna|kode karyawan> [global] atau /role revoke <@pengguna|kode karyawan> [global]",
+		MsgRoleCurrent:       "Peran Anda di sini adalah %s.",
+		MsgRoleListGroup:     "Peran di grup ini:",
+		MsgRoleListGlobal:    "Peran global:",
+		MsgRoleNone:          "Belum ada yang memegang peran di sini.",
+		MsgRoleGrantedGroup:  "%s sekarang menjadi %s di grup ini.",
+		MsgRoleGrantedGlobal: "%s sekarang menjadi %s di semua tempat.",
+		MsgRoleRevokedGroup:  "%s tidak lagi menjadi %s di grup ini.",
+		MsgRoleRevokedGlobal: "%s tidak lagi menjadi %s di semua tempat.",
+		MsgRoleConfigured:    "%s adalah owner melalui konfigurasi bot, yang tidak dapat diubah dari chat.",
+
+		MsgCommandHelpAudit: "tampilkan entri log audit terbaru",
+		MsgAuditUsage:       "Penggunaan: /audit [jumlah] [aksi], misalnya /audit 20 schedule",
+		MsgAuditHeader:      "%d entri audit terbaru:",
+		MsgAuditEntry:       "%s %s %s %s (%s)",
+		MsgAuditNone:        "Tidak ada entri audit.",
+
+		MsgCommandHelpPIC:       "tampilkan PIC minggu ini, riwayat rotasi dan tautan kalender, atau perpanjang, batalkan dan pulihkan rotasi",
+		MsgPICUsage:             "Penggunaan: /pic, /pic history [jumlah], /pic ical, /pic extend, /pic undo atau /pic restore <versi>",
+		MsgPICHistoryHeader:     "Versi terbaru rotasi %s:",
+		MsgPICHistoryEntry:      "v%d %s: %s oleh %s, %d entri",
+		MsgPICHistoryEmpty:      "Belum ada perubahan rotasi %s yang tercatat.",
+		MsgPICVersionNotFound:   "Versi %s dari rotasi tidak dikenal. Kirim /pic history untuk melihat versi yang tersimpan.",
+		MsgPICNothingToUndo:     "Tidak ada perubahan rotasi yang bisa dibatalkan.",
+		MsgPICActionReplace:     "diganti",
+		MsgPICActionAdvance:     "dirotasi",
+		MsgPICActionRestore:     "dipulihkan ke v%d",
+		MsgPICActionEdit:        "diubah di luar bot",
+		MsgPICActorUnknown:      "tidak diketahui",
+		MsgPICCalendar:          "Langganan rotasi %s di aplikasi kalender Anda: %s",
+		MsgPICCalendarPersonal:  "Hanya giliran Anda sendiri: %s",
+		MsgPICCalendarDisabled:  "Tautan kalender tidak tersedia karena PUBLIC_URL belum dikonfigurasi.",
+		MsgPICExtended:          "%d giliran baru rotasi %s telah direncanakan:",
+		MsgPICExtendedEntry:     "%s - PIC: %s",
+		MsgPICExtendNothing:     "Rotasi %s sudah direncanakan %d periode ke depan.",
+		MsgPICExtendInitialized: "Rotasi %s sekarang direncanakan otomatis setiap minggu, dengan %d anggota dari jadwal.",
+		MsgPICExtendNoMembers:   "Rotasi %s tidak memiliki entri untuk menentukan anggotanya. Impor jadwal terlebih dahulu.",
+		MsgCommandHelpFAQ:       "jawab pertanyaan yang sering diajukan, atau tambah, ubah dan hapus jawabannya",
+		MsgFAQUsage:             "Penggunaan: /faq, /faq <nomor>, /faq <pertanyaan>, /faq add <pemicu>; <pemicu> => <jawaban>, /faq edit <nomor> [<pemicu>] => [<jawaban>] atau /faq remove <nomor>. Awali pemicu dengan re: untuk mencocokkan ekspresi reguler.",
+		MsgFAQHeader:            "Pertanyaan yang sering diajukan:",
+		MsgFAQEntry:             "#%d %s",
+		MsgFAQEmpty:             "Belum ada entri FAQ. Admin dapat menambahkannya dengan /faq add <pemicu> => <jawaban>.",
+		MsgFAQAdded:             "FAQ #%d ditambahkan, menjawab: %s",
+		MsgFAQUpdated:           "FAQ #%d diperbarui, menjawab: %s",
+		MsgFAQRemoved:           "FAQ #%d dihapus.",
+		MsgFAQNotFound:          "FAQ #%v tidak ada. Kirim /faq untuk melihat entrinya.",
+		MsgFAQInvalid:           "Entri FAQ tidak valid: %s",
+		MsgFAQNoMatch:           "Tidak ada entri FAQ yang cocok dengan \"%s\". Kirim /faq untuk melihat entrinya.",
+		MsgFAQSuggest:           "Saya kurang yakin maksud Anda. Pertanyaan terdekat:",
+		MsgFAQSuggestFooter:     "Kirim /faq <nomor> untuk melihat jawabannya.",
+		MsgFAQRelated:           "Pertanyaan terkait:",
+	},
+}
+
+// LanguageName returns the name of a locale, spelled in the given display locale
+func LanguageName(displayLocale, locale string) string {
+	return T(displayLocale, "language."+locale)
+}
@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported locales
+const (
+	English    = "en"
+	Indonesian = "id"
+
+	// DefaultLocale is used when no preference is set
+	DefaultLocale = English
+)
+
+// aliases maps accepted spellings of a locale to its code
+var aliases = map[string]string{
+	"en":         English,
+	"en-us":      English,
+	"en-gb":      English,
+	"english":    English,
+	"id":         Indonesian,
+	"id-id":      Indonesian,
+	"in":         Indonesian,
+	"indonesian": Indonesian,
+	"indonesia":  Indonesian,
+	"bahasa":     Indonesian,
+}
+
+// Supported returns the codes of the supported locales
+func Supported() []string {
+	return []string{English, Indonesian}
+}
+
+// Normalize maps a locale name such as "ID" or "id-ID" to a supported locale code
+func Normalize(locale string) (string, bool) {
+	code, ok := aliases[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))]
+	return code, ok
+}
+
+// T returns the catalog message for key in the locale, formatted with args.
+// Messages missing from the locale fall back to English, then to the key itself.
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalog[locale][key]
+	if !ok {
+		message, ok = catalog[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// calendarNames holds the localized month and weekday names of a locale
+type calendarNames struct {
+	months      [12]string
+	shortMonths [12]string
+	days        [7]string
+	shortDays   [7]string
+}
+
+var calendars = map[string]calendarNames{
+	Indonesian: {
+		months:      [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+		days:        [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+		shortDays:   [7]string{"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"},
+	},
+}
+
+// nameTokens are the layout elements spelling out month and weekday names,
+// longest first so "January" is not read as "Jan"
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// FormatDate formats t with a Go time layout, spelling month and weekday names in the locale
+func FormatDate(locale, layout string, t time.Time) string {
+	names, ok := calendars[locale]
+	if !ok {
+		return t.Format(layout)
+	}
+
+	// Format the layout piece by piece so localized names are never
+	// reinterpreted as layout elements
+	var result strings.Builder
+	rest := layout
+	for rest != "" {
+		index, token := nextNameToken(rest)
+		if index < 0 {
+			result.WriteString(t.Format(rest))
+			break
+		}
+		if index > 0 {
+			result.WriteString(t.Format(rest[:index]))
+		}
+		switch token {
+		case "January":
+			result.WriteString(names.months[t.Month()-1])
+		case "Jan":
+			result.WriteString(names.shortMonths[t.Month()-1])
+		case "Monday":
+			result.WriteString(names.days[t.Weekday()])
+		case "Mon":
+			result.WriteString(names.shortDays[t.Weekday()])
+		}
+		rest = rest[index+len(token):]
+	}
+	return result.String()
+}
+
+// nextNameToken finds the first month or weekday name element in layout
+func nextNameToken(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		for _, token := range nameTokens {
+			if strings.HasPrefix(layout[i:], token) {
+				return i, token
+			}
+		}
+	}
+	return -1, ""
+}
@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// verbPattern matches the fmt verbs of a catalog message
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// verbs returns the fmt verbs of message in order, leaving out escaped percent signs
+func verbs(message string) string {
+	var found []string
+	for _, verb := range verbPattern.FindAllString(message, -1) {
+		if verb != "%%" {
+			found = append(found, verb)
+		}
+	}
+	return strings.Join(found, " ")
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for _, locale := range Supported() {
+		if len(catalog[locale]) == 0 {
+			t.Fatalf("no catalog for %s", locale)
+		}
+	}
+	keys := make(map[string]bool)
+	for _, messages := range catalog {
+		for key := range messages {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		english, ok := catalog[English][key]
+		if !ok {
+			t.Errorf("%s is missing from %s", key, English)
+			continue
+		}
+		for _, locale := range Supported() {
+			message, ok := catalog[locale][key]
+			if !ok {
+				t.Errorf("%s is missing from %s", key, locale)
+				continue
+			}
+			if verbs(message) != verbs(english) {
+				t.Errorf("%s in %s has verbs %q, want %q as in %s", key, locale, verbs(message), verbs(english), English)
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		locale string
+		key    string
+		args   []interface{}
+		want   string
+	}{
+		{English, MsgLangSetUser, []interface{}{"English"}, "I will now talk to you in English."},
+		{English, MsgHelpHeader, nil, "Available commands:"},
+		{"fr", MsgHelpHeader, nil, "Available commands:"},
+		{English, "no.such.key", nil, "no.such.key"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.locale, tt.key, tt.args...); got != tt.want {
+			t.Errorf("T(%s, %s) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+	if T(Indonesian, MsgHelpHeader) == T(English, MsgHelpHeader) {
+		t.Errorf("%s is not translated to %s", MsgHelpHeader, Indonesian)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{"en", English, true},
+		{" EN_gb ", English, true},
+		{"id-ID", Indonesian, true},
+		{"Bahasa", Indonesian, true},
+		{"fr", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Normalize(tt.locale)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, time.August, 20, 14, 5, 0, 0, time.UTC)
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{English, "Monday, 2 January 2006", "Tuesday, 20 August 2024"},
+		{Indonesian, "Monday, 2 January 2006", "Selasa, 20 Agustus 2024"},
+		{Indonesian, "Mon 2 Jan 2006 15:04", "Sel 20 Agu 2024 14:05"},
+		{Indonesian, "2006-01-02", "2024-08-20"},
+		// Localized names are never read as layout elements
+		{Indonesian, "January Jan", "Agustus Agu"},
+		{Indonesian, "Monday (Mon)", "Selasa (Sel)"},
+		{"fr", "2 Jan", "20 Aug"},
+	}
+	for _, tt := range tests {
+		if got := FormatDate(tt.locale, tt.layout, date); got != tt.want {
+			t.Errorf("FormatDate(%s, %q) = %q, want %q", tt.locale, tt.layout, got, tt.want)
+		}
+	}
+
+	// Months whose Indonesian names contain layout elements
+	for month, want := range map[time.Month]string{time.January: "Januari", time.May: "Mei", time.December: "Desember"} {
+		if got := FormatDate(Indonesian, "January", time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)); got != want {
+			t.Errorf("FormatDate(id, January) in %s = %q, want %q", month, got, want)
+		}
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locales.json")
+	p, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.UserLocale("E001"); got != DefaultLocale {
+		t.Errorf("UserLocale() = %q before any preference, want %q", got, DefaultLocale)
+	}
+	if err := p.SetUserLocale("E001", "Indonesian"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetGroupLocale("g1", "id-ID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetGroupLocale("g1", "klingon"); err == nil {
+		t.Error("SetGroupLocale() accepted an unsupported locale")
+	}
+
+	reloaded, err := LoadPreferences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.UserLocale("E001"); got != Indonesian {
+		t.Errorf("reloaded UserLocale() = %q, want %q", got, Indonesian)
+	}
+	if got := reloaded.GroupLocale("g1"); got != Indonesian {
+		t.Errorf("reloaded GroupLocale() = %q, want %q", got, Indonesian)
+	}
+	if got := reloaded.GroupLocale("g2"); got != DefaultLocale {
+		t.Errorf("GroupLocale() of another group = %q, want %q", got, DefaultLocale)
+	}
+}
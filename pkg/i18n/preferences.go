@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"errors"
+	"sync"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+)
+
+// preferencesData is the persisted form of the locale preferences
+type preferencesData struct {
+	Groups map[string]string `json:"groups"`
+	Users  map[string]string `json:"users"`
+}
+
+// Preferences stores the locale chosen per group and per user
+type Preferences struct {
+	path string
+	mu   sync.RWMutex
+	data preferencesData
+}
+
+// LoadPreferences loads the locale preferences persisted at path
+func LoadPreferences(path string) (*Preferences, error) {
+	p := &Preferences{
+		path: path,
+		data: preferencesData{
+			Groups: make(map[string]string),
+			Users:  make(map[string]string),
+		},
+	}
+	if err := filestore.LoadJSON(path, &p.data); err != nil {
+		return nil, err
+	}
+	if p.data.Groups == nil {
+		p.data.Groups = make(map[string]string)
+	}
+	if p.data.Users == nil {
+		p.data.Users = make(map[string]string)
+	}
+	return p, nil
+}
+
+// GroupLocale returns the locale of a group, or the default locale
+func (p *Preferences) GroupLocale(groupID string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if locale, ok := p.data.Groups[groupID]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// UserLocale returns the locale of an employee, or the default locale
+func (p *Preferences) UserLocale(employeeCode string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if locale, ok := p.data.Users[employeeCode]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// SetGroupLocale stores the locale of a group
+func (p *Preferences) SetGroupLocale(groupID, locale string) error {
+	return p.set(p.data.Groups, groupID, locale)
+}
+
+// SetUserLocale stores the locale of an employee
+func (p *Preferences) SetUserLocale(employeeCode, locale string) error {
+	return p.set(p.data.Users, employeeCode, locale)
+}
+
+func (p *Preferences) set(target map[string]string, key, locale string) error {
+	code, ok := Normalize(locale)
+	if !ok {
+		return errors.New(constants.ErrUnsupportedLocale + ": " + locale)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	target[key] = code
+	return filestore.SaveJSON(p.path, p.data)
+}
@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+)
+
+// Row is a row of an imported sheet with its line number
+type Row struct {
+	Line  int
+	Cells []string
+}
+
+// readCSV reads comma, semicolon or tab separated rows, guessing the
+// separator from the first line as spreadsheet exports vary by locale
+func readCSV(data []byte) ([]Row, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	first, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+	separator := ','
+	best := strings.Count(string(first), ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if n := strings.Count(string(first), string(candidate)); n > best {
+			separator, best = candidate, n
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var rows []Row
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.New(constants.ErrInvalidImport + ": " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, Row{Line: line, Cells: cells})
+	}
+}
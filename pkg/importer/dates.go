@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+)
+
+// dateLayouts are the date formats accepted in imported sheets. Dates with
+// slashes are read day first.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2-Jan-2006",
+	"2-January-2006",
+	"2 Jan 2006",
+	"2 January 2006",
+	"02/01/2006",
+	"2/1/2006",
+	"02-01-2006",
+	"02.01.2006",
+	"2006/01/02",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Monday, 2 January 2006",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// excelEpoch is day zero of spreadsheet serial dates
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// ParseDate reads a date in any of the accepted formats, or a spreadsheet
+// serial date, returning the calendar day at UTC midnight
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	// Spreadsheets store dates as days since 1899-12-30
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 2958466 {
+		return excelEpoch.AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, errors.New(constants.ErrorDateParse + ": " + value)
+}
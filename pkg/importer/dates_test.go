@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		layout string
+		value  string
+	}{
+		{"2006-01-02", "2026-10-20"},
+		{"2-Jan-2006", "20-Oct-2026"},
+		{"2-January-2006", "20-October-2026"},
+		{"2 Jan 2006", "20 Oct 2026"},
+		{"2 January 2006", "20 October 2026"},
+		{"02/01/2006", "20/10/2026"},
+		{"2/1/2006", "20/10/2026"},
+		{"02-01-2006", "20-10-2026"},
+		{"02.01.2006", "20.10.2026"},
+		{"2006/01/02", "2026/10/20"},
+		{"Jan 2, 2006", "Oct 20, 2026"},
+		{"January 2, 2006", "October 20, 2026"},
+		{"Monday, 2 January 2006", "Tuesday, 20 October 2026"},
+		{"RFC 3339", "2026-10-20T23:30:00+07:00"},
+		{"2006-01-02 15:04:05", "2026-10-20 23:30:00"},
+		{"2006-01-02T15:04:05", "2026-10-20T23:30:00"},
+		{"spreadsheet serial", "46315"},
+		{"spreadsheet serial with a time", "46315.75"},
+		{"surrounding spaces", "  2026-10-20 "},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.value)
+		if err != nil || !got.Equal(want) || got.Location() != time.UTC {
+			t.Errorf("%s: ParseDate(%q) = %v, %v, want %v", tt.layout, tt.value, got, err, want)
+		}
+	}
+}
+
+func TestParseDateIsDayFirst(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"02/01/2026", time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"3/4/2026", time.Date(2026, time.April, 3, 0, 0, 0, 0, time.UTC)},
+		{"05-06-2026", time.Date(2026, time.June, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got, err := ParseDate(tt.value); err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDateRejects(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"next tuesday",
+		"10/20/2026", // month first
+		"2026-13-01",
+		"0",
+		"-5",
+		"2958466", // past the last spreadsheet date
+	} {
+		if got, err := ParseDate(value); err == nil {
+			t.Errorf("ParseDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestSerialDates(t *testing.T) {
+	tests := []struct {
+		serial string
+		want   time.Time
+	}{
+		{"1", time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"61", time.Date(1900, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"45658", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"46022", time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got, err := ParseDate(tt.serial); err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%s) = %v, %v, want %v", tt.serial, got, err, tt.want)
+		}
+	}
+}
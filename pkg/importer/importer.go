@@ -0,0 +1,210 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Formats of imported files
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Fields of a schedule entry
+const (
+	FieldName  = schedule.FieldName
+	FieldDate  = schedule.FieldDate
+	FieldEmail = schedule.FieldEmail
+)
+
+// headerAliases are the header names recognized for each field, compared
+// ignoring case, spaces, dashes and underscores
+var headerAliases = map[string][]string{
+	FieldName:  {"name", "pic", "person", "member", "fullname", "nama", "picname"},
+	FieldDate:  {"date", "day", "week", "weekof", "tanggal", "schedule", "dutydate"},
+	FieldEmail: {"email", "mail", "emailaddress", "picemail", "surel"},
+}
+
+// Columns maps fields to the header of the column holding them, overriding
+// the recognized header names
+type Columns struct {
+	Name  string
+	Date  string
+	Email string
+}
+
+// Result is the outcome of reading an imported file
+type Result struct {
+	// Schedules holds the valid entries, sorted by date
+	Schedules []schedule.Schedule
+	// Rows is how many non-empty data rows the file holds
+	Rows   int
+	Issues []schedule.Issue
+}
+
+// Valid reports whether every row could be imported
+func (r Result) Valid() bool {
+	return !schedule.HasErrors(r.Issues)
+}
+
+// Parse reads the schedule entries of a CSV or XLSX file. An empty format is
+// detected from the content. Rows that cannot be imported are reported as
+// issues with their line number.
+func Parse(data []byte, format string, columns Columns) (Result, error) {
+	if format == "" {
+		format = FormatCSV
+		if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+			format = FormatXLSX
+		}
+	}
+
+	var rows []Row
+	var err error
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		rows, err = readCSV(data)
+	case FormatXLSX:
+		rows, err = readXLSX(data)
+	default:
+		return Result{}, errors.New(constants.ErrInvalidImport + ": unsupported format " + format)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	rows = nonEmpty(rows)
+	if len(rows) == 0 {
+		return Result{}, errors.New(constants.ErrInvalidImport + ": no rows")
+	}
+
+	index, header, err := locateColumns(rows[0], columns)
+	if err != nil {
+		return Result{}, err
+	}
+	if header {
+		rows = rows[1:]
+	}
+	return readEntries(rows, index), nil
+}
+
+// locateColumns finds the column of each field in the first row. Files
+// without a header row must hold the name, date and email columns in this
+// order, like schedule files.
+func locateColumns(first Row, columns Columns) (map[string]int, bool, error) {
+	overrides := map[string]string{FieldName: columns.Name, FieldDate: columns.Date, FieldEmail: columns.Email}
+	index := make(map[string]int)
+	for i, cell := range first.Cells {
+		header := normalizeHeader(cell)
+		for field, aliases := range headerAliases {
+			if _, found := index[field]; found {
+				continue
+			}
+			if override := overrides[field]; override != "" {
+				if header == normalizeHeader(override) {
+					index[field] = i
+				}
+				continue
+			}
+			for _, alias := range aliases {
+				if header == alias {
+					index[field] = i
+				}
+			}
+		}
+	}
+
+	if len(index) == 0 && len(first.Cells) >= 2 {
+		if _, err := ParseDate(first.Cells[1]); err == nil {
+			return map[string]int{FieldName: 0, FieldDate: 1, FieldEmail: 2}, false, nil
+		}
+	}
+	for _, field := range []string{FieldName, FieldDate} {
+		if _, found := index[field]; !found {
+			return nil, false, errors.New(constants.ErrImportColumnMissing + " " + field)
+		}
+	}
+	return index, true, nil
+}
+
+// readEntries turns data rows into schedule entries, reporting invalid rows
+func readEntries(rows []Row, index map[string]int) Result {
+	result := Result{Rows: len(rows)}
+	seen := make(map[string]int)
+	for _, row := range rows {
+		name := cell(row, index, FieldName)
+		rawDate := cell(row, index, FieldDate)
+		email := cell(row, index, FieldEmail)
+
+		var issues []schedule.Issue
+		fail := func(code, field, value, message string) {
+			issues = append(issues, schedule.Issue{Line: row.Line, Severity: schedule.SeverityError, Code: code, Field: field, Value: value, Message: message})
+		}
+
+		switch {
+		case name == "":
+			fail(schedule.CodeName, FieldName, name, "name is empty")
+		case strings.Contains(name, ","):
+			fail(schedule.CodeName, FieldName, name, "name cannot contain a comma")
+		}
+		date, err := ParseDate(rawDate)
+		if err != nil {
+			fail(schedule.CodeDate, FieldDate, rawDate, "date is not in a known format")
+		}
+		if email == "" {
+			issues = append(issues, schedule.Issue{Line: row.Line, Severity: schedule.SeverityWarning, Code: schedule.CodeEmail, Field: FieldEmail,
+				Message: "email is empty, the PIC cannot be mentioned"})
+		} else if !schedule.ValidEmail(email) {
+			fail(schedule.CodeEmail, FieldEmail, email, "email is malformed")
+		}
+
+		if !schedule.HasErrors(issues) {
+			key := strings.ToLower(name) + "|" + date.Format("2006-01-02")
+			if line, found := seen[key]; found {
+				fail(schedule.CodeDuplicate, "", "", "duplicate of line "+strconv.Itoa(line))
+			} else {
+				seen[key] = row.Line
+				result.Schedules = append(result.Schedules, schedule.Schedule{PIC: name, Date: date, Email: email})
+			}
+		}
+		result.Issues = append(result.Issues, issues...)
+	}
+
+	sort.SliceStable(result.Schedules, func(i, j int) bool {
+		return result.Schedules[i].Date.Before(result.Schedules[j].Date)
+	})
+	return result
+}
+
+// cell returns the trimmed value of a field in a row
+func cell(row Row, index map[string]int, field string) string {
+	i, found := index[field]
+	if !found || i >= len(row.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(row.Cells[i])
+}
+
+// nonEmpty drops the rows without any value
+func nonEmpty(rows []Row) []Row {
+	kept := rows[:0]
+	for _, row := range rows {
+		for _, c := range row.Cells {
+			if strings.TrimSpace(c) != "" {
+				kept = append(kept, row)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// normalizeHeader lowercases a header and drops spaces, dashes and underscores
+func normalizeHeader(header string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "", ".", "").Replace(strings.ToLower(strings.TrimSpace(header)))
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/schedule"
+)
+
+// oct returns the date of a day in October 2026
+func oct(day int) time.Time {
+	return time.Date(2026, time.October, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCSV(t *testing.T) {
+	want := []schedule.Schedule{
+		{PIC: "Alice", Date: oct(20), Email: "alice@example.com"},
+		{PIC: "Bob", Date: oct(27), Email: "bob@example.com"},
+	}
+	tests := []struct {
+		name    string
+		data    string
+		columns Columns
+	}{
+		{"comma", "Name,Date,Email\nAlice,2026-10-20,alice@example.com\nBob,2026-10-27,bob@example.com\n", Columns{}},
+		{"semicolon", "Name;Date;Email\nAlice;20/10/2026;alice@example.com\nBob;27/10/2026;bob@example.com\n", Columns{}},
+		{"tab", "Name\tDate\tEmail\nAlice\t20-Oct-2026\talice@example.com\nBob\t27-Oct-2026\tbob@example.com\n", Columns{}},
+		{"semicolon with commas in dates", "PIC;Date;Email\nAlice;Oct 20, 2026;alice@example.com\nBob;Oct 27, 2026;bob@example.com\n", Columns{}},
+		{"byte order mark and CRLF", "\xef\xbb\xbfName,Date,Email\r\nAlice,2026-10-20,alice@example.com\r\nBob,2026-10-27,bob@example.com\r\n", Columns{}},
+		{"unsorted with blank rows", "Name,Date,Email\nBob,2026-10-27,bob@example.com\n,,\n\nAlice,2026-10-20,alice@example.com\n", Columns{}},
+		{"aliases", "Nama,Tanggal,Surel\nAlice,2026-10-20,alice@example.com\nBob,2026-10-27,bob@example.com\n", Columns{}},
+		{"aliases ignore case and separators", "PIC Name,Duty-Date,E_Mail Address\nAlice,2026-10-20,alice@example.com\nBob,2026-10-27,bob@example.com\n", Columns{}},
+		{"columns in any order", "Email,Team,Week of,Person\nalice@example.com,ops,2026-10-20,Alice\nbob@example.com,ops,2026-10-27,Bob\n", Columns{}},
+		{
+			"overrides", "Owner,Date,Backup,Contact\nAlice,2026-10-20,Carol,alice@example.com\nBob,2026-10-27,Dave,bob@example.com\n",
+			Columns{Name: "owner", Email: "Contact"},
+		},
+		{
+			"overrides win over aliases", "Name,On call,Date,Email\nOps,Alice,2026-10-20,alice@example.com\nOps,Bob,2026-10-27,bob@example.com\n",
+			Columns{Name: "On Call"},
+		},
+		{"headerless", "Alice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026,bob@example.com\n", Columns{}},
+	}
+	for _, tt := range tests {
+		result, err := Parse([]byte(tt.data), "", tt.columns)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(result.Schedules, want) || result.Rows != 2 || !result.Valid() {
+			t.Errorf("%s: Parse() = %+v, want %+v", tt.name, result, want)
+		}
+	}
+}
+
+func TestParseIssues(t *testing.T) {
+	data := strings.Join([]string{
+		"Name,Date,Email",
+		"Alice,2026-10-20,alice@example.com",
+		"Bob,2026-10-27,",
+		"alice,20/10/2026,alice@example.com",
+		",2026-11-03,carol@example.com",
+		"Dave,next week,dave@example.com",
+		"Erin,2026-11-10,erin at example.com",
+		`"Frank, Jr",2026-11-17,frank@example.com`,
+		"Gina,2026-11-24,Gina <gina@example.com>",
+	}, "\n")
+	result, err := Parse([]byte(data), FormatCSV, Columns{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []schedule.Issue{
+		{Line: 3, Severity: schedule.SeverityWarning, Code: schedule.CodeEmail, Field: FieldEmail, Message: "email is empty, the PIC cannot be mentioned"},
+		{Line: 4, Severity: schedule.SeverityError, Code: schedule.CodeDuplicate, Message: "duplicate of line 2"},
+		{Line: 5, Severity: schedule.SeverityError, Code: schedule.CodeName, Field: FieldName, Message: "name is empty"},
+		{Line: 6, Severity: schedule.SeverityError, Code: schedule.CodeDate, Field: FieldDate, Value: "next week", Message: "date is not in a known format"},
+		{Line: 7, Severity: schedule.SeverityError, Code: schedule.CodeEmail, Field: FieldEmail, Value: "erin at example.com", Message: "email is malformed"},
+		{Line: 8, Severity: schedule.SeverityError, Code: schedule.CodeName, Field: FieldName, Value: "Frank, Jr", Message: "name cannot contain a comma"},
+		{Line: 9, Severity: schedule.SeverityError, Code: schedule.CodeEmail, Field: FieldEmail, Value: "Gina <gina@example.com>", Message: "email is malformed"},
+	}
+	if !reflect.DeepEqual(result.Issues, want) {
+		t.Errorf("issues =\n%+v\nwant\n%+v", result.Issues, want)
+	}
+	if result.Valid() || result.Rows != 8 || len(result.Schedules) != 2 {
+		t.Errorf("Parse() = %d schedules of %d rows, valid %v", len(result.Schedules), result.Rows, result.Valid())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		columns Columns
+		want    string
+	}{
+		{"empty file", "", FormatCSV, Columns{}, constants.ErrInvalidImport + ": no rows"},
+		{"only blank rows", ",,\n\n", FormatCSV, Columns{}, constants.ErrInvalidImport + ": no rows"},
+		{"unsupported format", "Name,Date", "ods", Columns{}, constants.ErrInvalidImport + ": unsupported format ods"},
+		{"no name column", "Date,Email\n2026-10-20,alice@example.com\n", FormatCSV, Columns{}, constants.ErrImportColumnMissing + " " + FieldName},
+		{"no date column", "Name,Email\nAlice,alice@example.com\n", FormatCSV, Columns{}, constants.ErrImportColumnMissing + " " + FieldDate},
+		{"overridden column missing", "Name,Date\nAlice,2026-10-20\n", FormatCSV, Columns{Date: "Week"}, constants.ErrImportColumnMissing + " " + FieldDate},
+		{"headerless without a date", "Alice,soon,alice@example.com\n", FormatCSV, Columns{}, constants.ErrImportColumnMissing + " " + FieldName},
+		{"not a workbook", "Name,Date", FormatXLSX, Columns{}, constants.ErrInvalidImport + ": zip: not a valid zip file"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.data), tt.format, tt.columns); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
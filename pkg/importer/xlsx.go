@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+)
+
+// xlsxSharedStrings is xl/sharedStrings.xml
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is a string, plain or made of rich text runs
+type xlsxText struct {
+	Text string     `xml:"t"`
+	Runs []xlsxText `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	var b strings.Builder
+	b.WriteString(t.Text)
+	for _, r := range t.Runs {
+		b.WriteString(r.String())
+	}
+	return b.String()
+}
+
+// xlsxSheet is a worksheet
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads the rows of the first worksheet of an Excel workbook
+func readXLSX(data []byte) ([]Row, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New(constants.ErrInvalidImport + ": " + err.Error())
+	}
+
+	var shared xlsxSharedStrings
+	var sheets []*zip.File
+	for _, f := range archive.File {
+		switch {
+		case f.Name == "xl/sharedStrings.xml":
+			if err := decodeXML(f, &shared); err != nil {
+				return nil, err
+			}
+		case path.Dir(f.Name) == "xl/worksheets" && strings.HasSuffix(f.Name, ".xml"):
+			sheets = append(sheets, f)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, errors.New(constants.ErrInvalidImport + ": no worksheet")
+	}
+	// sheet1.xml holds the first sheet of workbooks written by spreadsheet apps
+	sort.Slice(sheets, func(i, j int) bool {
+		return sheetNumber(sheets[i].Name) < sheetNumber(sheets[j].Name)
+	})
+
+	var sheet xlsxSheet
+	if err := decodeXML(sheets[0], &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(sheet.Rows))
+	for i, r := range sheet.Rows {
+		row := Row{Line: r.Number}
+		if row.Line == 0 {
+			row.Line = i + 1
+		}
+		for j, c := range r.Cells {
+			column := j
+			if c.Ref != "" {
+				if column, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(row.Cells) <= column {
+				row.Cells = append(row.Cells, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				if n, err := strconv.Atoi(c.Value); err == nil && n >= 0 && n < len(shared.Items) {
+					value = shared.Items[n].String()
+				}
+			case "inlineStr":
+				value = c.Inline.String()
+			}
+			row.Cells[column] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeXML decodes an XML file of the archive
+func decodeXML(f *zip.File, v interface{}) error {
+	r, err := f.Open()
+	if err != nil {
+		return errors.New(constants.ErrInvalidImport + ": " + err.Error())
+	}
+	defer r.Close()
+	if err := xml.NewDecoder(io.LimitReader(r, 64<<20)).Decode(v); err != nil {
+		return errors.New(constants.ErrInvalidImport + ": " + f.Name + ": " + err.Error())
+	}
+	return nil
+}
+
+// sheetNumber returns the number in a worksheet file name like sheet2.xml
+func sheetNumber(name string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path.Base(name), "sheet"), ".xml"))
+	if err != nil {
+		return 1 << 30
+	}
+	return n
+}
+
+// maxColumns is the number of columns of a worksheet, up to column XFD
+const maxColumns = 16384
+
+// cellRefPattern matches a cell reference like "C7"
+var cellRefPattern = regexp.MustCompile(`^([A-Z]{1,3})[0-9]+$`)
+
+// columnIndex returns the zero-based column of a cell reference like "C7"
+func columnIndex(ref string) (int, error) {
+	match := cellRefPattern.FindStringSubmatch(ref)
+	if match == nil {
+		return 0, errors.New(constants.ErrInvalidImport + ": invalid cell reference " + ref)
+	}
+	column := 0
+	for _, r := range match[1] {
+		column = column*26 + int(r-'A'+1)
+	}
+	if column > maxColumns {
+		return 0, errors.New(constants.ErrInvalidImport + ": invalid cell reference " + ref)
+	}
+	return column - 1, nil
+}
@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/schedule"
+)
+
+// workbook zips the given files into an XLSX workbook
+func workbook(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+const sharedStrings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4">
+<si><t>Name</t></si>
+<si><t>Date</t></si>
+<si><r><t>Ali</t></r><r><rPr><b/></rPr><t>ce</t></r></si>
+<si><t>alice@example.com</t></si>
+</sst>`
+
+// sheet1 mixes shared strings, inline strings, serial dates and skipped cells
+const sheet1 = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>Email</t></is></c></row>
+<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>46315</v></c><c r="D3" t="s"><v>3</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>Bob</t></is></c><c r="B4" t="inlineStr"><is><t>27-Oct-2026</t></is></c></row>
+</sheetData>
+</worksheet>`
+
+func TestParseXLSX(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"[Content_Types].xml":                 `<Types/>`,
+		"xl/workbook.xml":                     `<workbook/>`,
+		"xl/sharedStrings.xml":                sharedStrings,
+		"xl/worksheets/sheet1.xml":            sheet1,
+		"xl/worksheets/sheet2.xml":            `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Other sheet</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/_rels/sheet1.xml.rels": `<Relationships/>`,
+	})
+
+	result, err := Parse(data, "", Columns{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []schedule.Schedule{
+		{PIC: "Alice", Date: oct(20), Email: "alice@example.com"},
+		{PIC: "Bob", Date: oct(27)},
+	}
+	if !reflect.DeepEqual(result.Schedules, want) {
+		t.Errorf("schedules = %+v, want %+v", result.Schedules, want)
+	}
+	// Issues carry the row numbers of the sheet
+	if len(result.Issues) != 1 || result.Issues[0].Line != 4 || result.Issues[0].Field != FieldEmail {
+		t.Errorf("issues = %+v, want the missing email of row 4", result.Issues)
+	}
+}
+
+func TestReadXLSXRows(t *testing.T) {
+	rows, err := readXLSX(workbook(t, map[string]string{
+		"xl/sharedStrings.xml": sharedStrings,
+		// Rows and cells without references follow each other
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row><c t="s"><v>0</v></c><c t="s"><v>9</v></c><c r="C1"><v>1.5</v></c></row>
+<row><c r="AA2" t="inlineStr"><is><t>far</t></is></c></row>
+</sheetData></worksheet>`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	// An unknown shared string keeps its index
+	if want := (Row{Line: 1, Cells: []string{"Name", "9", "1.5"}}); !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("row 1 = %+v, want %+v", rows[0], want)
+	}
+	if rows[1].Line != 2 || len(rows[1].Cells) != 27 || rows[1].Cells[26] != "far" {
+		t.Errorf("row 2 = %+v, want far in column AA", rows[1])
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"Z9", 25},
+		{"AA2", 26},
+		{"XFD1048576", 16383},
+	}
+	for _, tt := range tests {
+		if got, err := columnIndex(tt.ref); err != nil || got != tt.want {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestReadXLSXRejectsCellReferences(t *testing.T) {
+	for _, ref := range []string{"a1", "1", "A", "A1B", "ZZZZZZZ1", "XFE1"} {
+		_, err := readXLSX(workbook(t, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="` + ref + `"><v>1</v></c></row></sheetData></worksheet>`,
+		}))
+		if want := constants.ErrInvalidImport + ": invalid cell reference " + ref; err == nil || err.Error() != want {
+			t.Errorf("readXLSX() with cell %s = %v, want %q", ref, err, want)
+		}
+	}
+}
+
+func TestReadXLSXWithoutWorksheet(t *testing.T) {
+	if _, err := readXLSX(workbook(t, map[string]string{"xl/workbook.xml": `<workbook/>`})); err == nil {
+		t.Error("readXLSX() of a workbook without a worksheet succeeded")
+	}
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/logging"
+	"seatalk-bot/internal/metrics"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Info describes a registered job and its timing
+type Info struct {
+	Name   string
+	Spec   string
+	Next   time.Time
+	Prev   time.Time
+	Paused bool
+}
+
+// job is a registered cron job
+type job struct {
+	name    string
+	spec    string
+	level   slog.Level
+	run     func() error
+	entryID cron.EntryID
+	paused  bool
+}
+
+// Runner owns the cron scheduler and the named jobs registered on it
+type Runner struct {
+	cron    *cron.Cron
+	clock   clock.Clock
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+}
+
+// NewRunner creates a Runner whose jobs recover from panics and log the stack.
+// The clock is used to compute next run times outside of the scheduler.
+func NewRunner(clk clock.Clock) *Runner {
+	return &Runner{
+		cron:  cron.New(cron.WithChain(cron.Recover(logging.CronLogger(slog.Default())))),
+		clock: clk,
+		jobs:  make(map[string]*job),
+	}
+}
+
+// Add registers a job under a unique name on a standard cron spec.
+// Every run is logged and recorded in the job metrics.
+func (r *Runner) Add(name, spec string, fn func() error) error {
+	return r.add(name, spec, slog.LevelInfo, fn)
+}
+
+// AddQuiet registers a job like Add, logging its runs at debug level. It
+// suits frequent housekeeping jobs whose runs are not worth a log line.
+func (r *Runner) AddQuiet(name, spec string, fn func() error) error {
+	return r.add(name, spec, slog.LevelDebug, fn)
+}
+
+// add registers a job whose runs are logged at level
+func (r *Runner) add(name, spec string, level slog.Level, fn func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[name]; ok {
+		return errors.New(constants.ErrJobExists + ": " + name)
+	}
+
+	j := &job{
+		name:  name,
+		spec:  spec,
+		level: level,
+		run:   logging.JobAt(name, level, metrics.Job(name, fn)),
+	}
+	entryID, err := r.cron.AddFunc(spec, func() {
+		if r.isPaused(name) {
+			slog.Log(context.Background(), j.level, "skipping paused job", "job", name)
+			return
+		}
+		j.run()
+	})
+	if err != nil {
+		return errors.New(constants.ErrInvalidCronSpec + ": " + spec + ": " + err.Error())
+	}
+	j.entryID = entryID
+	r.jobs[name] = j
+	return nil
+}
+
+// Start starts the scheduler in the background
+func (r *Runner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cron.Start()
+	r.started = true
+}
+
+// Stop stops the scheduler; the returned context is done once running jobs finish
+func (r *Runner) Stop() context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = false
+	return r.cron.Stop()
+}
+
+// Running reports an error unless the scheduler has been started
+func (r *Runner) Running(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started {
+		return errors.New(constants.ErrSchedulerNotRunning)
+	}
+	return nil
+}
+
+// List returns every job sorted by name. When the scheduler is not running the
+// next run time is computed from the spec.
+func (r *Runner) List() []Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	infos := make([]Info, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		entry := r.cron.Entry(j.entryID)
+		next := entry.Next
+		if next.IsZero() && entry.Schedule != nil {
+			next = entry.Schedule.Next(now)
+		}
+		infos = append(infos, Info{
+			Name:   j.name,
+			Spec:   j.spec,
+			Next:   next,
+			Prev:   entry.Prev,
+			Paused: j.paused,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// NextAfter returns the first time after t the named job is scheduled to run
+func (r *Runner) NextAfter(name string, t time.Time) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[name]
+	if !ok {
+		return time.Time{}, errors.New(constants.ErrJobNotFound + ": " + name)
+	}
+	return r.cron.Entry(j.entryID).Schedule.Next(t), nil
+}
+
+// Trigger runs a job immediately, even if it is paused, and returns its error
+func (r *Runner) Trigger(name string) (err error) {
+	r.mu.Lock()
+	j, ok := r.jobs[name]
+	r.mu.Unlock()
+	if !ok {
+		return errors.New(constants.ErrJobNotFound + ": " + name)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%s: %s: %v", constants.ErrJobPanicked, name, p)
+		}
+	}()
+	return j.run()
+}
+
+// Pause stops scheduled runs of a job until it is resumed
+func (r *Runner) Pause(name string) error {
+	return r.setPaused(name, true)
+}
+
+// Resume re-enables scheduled runs of a paused job
+func (r *Runner) Resume(name string) error {
+	return r.setPaused(name, false)
+}
+
+func (r *Runner) setPaused(name string, paused bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[name]
+	if !ok {
+		return errors.New(constants.ErrJobNotFound + ": " + name)
+	}
+	j.paused = paused
+	return nil
+}
+
+func (r *Runner) isPaused(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[name].paused
+}
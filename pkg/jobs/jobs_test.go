@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+)
+
+func TestAdd(t *testing.T) {
+	r := NewRunner(clock.Real{})
+	if err := r.Add("announce", "0 9 * * 2", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		job  string
+		spec string
+		err  string
+	}{
+		{"duplicate name", "announce", "0 9 * * 2", constants.ErrJobExists},
+		{"invalid spec", "remind", "every tuesday", constants.ErrInvalidCronSpec},
+		{"second job", "remind", "0 10 * * 2", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Add(tt.job, tt.spec, func() error { return nil })
+			if (err == nil) != (tt.err == "") || (err != nil && !strings.HasPrefix(err.Error(), tt.err)) {
+				t.Errorf("Add(%q, %q) = %v, want %q", tt.job, tt.spec, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestListAndPause(t *testing.T) {
+	r := NewRunner(clock.Real{})
+	r.Add("remind", "0 10 * * 2", func() error { return nil })
+	r.Add("announce", "0 9 * * 2", func() error { return nil })
+	if err := r.Pause("remind"); err != nil {
+		t.Fatal(err)
+	}
+
+	infos := r.List()
+	if len(infos) != 2 || infos[0].Name != "announce" || infos[1].Name != "remind" {
+		t.Fatalf("List() = %+v, want announce and remind", infos)
+	}
+	if infos[0].Paused || !infos[1].Paused {
+		t.Errorf("paused = %v, %v, want only remind paused", infos[0].Paused, infos[1].Paused)
+	}
+	if infos[0].Next.IsZero() {
+		t.Error("next run is not computed while the scheduler is stopped")
+	}
+
+	if err := r.Resume("remind"); err != nil {
+		t.Fatal(err)
+	}
+	if r.List()[1].Paused {
+		t.Error("remind is still paused after Resume")
+	}
+	if err := r.Pause("missing"); err == nil || !strings.HasPrefix(err.Error(), constants.ErrJobNotFound) {
+		t.Errorf("Pause(missing) = %v", err)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	runs := 0
+	r := NewRunner(clock.Real{})
+	r.Add("ok", "@daily", func() error { runs++; return nil })
+	r.Add("fails", "@daily", func() error { return errors.New("send failed") })
+	r.Add("panics", "@daily", func() error { panic("nil schedule") })
+	r.Pause("ok")
+
+	tests := []struct {
+		job string
+		err string
+	}{
+		{"ok", ""},
+		{"fails", "send failed"},
+		{"panics", constants.ErrJobPanicked + ": panics: nil schedule"},
+		{"missing", constants.ErrJobNotFound + ": missing"},
+	}
+	for _, tt := range tests {
+		err := r.Trigger(tt.job)
+		if (err == nil) != (tt.err == "") || (err != nil && err.Error() != tt.err) {
+			t.Errorf("Trigger(%q) = %v, want %q", tt.job, err, tt.err)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("paused job ran %d times when triggered, want 1", runs)
+	}
+}
+
+func TestRunning(t *testing.T) {
+	r := NewRunner(clock.Real{})
+	if err := r.Running(context.Background()); err == nil {
+		t.Error("Running() = nil before Start")
+	}
+	r.Start()
+	if err := r.Running(context.Background()); err != nil {
+		t.Errorf("Running() = %v after Start", err)
+	}
+	<-r.Stop().Done()
+	if err := r.Running(context.Background()); err == nil {
+		t.Error("Running() = nil after Stop")
+	}
+}
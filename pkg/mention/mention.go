@@ -0,0 +1,76 @@
+package mention
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"seatalk-bot/internal/constants"
+)
+
+// Target query keys understood by SeaTalk mention tags
+const (
+	keyEmail        = "email"
+	keyEmployeeCode = "employee_code"
+	keySeatalkID    = "id"
+)
+
+// allSeatalkID is the SeaTalk ID that notifies every member of a group
+const allSeatalkID = "0"
+
+// Target identifies the user to mention. The first non-empty field among
+// SeatalkID, EmployeeCode and Email is used.
+type Target struct {
+	SeatalkID    string
+	EmployeeCode string
+	Email        string
+}
+
+// Email renders a mention tag for the user with the given email
+func Email(email string) string {
+	return tag(keyEmail, email)
+}
+
+// EmployeeCode renders a mention tag for the user with the given employee code
+func EmployeeCode(code string) string {
+	return tag(keyEmployeeCode, code)
+}
+
+// SeatalkID renders a mention tag for the user with the given SeaTalk ID
+func SeatalkID(id string) string {
+	return tag(keySeatalkID, id)
+}
+
+// For renders a mention tag for the target, or an empty string when the
+// target has no identifier
+func For(t Target) string {
+	switch {
+	case t.SeatalkID != "":
+		return SeatalkID(t.SeatalkID)
+	case t.EmployeeCode != "":
+		return EmployeeCode(t.EmployeeCode)
+	case t.Email != "":
+		return Email(t.Email)
+	}
+	return ""
+}
+
+// All renders an @all mention tag, provided the group allows the bot to
+// notify everyone as told by its can_notify_with_at_all setting
+func All(canNotifyWithAtAll bool) (string, error) {
+	if !canNotifyWithAtAll {
+		return "", errors.New(constants.ErrAtAllNotAllowed)
+	}
+	return tag(keySeatalkID, allSeatalkID), nil
+}
+
+// tag renders a mention tag for the given target query
+func tag(key, value string) string {
+	return `<mention-tag target="seatalk://user?` + key + `=` + escape(value) + `"/>`
+}
+
+// escape keeps a target value from breaking out of the query or the tag
+// attribute while leaving readable emails such as jane.doe@example.com intact
+func escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(strings.TrimSpace(value)), "%40", "@")
+}
@@ -0,0 +1,56 @@
+package mention
+
+import (
+	"testing"
+
+	"seatalk-bot/internal/constants"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"email", Email("jane.doe@example.com"), `<mention-tag target="seatalk://user?email=jane.doe@example.com"/>`},
+		{"trimmed", Email("  jane@example.com\n"), `<mention-tag target="seatalk://user?email=jane@example.com"/>`},
+		{"plus sign", Email("jane+bot@example.com"), `<mention-tag target="seatalk://user?email=jane%2Bbot@example.com"/>`},
+		{"quote cannot close the attribute", Email(`x"/><b>hi</b>`), `<mention-tag target="seatalk://user?email=x%22%2F%3E%3Cb%3Ehi%3C%2Fb%3E"/>`},
+		{"ampersand cannot add a query key", EmployeeCode("E001&id=0"), `<mention-tag target="seatalk://user?employee_code=E001%26id%3D0"/>`},
+		{"employee code", EmployeeCode("E001"), `<mention-tag target="seatalk://user?employee_code=E001"/>`},
+		{"seatalk id", SeatalkID("12345"), `<mention-tag target="seatalk://user?id=12345"/>`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		target Target
+		want   string
+	}{
+		{"seatalk id first", Target{SeatalkID: "1", EmployeeCode: "E001", Email: "jane@example.com"}, SeatalkID("1")},
+		{"then employee code", Target{EmployeeCode: "E001", Email: "jane@example.com"}, EmployeeCode("E001")},
+		{"then email", Target{Email: "jane@example.com"}, Email("jane@example.com")},
+		{"nothing to mention", Target{}, ""},
+	}
+	for _, tt := range tests {
+		if got := For(tt.target); got != tt.want {
+			t.Errorf("%s: For() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	got, err := All(true)
+	if want := `<mention-tag target="seatalk://user?id=0"/>`; err != nil || got != want {
+		t.Errorf("All() = %s, %v, want %s", got, err, want)
+	}
+	if got, err := All(false); err == nil || err.Error() != constants.ErrAtAllNotAllowed || got != "" {
+		t.Errorf("All() without permission = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,211 @@
+package roles
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+)
+
+// Role is the level of trust given to an employee. Higher roles include the
+// permissions of lower ones.
+type Role int
+
+// Roles, from least to most trusted
+const (
+	Member Role = iota
+	Admin
+	Owner
+)
+
+// Global is the scope of roles that apply in every group and in direct messages
+const Global = ""
+
+// String returns the name of the role
+func (r Role) String() string {
+	switch r {
+	case Owner:
+		return "owner"
+	case Admin:
+		return "admin"
+	}
+	return "member"
+}
+
+// AtLeast reports whether r includes the permissions of required
+func (r Role) AtLeast(required Role) bool {
+	return r >= required
+}
+
+// Parse returns the role with the given name
+func Parse(name string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "owner":
+		return Owner, nil
+	case "admin":
+		return Admin, nil
+	case "member":
+		return Member, nil
+	}
+	return Member, errors.New(constants.ErrUnknownRole + ": " + name)
+}
+
+// Binding is a role held by an employee in a scope
+type Binding struct {
+	Scope        string
+	EmployeeCode string
+	Role         Role
+}
+
+// rolesData is the persisted form of the role bindings
+type rolesData struct {
+	Global map[string]string            `json:"global"`
+	Groups map[string]map[string]string `json:"groups"`
+}
+
+// Store persists the roles bound to employees globally and per group
+type Store struct {
+	path   string
+	owners map[string]bool
+	mu     sync.RWMutex
+	data   rolesData
+}
+
+// Load loads the role bindings persisted at path. The given employee codes
+// are global owners regardless of the persisted bindings.
+func Load(path string, owners []string) (*Store, error) {
+	s := &Store{path: path, owners: make(map[string]bool)}
+	if err := filestore.LoadJSON(path, &s.data); err != nil {
+		return nil, err
+	}
+	if s.data.Global == nil {
+		s.data.Global = make(map[string]string)
+	}
+	if s.data.Groups == nil {
+		s.data.Groups = make(map[string]map[string]string)
+	}
+	for _, code := range owners {
+		if code = strings.TrimSpace(code); code != "" {
+			s.owners[code] = true
+		}
+	}
+	return s, nil
+}
+
+// Bound returns the role bound to an employee in exactly the given scope
+func (s *Store) Bound(scope, employeeCode string) Role {
+	if scope == Global && s.owners[employeeCode] {
+		return Owner
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bindings := s.data.Global
+	if scope != Global {
+		bindings = s.data.Groups[scope]
+	}
+	role, _ := Parse(bindings[employeeCode])
+	return role
+}
+
+// Effective returns the role of an employee in a group, the higher of their
+// global and group roles. Pass Global for direct messages.
+func (s *Store) Effective(groupID, employeeCode string) Role {
+	if employeeCode == "" {
+		return Member
+	}
+	role := s.Bound(Global, employeeCode)
+	if groupID != Global {
+		role = max(role, s.Bound(groupID, employeeCode))
+	}
+	return role
+}
+
+// Grant binds a role to an employee in a scope. Granting Member removes the binding.
+func (s *Store) Grant(scope, employeeCode string, role Role) error {
+	if role == Member {
+		return s.Revoke(scope, employeeCode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bindings := s.bindings(scope)
+	bindings[employeeCode] = role.String()
+	return filestore.SaveJSON(s.path, s.data)
+}
+
+// Revoke removes the role bound to an employee in a scope
+func (s *Store) Revoke(scope, employeeCode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bindings := s.bindings(scope)
+	delete(bindings, employeeCode)
+	if scope != Global && len(bindings) == 0 {
+		delete(s.data.Groups, scope)
+	}
+	return filestore.SaveJSON(s.path, s.data)
+}
+
+// Bindings returns the roles bound in a scope, most trusted first. The global
+// scope includes the configured owners.
+func (s *Store) Bindings(scope string) []Binding {
+	s.mu.RLock()
+	bindings := s.data.Global
+	if scope != Global {
+		bindings = s.data.Groups[scope]
+	}
+	var list []Binding
+	for code, name := range bindings {
+		role, _ := Parse(name)
+		if scope == Global && s.owners[code] {
+			role = Owner
+		}
+		list = append(list, Binding{Scope: scope, EmployeeCode: code, Role: role})
+	}
+	s.mu.RUnlock()
+
+	if scope == Global {
+		for code := range s.owners {
+			if _, bound := bindings[code]; !bound {
+				list = append(list, Binding{Scope: scope, EmployeeCode: code, Role: Owner})
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Role != list[j].Role {
+			return list[i].Role > list[j].Role
+		}
+		return list[i].EmployeeCode < list[j].EmployeeCode
+	})
+	return list
+}
+
+// Configured reports whether the employee is an owner through the configuration
+// rather than a binding, which cannot be revoked from chat
+func (s *Store) Configured(employeeCode string) bool {
+	return s.owners[employeeCode]
+}
+
+// HasAdmins reports whether anyone holds at least the Admin role in a scope
+func (s *Store) HasAdmins(scope string) bool {
+	for _, b := range s.Bindings(scope) {
+		if b.Role.AtLeast(Admin) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindings returns the writable bindings of a scope; the caller holds the lock
+func (s *Store) bindings(scope string) map[string]string {
+	if scope == Global {
+		return s.data.Global
+	}
+	if s.data.Groups[scope] == nil {
+		s.data.Groups[scope] = make(map[string]string)
+	}
+	return s.data.Groups[scope]
+}
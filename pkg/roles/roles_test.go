@@ -0,0 +1,121 @@
+package roles
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEffective(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "roles.json"), []string{"OWNER", " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []Binding{
+		{Global, "GLOBALADMIN", Admin},
+		{"g1", "GROUPADMIN", Admin},
+		{"g1", "GLOBALADMIN", Admin},
+		{"g1", "OWNER", Admin},
+		{"g2", "GROUPOWNER", Owner},
+	} {
+		if err := s.Grant(b.Scope, b.EmployeeCode, b.Role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		groupID  string
+		employee string
+		want     Role
+	}{
+		{"configured owner everywhere", "g2", "OWNER", Owner},
+		{"configured owner over a lower group role", "g1", "OWNER", Owner},
+		{"configured owner in direct messages", Global, "OWNER", Owner},
+		{"global admin in any group", "g2", "GLOBALADMIN", Admin},
+		{"group admin in their group", "g1", "GROUPADMIN", Admin},
+		{"group admin in another group", "g2", "GROUPADMIN", Member},
+		{"group admin in direct messages", Global, "GROUPADMIN", Member},
+		{"group owner outranks the global role", "g2", "GROUPOWNER", Owner},
+		{"unknown employee", "g1", "E999", Member},
+		{"no employee code", "g1", "", Member},
+	}
+	for _, tt := range tests {
+		if got := s.Effective(tt.groupID, tt.employee); got != tt.want {
+			t.Errorf("%s: Effective(%q, %q) = %s, want %s", tt.name, tt.groupID, tt.employee, got, tt.want)
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{Owner, Admin, true},
+		{Owner, Owner, true},
+		{Admin, Member, true},
+		{Admin, Owner, false},
+		{Member, Admin, false},
+		{Member, Member, true},
+	}
+	for _, tt := range tests {
+		if got := tt.role.AtLeast(tt.required); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, role := range []Role{Member, Admin, Owner} {
+		if got, err := Parse(" " + role.String() + " "); err != nil || got != role {
+			t.Errorf("Parse(%q) = %s, %v", role.String(), got, err)
+		}
+	}
+	if got, err := Parse("ADMIN"); err != nil || got != Admin {
+		t.Errorf("Parse(ADMIN) = %s, %v", got, err)
+	}
+	if _, err := Parse("root"); err == nil {
+		t.Error("Parse(root) succeeded")
+	}
+}
+
+func TestGrantAndRevoke(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.json")
+	s, err := Load(path, []string{"OWNER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Grant("g1", "E001", Admin)
+	s.Grant("g1", "E002", Owner)
+	s.Grant(Global, "OWNER", Admin)
+	s.Grant(Global, "E003", Admin)
+
+	reloaded, err := Load(path, []string{"OWNER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGroup := []Binding{{"g1", "E002", Owner}, {"g1", "E001", Admin}}
+	if got := reloaded.Bindings("g1"); !reflect.DeepEqual(got, wantGroup) {
+		t.Errorf("Bindings(g1) = %+v, want %+v", got, wantGroup)
+	}
+	// A binding cannot demote a configured owner
+	wantGlobal := []Binding{{Global, "OWNER", Owner}, {Global, "E003", Admin}}
+	if got := reloaded.Bindings(Global); !reflect.DeepEqual(got, wantGlobal) {
+		t.Errorf("Bindings(global) = %+v, want %+v", got, wantGlobal)
+	}
+
+	// Granting Member revokes the binding
+	reloaded.Grant("g1", "E001", Member)
+	reloaded.Revoke("g1", "E002")
+	if got := reloaded.Bindings("g1"); len(got) != 0 {
+		t.Errorf("Bindings(g1) = %+v after revoking everyone", got)
+	}
+	if reloaded.HasAdmins("g1") {
+		t.Error("HasAdmins(g1) after revoking everyone")
+	}
+	if !reloaded.HasAdmins(Global) || !reloaded.Configured("OWNER") || reloaded.Configured("E003") {
+		t.Error("global owners and admins lost")
+	}
+}
@@ -0,0 +1,164 @@
+package rotation
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"seatalk-bot/pkg/schedule"
+)
+
+// Reasons of plan warnings
+const (
+	WarningExcluded   = "excluded date, nobody assigned"
+	WarningRelaxed    = "min gap or max consecutive relaxed"
+	WarningUnassigned = "no member available"
+)
+
+// Warning reports a period the plan could not fill as configured
+type Warning struct {
+	Date   time.Time
+	Reason string
+}
+
+// Plan is the outcome of planning a rotation
+type Plan struct {
+	Assignments []schedule.Schedule
+	Warnings    []Warning
+}
+
+// engine hands out turns from a copy of the turn counts
+type engine struct {
+	cfg   Config
+	stats map[string]Stats
+}
+
+// newEngine copies the turn counts. Members without turns yet start level
+// with the least loaded member instead of being owed every past turn.
+func newEngine(cfg Config, stats map[string]Stats) *engine {
+	e := &engine{cfg: cfg, stats: make(map[string]Stats, len(stats))}
+	for key, s := range stats {
+		e.stats[key] = s
+	}
+
+	minLoad := math.Inf(1)
+	for _, m := range cfg.Members {
+		if s, ok := e.stats[m.Key()]; ok {
+			minLoad = math.Min(minLoad, float64(s.Turns)/m.Share())
+		}
+	}
+	if math.IsInf(minLoad, 1) {
+		return e
+	}
+	for _, m := range cfg.Members {
+		if _, ok := e.stats[m.Key()]; !ok {
+			e.stats[m.Key()] = Stats{Turns: int(minLoad * m.Share())}
+		}
+	}
+	return e
+}
+
+// member returns the configured member serving a schedule entry
+func (e *engine) member(s schedule.Schedule) (Member, bool) {
+	for _, m := range e.cfg.Members {
+		if (s.Email != "" && strings.EqualFold(m.Email, s.Email)) || (s.Email == "" && m.Name == s.PIC) {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
+
+// record counts a turn of the member on the day
+func (e *engine) record(m Member, day time.Time) {
+	s := e.stats[m.Key()]
+	if !s.Last.IsZero() && e.periodsBetween(s.Last.Time, day) == 1 {
+		s.Consecutive++
+	} else {
+		s.Consecutive = 1
+	}
+	s.Turns++
+	s.Last = Day{day}
+	e.stats[m.Key()] = s
+}
+
+// periodsBetween returns how many whole periods separate two days
+func (e *engine) periodsBetween(from, to time.Time) int {
+	days := int(math.Round(to.Sub(from).Hours() / 24))
+	return days / e.cfg.Period()
+}
+
+// eligible reports whether the member may serve on the day. The gap and
+// consecutive turn constraints only apply when strict.
+func (e *engine) eligible(m Member, day time.Time, strict bool) bool {
+	if !m.AvailableOn(day) {
+		return false
+	}
+	s := e.stats[m.Key()]
+	if s.Last.IsZero() {
+		return true
+	}
+	since := e.periodsBetween(s.Last.Time, day)
+	if since < 1 {
+		// Already serving this period
+		return false
+	}
+	if !strict {
+		return true
+	}
+	if since <= e.cfg.Constraints.MinGap {
+		return false
+	}
+	max := e.cfg.Constraints.MaxConsecutive
+	return max == 0 || since > 1 || s.Consecutive < max
+}
+
+// less reports whether a should serve before b: the member with the fewest
+// turns for their weight first, then the one who served longest ago
+func (e *engine) less(a, b Member) bool {
+	sa, sb := e.stats[a.Key()], e.stats[b.Key()]
+	la, lb := float64(sa.Turns)/a.Share(), float64(sb.Turns)/b.Share()
+	if la != lb {
+		return la < lb
+	}
+	return sa.Last.Before(sb.Last.Time)
+}
+
+// pick returns the member who should serve on the day
+func (e *engine) pick(day time.Time, strict bool) (Member, bool) {
+	var best Member
+	found := false
+	for _, m := range e.cfg.Members {
+		if e.eligible(m, day, strict) && (!found || e.less(m, best)) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+// plan assigns members to periods starting on start
+func (e *engine) plan(start time.Time, periods int) Plan {
+	var p Plan
+	for i := 0; i < periods; i++ {
+		day := start.AddDate(0, 0, i*e.cfg.Period())
+		if e.cfg.excluded(day) {
+			p.Warnings = append(p.Warnings, Warning{Date: day, Reason: WarningExcluded})
+			continue
+		}
+
+		for slot := 0; slot < e.cfg.Slots(); slot++ {
+			m, ok := e.pick(day, true)
+			if !ok {
+				if m, ok = e.pick(day, false); ok {
+					p.Warnings = append(p.Warnings, Warning{Date: day, Reason: WarningRelaxed})
+				}
+			}
+			if !ok {
+				p.Warnings = append(p.Warnings, Warning{Date: day, Reason: WarningUnassigned})
+				break
+			}
+			e.record(m, day)
+			p.Assignments = append(p.Assignments, schedule.Schedule{PIC: m.Name, Date: day, Email: m.Email})
+		}
+	}
+	return p
+}
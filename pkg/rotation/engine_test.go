@@ -0,0 +1,181 @@
+package rotation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// start is the first period planned in the tests, a Tuesday
+var start = time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)
+
+// member returns a member named name with an email derived from it
+func member(name string) Member {
+	return Member{Name: name, Email: name + "@example.com"}
+}
+
+// day parses a day written as 2006-01-02
+func day(t *testing.T, value string) Day {
+	t.Helper()
+	d, err := ParseDay(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// describe lists the assignments and warnings of a plan as "2006-01-02 name"
+// and "2006-01-02 reason"
+func describe(p Plan) ([]string, []string) {
+	var assignments, warnings []string
+	for _, a := range p.Assignments {
+		assignments = append(assignments, a.Date.Format(time.DateOnly)+" "+a.PIC)
+	}
+	for _, w := range p.Warnings {
+		warnings = append(warnings, w.Date.Format(time.DateOnly)+" "+w.Reason)
+	}
+	return assignments, warnings
+}
+
+func TestEnginePlan(t *testing.T) {
+	a, b, c := member("A"), member("B"), member("C")
+	heavy := a
+	heavy.Weight = 2
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		stats       map[string]Stats
+		periods     int
+		assignments []string
+		warnings    []string
+	}{
+		{
+			name:    "round robin",
+			cfg:     Config{Members: []Member{a, b, c}},
+			periods: 6,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-27 B", "2026-11-03 C",
+				"2026-11-10 A", "2026-11-17 B", "2026-11-24 C",
+			},
+		},
+		{
+			name:    "weights",
+			cfg:     Config{Members: []Member{heavy, b}},
+			periods: 6,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-27 B", "2026-11-03 A",
+				"2026-11-10 B", "2026-11-17 A", "2026-11-24 A",
+			},
+		},
+		{
+			name:    "max consecutive",
+			cfg:     Config{Members: []Member{a, b}, Constraints: Constraints{MaxConsecutive: 2}},
+			stats:   map[string]Stats{a.Key(): {Turns: 0}, b.Key(): {Turns: 4}},
+			periods: 6,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-27 A", "2026-11-03 B",
+				"2026-11-10 A", "2026-11-17 A", "2026-11-24 B",
+			},
+		},
+		{
+			name:    "min gap",
+			cfg:     Config{Members: []Member{a, b, c}, Constraints: Constraints{MinGap: 1}},
+			stats:   map[string]Stats{a.Key(): {Turns: 0}, b.Key(): {Turns: 0}, c.Key(): {Turns: 10}},
+			periods: 4,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-27 B", "2026-11-03 A", "2026-11-10 B",
+			},
+		},
+		{
+			name:        "min gap relaxed",
+			cfg:         Config{Members: []Member{a}, Constraints: Constraints{MinGap: 1}},
+			periods:     2,
+			assignments: []string{"2026-10-20 A", "2026-10-27 A"},
+			warnings:    []string{"2026-10-27 " + WarningRelaxed},
+		},
+		{
+			name:        "excluded date",
+			cfg:         Config{Members: []Member{a, b}, Constraints: Constraints{ExcludedDates: []Day{day(t, "2026-10-27")}}},
+			periods:     3,
+			assignments: []string{"2026-10-20 A", "2026-11-03 B"},
+			warnings:    []string{"2026-10-27 " + WarningExcluded},
+		},
+		{
+			name: "availability window",
+			cfg: Config{Members: []Member{
+				{Name: "C", Email: "C@example.com", Available: []Window{{From: day(t, "2026-11-03")}}},
+				a, b,
+			}},
+			periods: 5,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-27 B", "2026-11-03 C", "2026-11-10 A", "2026-11-17 B",
+			},
+		},
+		{
+			name: "nobody available",
+			cfg: Config{Members: []Member{
+				{Name: "A", Email: "A@example.com", Unavailable: []Window{{From: day(t, "2026-01-01")}}},
+			}},
+			periods:  1,
+			warnings: []string{"2026-10-20 " + WarningUnassigned},
+		},
+		{
+			name:    "several per period",
+			cfg:     Config{Members: []Member{a, b, c}, PerPeriod: 2},
+			periods: 3,
+			assignments: []string{
+				"2026-10-20 A", "2026-10-20 B", "2026-10-27 C", "2026-10-27 A", "2026-11-03 B", "2026-11-03 C",
+			},
+		},
+		{
+			name: "new member starts level",
+			cfg:  Config{Members: []Member{a, b, c}},
+			stats: map[string]Stats{
+				a.Key(): {Turns: 4, Last: day(t, "2026-10-13"), Consecutive: 1},
+				b.Key(): {Turns: 4, Last: day(t, "2026-10-06"), Consecutive: 1},
+			},
+			periods: 4,
+			assignments: []string{
+				"2026-10-20 C", "2026-10-27 B", "2026-11-03 A", "2026-11-10 C",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments, warnings := describe(newEngine(tt.cfg, tt.stats).plan(start, tt.periods))
+			if !reflect.DeepEqual(assignments, tt.assignments) {
+				t.Errorf("assignments = %q, want %q", assignments, tt.assignments)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestEngineCompensatesLeave(t *testing.T) {
+	a, b := member("A"), member("B")
+	a.Unavailable = []Window{{From: day(t, "2026-10-20"), To: day(t, "2026-11-03")}}
+
+	assignments, warnings := describe(newEngine(Config{Members: []Member{a, b}}, nil).plan(start, 7))
+	want := []string{
+		"2026-10-20 B", "2026-10-27 B", "2026-11-03 B",
+		"2026-11-10 A", "2026-11-17 A", "2026-11-24 A",
+		"2026-12-01 B",
+	}
+	if !reflect.DeepEqual(assignments, want) {
+		t.Errorf("assignments = %q, want %q", assignments, want)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %q, want none", warnings)
+	}
+}
+
+func TestEngineLeavesStatsUntouched(t *testing.T) {
+	stats := map[string]Stats{"a@example.com": {Turns: 2}}
+	newEngine(Config{Members: []Member{member("A"), member("B")}}, stats).plan(start, 3)
+	if want := (map[string]Stats{"a@example.com": {Turns: 2}}); !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats = %v, want %v", stats, want)
+	}
+}
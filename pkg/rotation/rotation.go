@@ -0,0 +1,305 @@
+package rotation
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Defaults of the rotation settings left at zero
+const (
+	DefaultPeriodDays   = 7
+	DefaultPeriodsAhead = 8
+	DefaultPerPeriod    = 1
+)
+
+// Day is a calendar day, written as 2006-01-02 in rotation files
+type Day struct {
+	time.Time
+}
+
+// ParseDay parses a day written as 2006-01-02
+func ParseDay(value string) (Day, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return Day{}, errors.New(constants.ErrorDateParse + ": " + value)
+	}
+	return Day{t}, nil
+}
+
+// MarshalJSON writes the day as 2006-01-02
+func (d Day) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+// UnmarshalJSON reads a day written as 2006-01-02
+func (d *Day) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		*d = Day{}
+		return nil
+	}
+	parsed, err := ParseDay(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Window is a range of days, both ends included. A zero end is open.
+type Window struct {
+	From Day `json:"from"`
+	To   Day `json:"to"`
+}
+
+// Contains reports whether the day falls in the window
+func (w Window) Contains(day time.Time) bool {
+	if !w.From.IsZero() && day.Before(w.From.Time) {
+		return false
+	}
+	return w.To.IsZero() || !day.After(w.To.Time)
+}
+
+// Member is someone taking turns in a rotation
+type Member struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Weight scales the share of turns of the member, 1 when zero
+	Weight float64 `json:"weight,omitempty"`
+	// Available limits the member to these windows when set
+	Available []Window `json:"available,omitempty"`
+	// Unavailable holds the leaves of the member
+	Unavailable []Window `json:"unavailable,omitempty"`
+}
+
+// Key identifies the member in the turn counts
+func (m Member) Key() string {
+	if m.Email != "" {
+		return strings.ToLower(m.Email)
+	}
+	return m.Name
+}
+
+// Share returns the weight of the member, 1 when unset
+func (m Member) Share() float64 {
+	if m.Weight <= 0 {
+		return 1
+	}
+	return m.Weight
+}
+
+// AvailableOn reports whether the member can take a turn on the day
+func (m Member) AvailableOn(day time.Time) bool {
+	for _, w := range m.Unavailable {
+		if w.Contains(day) {
+			return false
+		}
+	}
+	if len(m.Available) == 0 {
+		return true
+	}
+	for _, w := range m.Available {
+		if w.Contains(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Constraints restrict how turns are handed out
+type Constraints struct {
+	// MaxConsecutive is how many periods in a row a member may serve, 0 for no limit
+	MaxConsecutive int `json:"max_consecutive,omitempty"`
+	// MinGap is how many periods a member rests between turns
+	MinGap int `json:"min_gap,omitempty"`
+	// ExcludedDates are periods nobody is assigned to, such as holidays
+	ExcludedDates []Day `json:"excluded_dates,omitempty"`
+}
+
+// Config describes a rotation
+type Config struct {
+	Members     []Member    `json:"members"`
+	Constraints Constraints `json:"constraints"`
+	// PeriodDays is the length of a period, DefaultPeriodDays when zero
+	PeriodDays int `json:"period_days,omitempty"`
+	// PeriodsAhead is how many periods the schedule is planned ahead
+	PeriodsAhead int `json:"periods_ahead,omitempty"`
+	// PerPeriod is how many members serve each period
+	PerPeriod int `json:"per_period,omitempty"`
+}
+
+// Period returns the length of a period in days
+func (c Config) Period() int {
+	if c.PeriodDays <= 0 {
+		return DefaultPeriodDays
+	}
+	return c.PeriodDays
+}
+
+// Ahead returns how many periods the schedule is planned ahead
+func (c Config) Ahead() int {
+	if c.PeriodsAhead <= 0 {
+		return DefaultPeriodsAhead
+	}
+	return c.PeriodsAhead
+}
+
+// Slots returns how many members serve each period
+func (c Config) Slots() int {
+	if c.PerPeriod <= 0 {
+		return DefaultPerPeriod
+	}
+	return c.PerPeriod
+}
+
+// excluded reports whether nobody is assigned on the day
+func (c Config) excluded(day time.Time) bool {
+	for _, d := range c.Constraints.ExcludedDates {
+		if d.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats counts the turns a member served
+type Stats struct {
+	Turns int `json:"turns"`
+	Last  Day `json:"last"`
+	// Consecutive is how many periods in a row ended with Last
+	Consecutive int `json:"consecutive"`
+}
+
+// state is the content of a rotation file
+type state struct {
+	Config Config           `json:"config"`
+	Stats  map[string]Stats `json:"stats,omitempty"`
+}
+
+// Rotation holds the configuration of a rotation and the turns served so far
+type Rotation struct {
+	path  string
+	mu    sync.RWMutex
+	state state
+}
+
+// Load loads the rotation stored at path. A missing file is a rotation
+// without members, which the engine leaves alone.
+func Load(path string) (*Rotation, error) {
+	r := &Rotation{path: path}
+	if err := filestore.LoadJSON(path, &r.state); err != nil {
+		return nil, err
+	}
+	if r.state.Stats == nil {
+		r.state.Stats = make(map[string]Stats)
+	}
+	return r, nil
+}
+
+// Configured reports whether the rotation has members
+func (r *Rotation) Configured() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.state.Config.Members) > 0
+}
+
+// Config returns the configuration of the rotation
+func (r *Rotation) Config() Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state.Config
+}
+
+// Stats returns the turns served by each member, by member key
+func (r *Rotation) Stats() map[string]Stats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := make(map[string]Stats, len(r.state.Stats))
+	for key, s := range r.state.Stats {
+		stats[key] = s
+	}
+	return stats
+}
+
+// Init configures the rotation with the members of a schedule, in order of
+// their first turn, and default settings
+func (r *Rotation) Init(schedules []schedule.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var members []Member
+	for _, s := range schedules {
+		m := Member{Name: s.PIC, Email: s.Email}
+		if !seen[m.Key()] {
+			seen[m.Key()] = true
+			members = append(members, m)
+		}
+	}
+	r.state.Config = Config{
+		Members:      members,
+		PeriodDays:   DefaultPeriodDays,
+		PeriodsAhead: DefaultPeriodsAhead,
+		PerPeriod:    DefaultPerPeriod,
+	}
+	return filestore.SaveJSON(r.path, r.state)
+}
+
+// Record counts the turns of the given schedules as served. Turns on or
+// before the last one counted for a member are ignored, so recording the
+// same period twice has no effect.
+func (r *Rotation) Record(served []schedule.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := newEngine(r.state.Config, r.state.Stats)
+	changed := false
+	for _, s := range served {
+		m, ok := e.member(s)
+		if !ok || !s.Date.After(e.stats[m.Key()].Last.Time) {
+			continue
+		}
+		e.record(m, s.Date)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	r.state.Stats = e.stats
+	return filestore.SaveJSON(r.path, r.state)
+}
+
+// Plan assigns members to periods starting on start, accounting for the
+// scheduled turns in pending that were not recorded yet. It does not change
+// the rotation.
+func (r *Rotation) Plan(start time.Time, periods int, pending []schedule.Schedule) Plan {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sorted := append([]schedule.Schedule(nil), pending...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	e := newEngine(r.state.Config, r.state.Stats)
+	for _, s := range sorted {
+		if m, ok := e.member(s); ok && s.Date.After(e.stats[m.Key()].Last.Time) {
+			e.record(m, s.Date)
+		}
+	}
+	return e.plan(start, periods)
+}
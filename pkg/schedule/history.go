@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"time"
+
+	"seatalk-bot/internal/filestore"
+)
+
+// maxVersions is how many versions of a rotation are kept
+const maxVersions = 50
+
+// ActionEdit marks a version found in the schedule file that no recorded
+// change produced, such as a manual edit or the state before history began
+const ActionEdit = "schedule.edit"
+
+// Version is a snapshot of a rotation taken after a change
+type Version struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// Action is the audit action that produced the version
+	Action string `json:"action"`
+	Actor  string `json:"actor,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// RestoredFrom is the version a restoration brought back
+	RestoredFrom int `json:"restored_from,omitempty"`
+	// Undo marks a restoration made by undoing a change
+	Undo    bool       `json:"undo,omitempty"`
+	Entries []Schedule `json:"entries"`
+}
+
+// History holds the latest versions of a rotation, oldest first. It is
+// guarded by the lock of the Store it belongs to.
+type History struct {
+	path     string
+	versions []Version
+}
+
+// LoadHistory reads the history stored at path. A missing file is an empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path}
+	if err := filestore.LoadJSON(path, &h.versions); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// latest returns the most recent version
+func (h *History) latest() (Version, bool) {
+	if len(h.versions) == 0 {
+		return Version{}, false
+	}
+	return h.versions[len(h.versions)-1], true
+}
+
+// get returns the given version
+func (h *History) get(version int) (Version, bool) {
+	if i := h.index(version); i >= 0 {
+		return h.versions[i], true
+	}
+	return Version{}, false
+}
+
+// index returns the position of the given version, or -1 when it is not kept
+func (h *History) index(version int) int {
+	for i, v := range h.versions {
+		if v.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// add numbers and saves a new version, dropping the oldest beyond maxVersions
+func (h *History) add(v Version) (Version, error) {
+	v.Version = 1
+	if latest, ok := h.latest(); ok {
+		v.Version = latest.Version + 1
+	}
+	versions := append(h.versions, v)
+	if len(versions) > maxVersions {
+		versions = versions[len(versions)-maxVersions:]
+	}
+	if err := filestore.SaveJSON(h.path, versions); err != nil {
+		return Version{}, err
+	}
+	h.versions = versions
+	return v, nil
+}
+
+// sameEntries reports whether two schedules hold the same entries in order
+func sameEntries(a, b []Schedule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if scheduleLine(a[i]) != scheduleLine(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+)
+
+// newHistoryStore creates a rotation of the given lines that keeps its history
+func newHistoryStore(t *testing.T, lines ...string) (*Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "schedule.csv")
+	if err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	history, err := LoadHistory(filepath.Join(dir, "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore("stock", filename, clock.NewFake(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)))
+	store.SetHistory(history)
+	return store, filename
+}
+
+// pics returns the PICs of the rotation in order
+func pics(t *testing.T, store *Store) string {
+	t.Helper()
+	schedules, err := store.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, sch := range schedules {
+		names = append(names, sch.PIC)
+	}
+	return strings.Join(names, ",")
+}
+
+// entries returns schedules of the given PICs on consecutive Tuesdays
+func entries(names ...string) []Schedule {
+	var schedules []Schedule
+	for i, name := range names {
+		schedules = append(schedules, Schedule{PIC: name, Date: day(2026, time.October, 20).AddDate(0, 0, 7*i)})
+	}
+	return schedules
+}
+
+func TestHistoryRecordsChanges(t *testing.T) {
+	store, _ := newHistoryStore(t, "Alice,20-Oct-2026,", "Bob,27-Oct-2026,")
+	ctx := audit.WithReason(audit.WithActor(context.Background(), audit.Employee("E001")), "swap")
+	if err := store.Replace(ctx, entries("Bob", "Alice")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Replace(context.Background(), entries("Bob", "Carol")); err != nil {
+		t.Fatal(err)
+	}
+
+	versions := store.History(10)
+	want := []struct {
+		version int
+		action  string
+		actor   string
+		pics    string
+	}{
+		{3, audit.ActionScheduleReplace, audit.ActorBot, "Bob,Carol"},
+		{2, audit.ActionScheduleReplace, "employee:E001", "Bob,Alice"},
+		// The file as it was before the first recorded change
+		{1, ActionEdit, audit.ActorBot, "Alice,Bob"},
+	}
+	if len(versions) != len(want) {
+		t.Fatalf("got %d versions, want %d", len(versions), len(want))
+	}
+	for i, w := range want {
+		v := versions[i]
+		var names []string
+		for _, sch := range v.Entries {
+			names = append(names, sch.PIC)
+		}
+		if v.Version != w.version || v.Action != w.action || strings.Join(names, ",") != w.pics || (i < 2 && v.Actor != w.actor) {
+			t.Errorf("version %d = %+v, want %+v", i, v, w)
+		}
+	}
+	if versions[1].Reason != "swap" {
+		t.Errorf("reason = %q, want the one carried by the context", versions[1].Reason)
+	}
+	if got := store.History(1); len(got) != 1 || got[0].Version != 3 {
+		t.Errorf("History(1) = %+v, want only the latest version", got)
+	}
+}
+
+func TestUndo(t *testing.T) {
+	store, filename := newHistoryStore(t, "Alice,20-Oct-2026,", "Bob,27-Oct-2026,")
+	ctx := context.Background()
+	if _, err := store.Undo(ctx); err == nil || err.Error() != constants.ErrNothingToUndo {
+		t.Fatalf("Undo() without history = %v, want %q", err, constants.ErrNothingToUndo)
+	}
+	for _, names := range [][]string{{"Bob", "Alice"}, {"Carol"}} {
+		if err := store.Replace(ctx, entries(names...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each undo steps back one more version, until the first one
+	steps := []struct {
+		name         string
+		restore      int
+		want         string
+		restoredFrom int
+	}{
+		{"undo", 0, "Bob,Alice", 2},
+		{"undo again", 0, "Alice,Bob", 1},
+		{"nothing left", 0, "", 0},
+		{"restore", 3, "Carol", 3},
+		{"undo the restore", 0, "Alice,Bob", 5},
+	}
+	for _, step := range steps {
+		var restored Version
+		var err error
+		if step.restore > 0 {
+			restored, err = store.Restore(ctx, step.restore)
+		} else {
+			restored, err = store.Undo(ctx)
+		}
+		if step.want == "" {
+			if err == nil || err.Error() != constants.ErrNothingToUndo {
+				t.Errorf("%s: Undo() = %+v, %v, want %q", step.name, restored, err, constants.ErrNothingToUndo)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := pics(t, store); got != step.want || restored.Action != audit.ActionScheduleRestore || restored.RestoredFrom != step.restoredFrom {
+			t.Errorf("%s: rotation = %s, version = %+v, want %s from version %d", step.name, got, restored, step.want, step.restoredFrom)
+		}
+	}
+
+	// A change made outside the bot is undone first
+	if err := os.WriteFile(filename, []byte("Carol,20-Oct-2026,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Undo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := pics(t, store); got != "Alice,Bob" {
+		t.Errorf("after undoing a manual edit rotation = %s, want Alice,Bob", got)
+	}
+	if v := store.History(2)[1]; v.Action != ActionEdit || v.Entries[0].PIC != "Carol" {
+		t.Errorf("manual edit kept as %+v", v)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	store, _ := newHistoryStore(t, "Alice,20-Oct-2026,")
+	ctx := context.Background()
+	for _, names := range [][]string{{"Bob"}, {"Carol"}} {
+		if err := store.Replace(ctx, entries(names...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored, err := store.Restore(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pics(t, store); got != "Bob" || restored.Version != 4 || restored.RestoredFrom != 2 {
+		t.Errorf("Restore(2) = %+v, rotation %s", restored, got)
+	}
+	if _, err := store.Restore(ctx, 99); err == nil || !strings.HasPrefix(err.Error(), constants.ErrVersionNotFound) {
+		t.Errorf("Restore(99) = %v, want %q", err, constants.ErrVersionNotFound)
+	}
+
+	// Versions survive a restart
+	reloaded, err := LoadHistory(filepath.Join(filepath.Dir(store.filename), "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := reloaded.latest(); latest.Version != 4 || latest.RestoredFrom != 2 {
+		t.Errorf("reloaded latest = %+v", latest)
+	}
+}
+
+func TestHistoryKeepsFiftyVersions(t *testing.T) {
+	store, _ := newHistoryStore(t, "Alice,20-Oct-2026,")
+	for i := 0; i < 60; i++ {
+		if err := store.Replace(context.Background(), entries("PIC"+strconv.Itoa(i), "Alice")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions := store.History(100)
+	if len(versions) != maxVersions {
+		t.Fatalf("kept %d versions, want %d", len(versions), maxVersions)
+	}
+	if newest, oldest := versions[0].Version, versions[len(versions)-1].Version; newest != 61 || oldest != 12 {
+		t.Errorf("kept versions %d to %d, want 12 to 61", oldest, newest)
+	}
+	if _, err := store.Restore(context.Background(), 11); err == nil {
+		t.Error("Restore() of a dropped version succeeded")
+	}
+}
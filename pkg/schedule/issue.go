@@ -0,0 +1,68 @@
+package schedule
+
+import "strconv"
+
+// Severities of schedule issues
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Fields of a schedule entry
+const (
+	FieldName  = "name"
+	FieldDate  = "date"
+	FieldEmail = "email"
+)
+
+// Codes identifying the kind of an issue
+const (
+	CodeFields    = "fields"
+	CodeName      = "name"
+	CodeDate      = "date"
+	CodeEmail     = "email"
+	CodeDuplicate = "duplicate"
+	CodeOrder     = "order"
+	CodeOverlap   = "overlap"
+	CodeGap       = "gap"
+	CodeEmpty     = "empty"
+	CodePast      = "past"
+	CodeCurrent   = "current"
+	CodeRunway    = "runway"
+)
+
+// Issue is a problem found on a line of a schedule or of an imported sheet.
+// Line is zero for problems of the schedule as a whole.
+type Issue struct {
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Field    string `json:"field,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the issue as "line 3: error: message (field "value")"
+func (i Issue) String() string {
+	text := i.Severity + ": " + i.Message
+	switch {
+	case i.Field != "" && i.Value != "":
+		text += " (" + i.Field + " " + strconv.Quote(i.Value) + ")"
+	case i.Field != "":
+		text += " (" + i.Field + ")"
+	}
+	if i.Line > 0 {
+		text = "line " + strconv.Itoa(i.Line) + ": " + text
+	}
+	return text
+}
+
+// HasErrors reports whether any of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
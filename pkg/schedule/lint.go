@@ -0,0 +1,256 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+)
+
+// Defaults of the lint options
+const (
+	DefaultPeriodDays = 7
+	DefaultPerPeriod  = 1
+	DefaultRunway     = 2
+)
+
+// LintOptions tunes the checks made by Lint
+type LintOptions struct {
+	// Now places the current week; past-only and runway checks are skipped
+	// when it is zero
+	Now time.Time
+	// PeriodDays is the number of days between two turns of the rotation
+	PeriodDays int
+	// PerPeriod is the number of PICs sharing a turn
+	PerPeriod int
+	// Runway is the number of periods after the current week that should
+	// be scheduled; zero disables the check
+	Runway int
+}
+
+// lintEntry is a schedule entry with the line it was read from
+type lintEntry struct {
+	Schedule
+	line int
+}
+
+// ValidEmail reports whether email is a bare address with a domain name,
+// the form in which PICs are mentioned
+func ValidEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || strings.Contains(email, ",") {
+		return false
+	}
+	_, domain, _ := strings.Cut(email, "@")
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// LintFile checks the schedule file at filename
+func LintFile(filename string, opts LintOptions) ([]Issue, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New(constants.ErrorFileOpen + ": " + err.Error())
+	}
+	return Lint(data, opts), nil
+}
+
+// Lint checks the content of a schedule file, reporting the lines that
+// ReadSchedules skips or fails on, duplicated, overlapping and missing
+// turns, and schedules that have run out or are about to
+func Lint(data []byte, opts LintOptions) []Issue {
+	if opts.PeriodDays <= 0 {
+		opts.PeriodDays = DefaultPeriodDays
+	}
+	if opts.PerPeriod <= 0 {
+		opts.PerPeriod = DefaultPerPeriod
+	}
+
+	var issues []Issue
+	var entries []lintEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		entry, lineIssues := lintLine(line, text)
+		issues = append(issues, lineIssues...)
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+
+	if len(entries) == 0 {
+		return append(issues, Issue{Severity: SeverityError, Code: CodeEmpty, Message: "schedule has no valid entries"})
+	}
+	issues = append(issues, lintEntries(entries, opts)...)
+	issues = append(issues, lintRunway(entries, opts)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// lintLine checks the fields of a line, returning its entry unless
+// ReadSchedules would skip or fail on it
+func lintLine(line int, text string) (*lintEntry, []Issue) {
+	var issues []Issue
+	report := func(severity, code, field, value, message string) {
+		issues = append(issues, Issue{Line: line, Severity: severity, Code: code, Field: field, Value: value, Message: message})
+	}
+
+	parts := strings.Split(text, ",")
+	if len(parts) != 3 {
+		report(SeverityError, CodeFields, "", "", fmt.Sprintf("expected 3 comma-separated fields (name, date, email), found %d; the line is ignored", len(parts)))
+		return nil, issues
+	}
+
+	pic := strings.TrimSpace(parts[0])
+	rawDate := strings.TrimSpace(parts[1])
+	email := strings.TrimSpace(parts[2])
+	if pic == "" {
+		report(SeverityError, CodeName, FieldName, "", "name is empty")
+	}
+	date, err := ParseDate(rawDate)
+	if err != nil {
+		report(SeverityError, CodeDate, FieldDate, rawDate, "date is not in the "+constants.DateFormat+" format; the schedule cannot be read")
+	}
+	switch {
+	case email == "":
+		report(SeverityWarning, CodeEmail, FieldEmail, "", "email is empty, the PIC cannot be mentioned")
+	case !ValidEmail(email):
+		report(SeverityError, CodeEmail, FieldEmail, email, "email is malformed")
+	}
+
+	if pic == "" || err != nil {
+		return nil, issues
+	}
+	return &lintEntry{Schedule: Schedule{PIC: pic, Date: date, Email: email}, line: line}, issues
+}
+
+// lintEntries checks the entries against each other: duplicates, order,
+// turns sharing a period and periods left without a PIC
+func lintEntries(entries []lintEntry, opts LintOptions) []Issue {
+	var issues []Issue
+	seen := make(map[string]int)
+	emails := make(map[string]lintEntry)
+	for i, e := range entries {
+		key := strings.ToLower(e.PIC) + "|" + e.Date.Format(constants.DateFormat)
+		if line, found := seen[key]; found {
+			issues = append(issues, Issue{Line: e.line, Severity: SeverityError, Code: CodeDuplicate,
+				Message: "duplicate of line " + strconv.Itoa(line)})
+		} else {
+			seen[key] = e.line
+		}
+
+		if other, found := emails[strings.ToLower(e.PIC)]; found && e.Email != "" && !strings.EqualFold(other.Email, e.Email) {
+			issues = append(issues, Issue{Line: e.line, Severity: SeverityWarning, Code: CodeDuplicate, Field: FieldEmail, Value: e.Email,
+				Message: "email differs from line " + strconv.Itoa(other.line) + " for the same name"})
+		} else if !found && e.Email != "" {
+			emails[strings.ToLower(e.PIC)] = e
+		}
+
+		if i > 0 && e.Date.Before(entries[i-1].Date) {
+			issues = append(issues, Issue{Line: e.line, Severity: SeverityWarning, Code: CodeOrder,
+				Message: "date is before line " + strconv.Itoa(entries[i-1].line) + "; entries are not in date order"})
+		}
+	}
+
+	sorted := make([]lintEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	// Entries are grouped into turns by date; a turn holds up to PerPeriod
+	// PICs and follows the previous one after exactly PeriodDays
+	period := time.Duration(opts.PeriodDays) * 24 * time.Hour
+	turnStart, turnSize := sorted[0], 1
+	for _, e := range sorted[1:] {
+		gap := e.Date.Sub(turnStart.Date)
+		switch {
+		case gap == 0:
+			turnSize++
+			if turnSize > opts.PerPeriod && seen[strings.ToLower(e.PIC)+"|"+e.Date.Format(constants.DateFormat)] == e.line {
+				issues = append(issues, Issue{Line: e.line, Severity: SeverityWarning, Code: CodeOverlap,
+					Message: fmt.Sprintf("more than %d PIC on %s, as on line %d", opts.PerPeriod, e.Date.Format(constants.DateFormat), turnStart.line)})
+			}
+			continue
+		case gap < period:
+			issues = append(issues, Issue{Line: e.line, Severity: SeverityWarning, Code: CodeOverlap,
+				Message: fmt.Sprintf("overlaps the turn on line %d, only %d days after it", turnStart.line, int(gap.Hours()/24))})
+		case gap > period:
+			issues = append(issues, Issue{Line: e.line, Severity: SeverityWarning, Code: CodeGap,
+				Message: fmt.Sprintf("no PIC between %s and %s", turnStart.Date.Format(constants.DateFormat), e.Date.Format(constants.DateFormat))})
+		}
+		turnStart, turnSize = e, 1
+	}
+	return issues
+}
+
+// Coverage describes how far a schedule reaches from the current week
+type Coverage struct {
+	// Current is the number of PICs scheduled this week
+	Current int
+	// Periods is the number of turns scheduled after this week
+	Periods int
+	// Last is the date of the latest entry, zero when there is none
+	Last time.Time
+}
+
+// CoverageOf measures the schedules against the week containing now
+func CoverageOf(schedules []Schedule, now time.Time) Coverage {
+	var cov Coverage
+	start, end := WeekRange(now)
+	turns := make(map[time.Time]bool)
+	for _, sch := range schedules {
+		if sch.Date.After(cov.Last) {
+			cov.Last = sch.Date
+		}
+		switch {
+		case sch.Date.After(end):
+			turns[sch.Date] = true
+		case !sch.Date.Before(start):
+			cov.Current++
+		}
+	}
+	cov.Periods = len(turns)
+	return cov
+}
+
+// lintRunway reports schedules whose entries are all in the past, that have
+// no PIC this week or fewer turns ahead than the runway
+func lintRunway(entries []lintEntry, opts LintOptions) []Issue {
+	if opts.Now.IsZero() {
+		return nil
+	}
+	schedules := make([]Schedule, 0, len(entries))
+	for _, e := range entries {
+		schedules = append(schedules, e.Schedule)
+	}
+	cov := CoverageOf(schedules, opts.Now)
+
+	start, _ := WeekRange(opts.Now)
+	if cov.Last.Before(start) {
+		return []Issue{{Severity: SeverityError, Code: CodePast,
+			Message: "every entry is before the current week; the last one is on " + cov.Last.Format(constants.DateFormat)}}
+	}
+	var issues []Issue
+	if cov.Current == 0 {
+		issues = append(issues, Issue{Severity: SeverityWarning, Code: CodeCurrent, Message: "no PIC is scheduled this week"})
+	}
+	if opts.Runway > 0 && cov.Periods < opts.Runway {
+		issues = append(issues, Issue{Severity: SeverityWarning, Code: CodeRunway,
+			Message: fmt.Sprintf("only %d periods are scheduled after this week, %d wanted; the last entry is on %s", cov.Periods, opts.Runway, cov.Last.Format(constants.DateFormat))})
+	}
+	return issues
+}
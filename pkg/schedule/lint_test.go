@@ -0,0 +1,148 @@
+package schedule
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// brief describes an issue as "line severity code field"
+func brief(i Issue) string {
+	return fmt.Sprintf("%d %s %s %s", i.Line, i.Severity, i.Code, i.Field)
+}
+
+func TestLint(t *testing.T) {
+	// Wednesday of the week starting on Tuesday 20 October 2026
+	now := time.Date(2026, time.October, 21, 9, 0, 0, 0, jakarta)
+
+	tests := []struct {
+		name string
+		data string
+		opts LintOptions
+		want []string
+	}{
+		{
+			name: "valid",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026,bob@example.com\nCarol,3-Nov-2026,carol@example.com\n",
+		},
+		{
+			name: "field count",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026\nCarol,3-Nov-2026,carol@example.com,extra\n",
+			want: []string{"2 error fields ", "3 error fields "},
+		},
+		{
+			name: "name, date and email",
+			data: " ,20-Oct-2026,alice@example.com\nBob,2026-10-27,bob@example.com\n\nCarol,3-Nov-2026,\nDave,10-Nov-2026,dave@example\n",
+			want: []string{"1 error name name", "2 error date date", "4 warning email email", "5 error email email"},
+		},
+		{
+			name: "duplicate is not also an overlap",
+			data: "Alice,20-Oct-2026,alice@example.com\nalice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026,bob@example.com\n",
+			want: []string{"2 error duplicate "},
+		},
+		{
+			name: "email differs for the same name",
+			data: "Alice,20-Oct-2026,alice@example.com\nAlice,27-Oct-2026,alice2@example.com\n",
+			want: []string{"2 warning duplicate email"},
+		},
+		{
+			name: "order",
+			data: "Alice,27-Oct-2026,alice@example.com\nBob,20-Oct-2026,bob@example.com\n",
+			want: []string{"2 warning order "},
+		},
+		{
+			name: "too many PICs in a turn",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,20-Oct-2026,bob@example.com\nCarol,27-Oct-2026,carol@example.com\n",
+			want: []string{"2 warning overlap "},
+		},
+		{
+			name: "shared turns within PerPeriod",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,20-Oct-2026,bob@example.com\nCarol,27-Oct-2026,carol@example.com\n",
+			opts: LintOptions{PerPeriod: 2},
+		},
+		{
+			name: "turn too soon",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,23-Oct-2026,bob@example.com\n",
+			want: []string{"2 warning overlap "},
+		},
+		{
+			name: "gap",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,3-Nov-2026,bob@example.com\n",
+			want: []string{"2 warning gap "},
+		},
+		{
+			name: "other period length",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,3-Nov-2026,bob@example.com\n",
+			opts: LintOptions{PeriodDays: 14},
+		},
+		{
+			name: "no valid entries",
+			data: "not a schedule\n",
+			want: []string{"1 error fields ", "0 error empty "},
+		},
+		{
+			name: "past only",
+			data: "Alice,6-Oct-2026,alice@example.com\nBob,13-Oct-2026,bob@example.com\n",
+			opts: LintOptions{Now: now, Runway: 2},
+			want: []string{"0 error past "},
+		},
+		{
+			name: "no PIC this week and short runway",
+			data: "Alice,27-Oct-2026,alice@example.com\nBob,3-Nov-2026,bob@example.com\n",
+			opts: LintOptions{Now: now, Runway: 3},
+			want: []string{"0 warning current ", "0 warning runway "},
+		},
+		{
+			name: "runway covered",
+			data: "Alice,20-Oct-2026,alice@example.com\nBob,27-Oct-2026,bob@example.com\nCarol,3-Nov-2026,carol@example.com\n",
+			opts: LintOptions{Now: now, Runway: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range Lint([]byte(tt.data), tt.opts) {
+				got = append(got, brief(issue))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintMessages(t *testing.T) {
+	issues := Lint([]byte("Alice,20-Oct-2026,alice@example.com\nAlice,20-Oct-2026,alice@example.com\nBob,2026-10-27,bob@example.com\n"), LintOptions{})
+	want := []string{
+		"line 2: error: duplicate of line 1",
+		`line 3: error: date is not in the 2-Jan-2006 format; the schedule cannot be read (date "2026-10-27")`,
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %q, want %q", got, want)
+	}
+	if !HasErrors(issues) {
+		t.Error("HasErrors() = false, want true")
+	}
+}
+
+func TestValidEmail(t *testing.T) {
+	tests := map[string]bool{
+		"alice@example.com":           true,
+		"alice.smith@mail.example.co": true,
+		"alice@example":               false,
+		"Alice <alice@example.com>":   false,
+		"alice@example.com,bob":       false,
+		"alice":                       false,
+		"":                            false,
+	}
+	for email, want := range tests {
+		if got := ValidEmail(email); got != want {
+			t.Errorf("ValidEmail(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
@@ -1,21 +1,23 @@
-package eventcallback
+package schedule
 
 import (
 	"bufio"
 	"errors"
 	"os"
 	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/mention"
 	"sort"
 	"strings"
 	"time"
 )
 
-// Define constants
+// jakarta is the time zone the rotation weeks follow
+var jakarta = time.FixedZone("Asia/Jakarta", 7*60*60)
 
 type Schedule struct {
-	PIC   string    // Name of the Person In Charge
-	Date  time.Time // Date of the schedule
-	Email string    // Email of the Person In Charge
+	PIC   string    `json:"pic"`   // Name of the Person In Charge
+	Date  time.Time `json:"date"`  // Date of the schedule
+	Email string    `json:"email"` // Email of the Person In Charge
 }
 
 func ParseDate(dateStr string) (time.Time, error) {
@@ -108,6 +110,11 @@ func UpdatePreviousPICDate(filename, currentPIC string) error {
 	})
 
 	// Write the updated schedules back to the file
+	return WriteSchedules(filename, schedules)
+}
+
+// WriteSchedules writes the schedules to file in the format read by ReadSchedules
+func WriteSchedules(filename string, schedules []Schedule) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return errors.New(constants.ErrorFileCreate + ": " + err.Error())
@@ -131,40 +138,60 @@ func UpdatePreviousPICDate(filename, currentPIC string) error {
 
 // Get the start and end date of the current week
 func GetCurrentWeekRange() (time.Time, time.Time) {
-	loc := time.FixedZone("Asia/Jakarta", 7*60*60)
-	now := time.Now().In(loc)
+	return WeekRange(time.Now())
+}
+
+// WeekRange returns the Tuesday-to-Monday week containing now in Jakarta time.
+// The bounds are at UTC midnight of the Jakarta calendar days, matching the
+// dates parsed from schedule files.
+func WeekRange(now time.Time) (time.Time, time.Time) {
+	local := now.In(jakarta)
 
 	// Calculate the start of the current week (Tuesday)
-	offset := int(time.Tuesday) - int(now.Weekday())
+	offset := int(time.Tuesday) - int(local.Weekday())
 	if offset > 0 {
 		offset -= 7
 	}
-	startOfWeek := now.AddDate(0, 0, offset).Truncate(24 * time.Hour).In(loc)
+	tuesday := local.AddDate(0, 0, offset)
+	startOfWeek := time.Date(tuesday.Year(), tuesday.Month(), tuesday.Day(), 0, 0, 0, 0, time.UTC)
 
 	// Calculate the end of the current week (Monday of the next week)
-	endOfWeek := startOfWeek.AddDate(0, 0, 6).Truncate(24 * time.Hour).In(loc)
+	endOfWeek := startOfWeek.AddDate(0, 0, 6)
 	endOfWeek = endOfWeek.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 
 	return startOfWeek, endOfWeek
 }
 
-// Display PICs within a date range
-func DisplayPICsWithinRange(schedules []Schedule, startDate, endDate time.Time) string {
-	var result strings.Builder
+// Day returns the Jakarta calendar day of now at UTC midnight, the form in
+// which schedule dates are stored
+func Day(now time.Time) time.Time {
+	local := now.In(jakarta)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
 
+// SchedulesWithinRange returns the schedules dated between startDate and endDate inclusive
+func SchedulesWithinRange(schedules []Schedule, startDate, endDate time.Time) []Schedule {
+	var result []Schedule
 	for _, schedule := range schedules {
 		if (schedule.Date.After(startDate) || schedule.Date.Equal(startDate)) &&
 			(schedule.Date.Before(endDate) || schedule.Date.Equal(endDate)) {
-			result.WriteString(strings.Join([]string{
-				"Date: " + schedule.Date.Format("2006-01-02"),
-				"- PIC: " + schedule.PIC,
-				" <mention-tag target=\"seatalk://user?email=" + schedule.Email + "\"/>",
-			}, "") + "\n")
-			if err := UpdatePreviousPICDate(constants.StockInventoryScheduleFile, schedule.PIC); err != nil {
-				// Optionally log the error or handle it as needed
-			}
+			result = append(result, schedule)
 		}
 	}
+	return result
+}
+
+// FormatPICsWithinRange lists the PICs within a date range with a mention of each
+func FormatPICsWithinRange(schedules []Schedule, startDate, endDate time.Time) string {
+	var result strings.Builder
+
+	for _, schedule := range SchedulesWithinRange(schedules, startDate, endDate) {
+		result.WriteString(strings.Join([]string{
+			"Date: " + schedule.Date.Format("2006-01-02"),
+			"- PIC: " + schedule.PIC,
+			" " + mention.Email(schedule.Email),
+		}, "") + "\n")
+	}
 	return result.String()
 }
 
@@ -0,0 +1,141 @@
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+)
+
+// day returns the date at UTC midnight, the form schedule dates are stored in
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestWeekRange(t *testing.T) {
+	weekOf13 := day(2026, time.October, 13)
+	weekOf20 := day(2026, time.October, 20)
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		start time.Time
+	}{
+		{"tuesday", time.Date(2026, time.October, 13, 12, 0, 0, 0, jakarta), weekOf13},
+		{"wednesday", time.Date(2026, time.October, 14, 12, 0, 0, 0, jakarta), weekOf13},
+		{"thursday", time.Date(2026, time.October, 15, 12, 0, 0, 0, jakarta), weekOf13},
+		{"friday", time.Date(2026, time.October, 16, 12, 0, 0, 0, jakarta), weekOf13},
+		{"saturday", time.Date(2026, time.October, 17, 12, 0, 0, 0, jakarta), weekOf13},
+		{"sunday", time.Date(2026, time.October, 18, 12, 0, 0, 0, jakarta), weekOf13},
+		{"monday", time.Date(2026, time.October, 19, 12, 0, 0, 0, jakarta), weekOf13},
+		{"next tuesday", time.Date(2026, time.October, 20, 12, 0, 0, 0, jakarta), weekOf20},
+		{"tuesday midnight in jakarta", time.Date(2026, time.October, 20, 0, 0, 0, 0, jakarta), weekOf20},
+		{"monday last second in jakarta", time.Date(2026, time.October, 19, 23, 59, 59, 0, jakarta), weekOf13},
+		{"monday in utc, tuesday in jakarta", time.Date(2026, time.October, 19, 17, 0, 0, 0, time.UTC), weekOf20},
+		{"monday in utc, still monday in jakarta", time.Date(2026, time.October, 19, 16, 59, 59, 0, time.UTC), weekOf13},
+		{"across a month", time.Date(2026, time.November, 2, 9, 0, 0, 0, jakarta), day(2026, time.October, 27)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := WeekRange(tt.now)
+			wantEnd := tt.start.AddDate(0, 0, 7).Add(-time.Second)
+			if !start.Equal(tt.start) || !end.Equal(wantEnd) {
+				t.Errorf("WeekRange(%v) = %v - %v, want %v - %v", tt.now, start, end, tt.start, wantEnd)
+			}
+		})
+	}
+}
+
+func TestStoreCurrentWeekFollowsClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, time.October, 19, 16, 59, 59, 0, time.UTC))
+	store := NewStore("test", filepath.Join(t.TempDir(), "schedule.csv"), clk)
+
+	if start, _ := store.CurrentWeek(); !start.Equal(day(2026, time.October, 13)) {
+		t.Errorf("week starts %v, want 2026-10-13", start)
+	}
+	clk.Advance(time.Second)
+	if start, _ := store.CurrentWeek(); !start.Equal(day(2026, time.October, 20)) {
+		t.Errorf("week starts %v after advancing the clock, want 2026-10-20", start)
+	}
+}
+
+func TestStoreAdvanceAcrossWeeks(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "schedule.csv")
+	data := "Alice,13-Oct-2026,alice@example.com\nBob,20-Oct-2026,bob@example.com\nCarol,27-Oct-2026,carol@example.com\n"
+	if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(time.Date(2026, time.October, 20, 9, 0, 0, 0, jakarta))
+	store := NewStore("test", filename, clk)
+
+	// advance rotates the PIC of the current week and returns the schedule
+	advance := func(wantPIC string) []Schedule {
+		t.Helper()
+		schedules, err := store.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, end := store.CurrentWeek()
+		current := SchedulesWithinRange(schedules, start, end)
+		if len(current) != 1 || current[0].PIC != wantPIC {
+			t.Fatalf("PICs of the week of %v = %v, want %s", start, current, wantPIC)
+		}
+		if err := store.Advance(context.Background(), wantPIC); err != nil {
+			t.Fatalf("Advance(%s): %v", wantPIC, err)
+		}
+		schedules, err = store.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return schedules
+	}
+	assertSchedule := func(got []Schedule, want []Schedule) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i].PIC != want[i].PIC || !got[i].Date.Equal(want[i].Date) || got[i].Email != want[i].Email {
+				t.Errorf("entry %d = %v, want %v", i, got[i], want[i])
+			}
+		}
+	}
+
+	assertSchedule(advance("Bob"), []Schedule{
+		{PIC: "Bob", Date: day(2026, time.October, 20), Email: "bob@example.com"},
+		{PIC: "Carol", Date: day(2026, time.October, 27), Email: "carol@example.com"},
+		{PIC: "Alice", Date: day(2026, time.November, 3), Email: "alice@example.com"},
+	})
+
+	clk.Advance(7 * 24 * time.Hour)
+	assertSchedule(advance("Carol"), []Schedule{
+		{PIC: "Carol", Date: day(2026, time.October, 27), Email: "carol@example.com"},
+		{PIC: "Alice", Date: day(2026, time.November, 3), Email: "alice@example.com"},
+		{PIC: "Bob", Date: day(2026, time.November, 10), Email: "bob@example.com"},
+	})
+}
+
+func TestStoreAdvanceErrors(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "schedule.csv")
+	if err := os.WriteFile(filename, []byte("Alice,13-Oct-2026,alice@example.com\nBob,20-Oct-2026,bob@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore("test", filename, clock.NewFake(time.Date(2026, time.October, 13, 9, 0, 0, 0, jakarta)))
+
+	tests := []struct {
+		pic  string
+		want string
+	}{
+		{"Alice", constants.ErrorPreviousPICNotFound},
+		{"Dave", constants.ErrorPICNotFound},
+	}
+	for _, tt := range tests {
+		if err := store.Advance(context.Background(), tt.pic); err == nil || err.Error() != tt.want {
+			t.Errorf("Advance(%s) = %v, want %q", tt.pic, err, tt.want)
+		}
+	}
+}
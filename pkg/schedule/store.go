@@ -0,0 +1,284 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/internal/clock"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/audit"
+)
+
+// Store serializes access to the schedule file of a single rotation
+type Store struct {
+	name     string
+	filename string
+	clock    clock.Clock
+	audit    *audit.Log
+	history  *History
+	mu       sync.RWMutex
+}
+
+// NewStore creates a Store for the named rotation backed by filename.
+// The clock decides which week is current.
+func NewStore(name, filename string, clk clock.Clock) *Store {
+	return &Store{name: name, filename: filename, clock: clk}
+}
+
+// SetAudit records every mutation of the rotation in the audit log
+func (s *Store) SetAudit(log *audit.Log) {
+	s.audit = log
+}
+
+// SetHistory keeps a version of the rotation after every mutation
+func (s *Store) SetHistory(history *History) {
+	s.history = history
+}
+
+// Name returns the rotation name
+func (s *Store) Name() string {
+	return s.name
+}
+
+// Filename returns the schedule file backing the rotation
+func (s *Store) Filename() string {
+	return s.filename
+}
+
+// CurrentWeek returns the bounds of the current rotation week
+func (s *Store) CurrentWeek() (time.Time, time.Time) {
+	return WeekRange(s.clock.Now())
+}
+
+// Read returns the schedules of the rotation
+func (s *Store) Read() ([]Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return ReadSchedules(s.filename)
+}
+
+// Lint checks the schedule file of the rotation. The current week is taken
+// from the store clock unless opts sets it.
+func (s *Store) Lint(opts LintOptions) ([]Issue, error) {
+	if opts.Now.IsZero() {
+		opts.Now = s.clock.Now()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return LintFile(s.filename, opts)
+}
+
+// Replace overwrites the rotation with the given schedules, sorted by date.
+// The actor and reason carried by ctx are recorded in the audit log.
+func (s *Store) Replace(ctx context.Context, schedules []Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, _ := ReadSchedules(s.filename)
+	sorted := append([]Schedule(nil), schedules...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	err := WriteSchedules(s.filename, sorted)
+	s.record(ctx, Version{Action: audit.ActionScheduleReplace, Entries: sorted}, before, err)
+	return err
+}
+
+// Advance moves the PIC before currentPIC to the end of the rotation
+func (s *Store) Advance(ctx context.Context, currentPIC string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, _ := ReadSchedules(s.filename)
+	err := UpdatePreviousPICDate(s.filename, currentPIC)
+	after, _ := ReadSchedules(s.filename)
+	s.record(ctx, Version{Action: audit.ActionScheduleAdvance, Entries: after}, before, err)
+	return err
+}
+
+// History returns up to limit versions of the rotation, newest first
+func (s *Store) History(limit int) []Version {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.history == nil {
+		return nil
+	}
+	var versions []Version
+	for i := len(s.history.versions) - 1; i >= 0 && len(versions) < limit; i-- {
+		versions = append(versions, s.history.versions[i])
+	}
+	return versions
+}
+
+// Restore brings the rotation back to the given version, recorded as a new version
+func (s *Store) Restore(ctx context.Context, version int) (Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.history == nil {
+		return Version{}, errors.New(constants.ErrVersionNotFound + ": " + strconv.Itoa(version))
+	}
+	target, ok := s.history.get(version)
+	if !ok {
+		return Version{}, errors.New(constants.ErrVersionNotFound + ": " + strconv.Itoa(version))
+	}
+	return s.restore(ctx, target, false)
+}
+
+// Undo reverts the latest change of the rotation. A change made outside the
+// bot is reverted first; each further undo steps back one more version.
+func (s *Store) Undo(ctx context.Context) (Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.history == nil {
+		return Version{}, errors.New(constants.ErrNothingToUndo)
+	}
+	current, err := ReadSchedules(s.filename)
+	if err != nil {
+		return Version{}, err
+	}
+
+	versions := s.history.versions
+	if len(versions) == 0 {
+		return Version{}, errors.New(constants.ErrNothingToUndo)
+	}
+	latest := len(versions) - 1
+	if !sameEntries(current, versions[latest].Entries) {
+		return s.restore(ctx, versions[latest], true)
+	}
+
+	// Undos go back to the version they restored, so that the next one steps
+	// back past it
+	i := latest
+	for versions[i].Undo {
+		if i = s.history.index(versions[i].RestoredFrom); i < 0 {
+			return Version{}, errors.New(constants.ErrNothingToUndo)
+		}
+	}
+	if i == 0 {
+		return Version{}, errors.New(constants.ErrNothingToUndo)
+	}
+	return s.restore(ctx, versions[i-1], true)
+}
+
+// restore writes the entries of target to the schedule file, marking the
+// new version as an undo when it is one
+func (s *Store) restore(ctx context.Context, target Version, undo bool) (Version, error) {
+	before, _ := ReadSchedules(s.filename)
+	err := WriteSchedules(s.filename, target.Entries)
+	restored := s.record(ctx, Version{
+		Action:       audit.ActionScheduleRestore,
+		RestoredFrom: target.Version,
+		Undo:         undo,
+		Entries:      target.Entries,
+	}, before, err)
+	return restored, err
+}
+
+// record writes a schedule mutation to the audit log with the lines it
+// removed and added, and keeps the resulting version in the history
+func (s *Store) record(ctx context.Context, v Version, before []Schedule, err error) Version {
+	details := map[string]string{"entries": strconv.Itoa(len(v.Entries))}
+	removed, added := diffSchedules(before, v.Entries)
+	if len(removed) > 0 {
+		details["removed"] = strings.Join(removed, "; ")
+	}
+	if len(added) > 0 {
+		details["added"] = strings.Join(added, "; ")
+	}
+	if v.RestoredFrom > 0 {
+		details["restored_version"] = strconv.Itoa(v.RestoredFrom)
+	}
+	s.audit.RecordResult(ctx, audit.Entry{Action: v.Action, Target: s.name, Details: details}, err)
+
+	if err != nil || s.history == nil {
+		return v
+	}
+
+	// Keep the state the change started from when the history does not end
+	// with it, so that the change can be undone
+	now := s.clock.Now()
+	if latest, ok := s.history.latest(); before != nil && (!ok || !sameEntries(latest.Entries, before)) {
+		if _, err := s.history.add(Version{Time: now, Action: ActionEdit, Entries: before}); err != nil {
+			slog.Warn("failed to save schedule version", "rotation", s.name, "error", err)
+		}
+	}
+
+	v.Time, v.Actor, v.Reason = now, audit.ActorFrom(ctx), audit.ReasonFrom(ctx)
+	saved, err := s.history.add(v)
+	if err != nil {
+		slog.Warn("failed to save schedule version", "rotation", s.name, "error", err)
+		return v
+	}
+	return saved
+}
+
+// diffSchedules returns the schedule lines only in before and only in after
+func diffSchedules(before, after []Schedule) ([]string, []string) {
+	count := make(map[string]int, len(before))
+	for _, sch := range before {
+		count[scheduleLine(sch)]++
+	}
+	var added []string
+	for _, sch := range after {
+		line := scheduleLine(sch)
+		if count[line] > 0 {
+			count[line]--
+			continue
+		}
+		added = append(added, line)
+	}
+	var removed []string
+	for _, sch := range before {
+		line := scheduleLine(sch)
+		if count[line] > 0 {
+			count[line]--
+			removed = append(removed, line)
+		}
+	}
+	return removed, added
+}
+
+// scheduleLine formats a schedule the way it is stored
+func scheduleLine(sch Schedule) string {
+	return sch.PIC + "," + sch.Date.Format(constants.DateFormat) + "," + sch.Email
+}
+
+// Registry holds the stores of every rotation the bot manages
+type Registry struct {
+	stores map[string]*Store
+	names  []string
+}
+
+// NewRegistry creates a Registry from the given stores
+func NewRegistry(stores ...*Store) *Registry {
+	r := &Registry{stores: make(map[string]*Store, len(stores))}
+	for _, store := range stores {
+		r.stores[store.Name()] = store
+		r.names = append(r.names, store.Name())
+	}
+	sort.Strings(r.names)
+	return r
+}
+
+// Get returns the store of the named rotation
+func (r *Registry) Get(name string) (*Store, error) {
+	store, ok := r.stores[name]
+	if !ok {
+		return nil, errors.New(constants.ErrRotationNotFound + ": " + name)
+	}
+	return store, nil
+}
+
+// Names returns the rotation names in alphabetical order
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
@@ -0,0 +1,174 @@
+package seatalk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"seatalk-bot/internal/config"
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/metrics"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	tokernservice "seatalk-bot/pkg/tokenservice"
+)
+
+// Sender sends bot messages to SeaTalk
+type Sender interface {
+	SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error)
+	SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error)
+}
+
+// Client sends messages through the SeaTalk Open Platform API
+type Client struct {
+	config     *config.Config
+	tokens     *tokernservice.TokenService
+	httpClient *http.Client
+}
+
+// NewClient creates a new Client authenticating with tokens from the TokenService
+func NewClient(cfg *config.Config, tokens *tokernservice.TokenService) *Client {
+	return &Client{
+		config:     cfg,
+		tokens:     tokens,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SendMessageToSubscriber sends a message to a subscriber using the Seatalk API
+func (c *Client) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	apiURL := c.config.SingleChatUrl
+
+	// Count the message as in flight and record the call once it completes
+	metrics.SendsInFlightAdd(1)
+	defer metrics.SendsInFlightAdd(-1)
+	var status, code int
+	start := time.Now()
+	defer func() { metrics.ObserveSeaTalkRequest("single_chat", status, code, time.Since(start)) }()
+
+	// Marshal the request into JSON
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToMarshalPayload)
+	}
+
+	// Create a new HTTP request
+	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToCreateRequest)
+	}
+
+	// Set the request headers
+	token, err := c.tokens.RefreshToken()
+	if err != nil {
+		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToGetToken + ": " + err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	// Send the request
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToExecuteRequest)
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+
+	// Check for a successful status code
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return response.SendMessageToBotSubscriberResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
+		}
+		code = errorResponse.Code
+		return response.SendMessageToBotSubscriberResponse{
+			Code: errorResponse.Code,
+		}, errors.New(constants.ErrApiError)
+	}
+
+	// Parse the response
+	var response response.SendMessageToBotSubscriberResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return response, errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	code = response.Code
+	if response.Code != 0 {
+		return response, errors.New(constants.ErrApiError + ": code " + strconv.Itoa(response.Code))
+	}
+
+	return response, nil
+}
+
+// SendMessageToGroup sends a message to a group
+func (c *Client) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	apiURL := c.config.GroupChatUrl
+
+	// Count the message as in flight and record the call once it completes
+	metrics.SendsInFlightAdd(1)
+	defer metrics.SendsInFlightAdd(-1)
+	var status, code int
+	start := time.Now()
+	defer func() { metrics.ObserveSeaTalkRequest("group_chat", status, code, time.Since(start)) }()
+
+	// Marshal the request into JSON
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToMarshalPayload)
+	}
+
+	// Create a new HTTP request
+	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToCreateRequest)
+	}
+
+	// Set the request headers
+	token, err := c.tokens.RefreshToken()
+	if err != nil {
+		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToGetToken + ": " + err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	// Send the request
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToExecuteRequest)
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+
+	// Check for a successful status code
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
+		}
+		code = errorResponse.Code
+		return response.SendMessageToBotGroupResponse{
+			Code:      errorResponse.Code,
+			MessegeId: "0",
+		}, errors.New(constants.ErrApiError)
+	}
+
+	// Parse the response
+	var groupResponse response.SendMessageToBotGroupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&groupResponse); err != nil {
+		return response.SendMessageToBotGroupResponse{}, errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	code = groupResponse.Code
+	if groupResponse.Code != 0 {
+		return groupResponse, errors.New(constants.ErrApiError + ": code " + strconv.Itoa(groupResponse.Code))
+	}
+
+	return groupResponse, nil
+}
@@ -0,0 +1,94 @@
+package seatalk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/metrics"
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// Contacts reads employee profiles from SeaTalk
+type Contacts interface {
+	EmployeeProfiles(codes []string) ([]response.EmployeeProfile, error)
+	EmployeeCodesByEmail(emails []string) ([]response.EmployeeCodeResult, error)
+}
+
+// EmployeeProfiles fetches the profiles of the employees with the given codes
+func (c *Client) EmployeeProfiles(codes []string) ([]response.EmployeeProfile, error) {
+	query := url.Values{"employee_code": {strings.Join(codes, ",")}}
+	httpReq, err := http.NewRequest("GET", c.config.ProfileUrl+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errors.New(constants.ErrFailedToCreateRequest)
+	}
+
+	var resp response.GetEmployeeProfilesResponse
+	if err := c.call("profile", httpReq, &resp, &resp.Code); err != nil {
+		return nil, err
+	}
+	return resp.Employees, nil
+}
+
+// EmployeeCodesByEmail looks up the employee codes registered with the given emails
+func (c *Client) EmployeeCodesByEmail(emails []string) ([]response.EmployeeCodeResult, error) {
+	requestBody, err := json.Marshal(request.GetEmployeeCodesByEmailRequest{Emails: emails})
+	if err != nil {
+		return nil, errors.New(constants.ErrFailedToMarshalPayload)
+	}
+	httpReq, err := http.NewRequest("POST", c.config.EmployeeCodeUrl, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, errors.New(constants.ErrFailedToCreateRequest)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	var resp response.GetEmployeeCodesByEmailResponse
+	if err := c.call("employee_code_with_email", httpReq, &resp, &resp.Code); err != nil {
+		return nil, err
+	}
+	return resp.Employees, nil
+}
+
+// call sends an authenticated request and decodes the response into out,
+// whose SeaTalk response code is read from code
+func (c *Client) call(endpoint string, httpReq *http.Request, out interface{}, code *int) error {
+	var status int
+	start := time.Now()
+	defer func() { metrics.ObserveSeaTalkRequest(endpoint, status, *code, time.Since(start)) }()
+
+	token, err := c.tokens.RefreshToken()
+	if err != nil {
+		return errors.New(constants.ErrFailedToGetToken + ": " + err.Error())
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return errors.New(constants.ErrFailedToExecuteRequest)
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return errors.New(constants.ErrApiError + ": " + resp.Status)
+		}
+		return errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	if resp.StatusCode != http.StatusOK || *code != 0 {
+		return errors.New(constants.ErrApiError + ": code " + strconv.Itoa(*code))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package seatalk
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// dryRunRecord is a rendered message written by the DryRunSender
+type dryRunRecord struct {
+	Time      time.Time   `json:"time"`
+	Kind      string      `json:"kind"`
+	MessageID string      `json:"message_id"`
+	Payload   interface{} `json:"payload"`
+}
+
+// DryRunSender renders outgoing messages to the log, and optionally to a
+// writer as JSON lines, instead of sending them to SeaTalk
+type DryRunSender struct {
+	mu  sync.Mutex
+	out io.Writer
+	seq int
+}
+
+// NewDryRunSender creates a DryRunSender; out may be nil to only log messages
+func NewDryRunSender(out io.Writer) *DryRunSender {
+	return &DryRunSender{out: out}
+}
+
+// SendMessageToSubscriber renders a message that would be sent to a subscriber
+func (d *DryRunSender) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	d.record("subscriber", req, slog.String("employee_code", req.EmployeeCode), slog.String("content", req.Message.Text.Content))
+	return response.SendMessageToBotSubscriberResponse{Code: 0}, nil
+}
+
+// SendMessageToGroup renders a message that would be sent to a group
+func (d *DryRunSender) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	messageID := d.record("group", req, slog.String("group_id", req.GroupID), slog.String("content", req.Message.Text.Content))
+	return response.SendMessageToBotGroupResponse{Code: 0, MessegeId: messageID}, nil
+}
+
+// record logs the payload and appends it to the output, returning a fake message ID
+func (d *DryRunSender) record(kind string, payload interface{}, attrs ...any) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.seq++
+	messageID := "dry-run-" + strconv.Itoa(d.seq)
+	slog.Info("dry run: message not sent", append([]any{"kind", kind, "message_id", messageID}, attrs...)...)
+
+	if d.out == nil {
+		return messageID
+	}
+	line, err := json.Marshal(dryRunRecord{
+		Time:      time.Now(),
+		Kind:      kind,
+		MessageID: messageID,
+		Payload:   payload,
+	})
+	if err != nil {
+		slog.Error("dry run: failed to render message", "error", err)
+		return messageID
+	}
+	if _, err := d.out.Write(append(line, '\n')); err != nil {
+		slog.Error("dry run: failed to write message", "error", err)
+	}
+	return messageID
+}
@@ -0,0 +1,46 @@
+package seatalk
+
+import (
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// SandboxSender redirects every message to a single test group. The original
+// recipient is noted at the top of the message.
+type SandboxSender struct {
+	next    Sender
+	groupID string
+}
+
+// NewSandboxSender creates a SandboxSender delivering through next
+func NewSandboxSender(next Sender, groupID string) *SandboxSender {
+	return &SandboxSender{next: next, groupID: groupID}
+}
+
+// SendMessageToSubscriber posts the direct message to the sandbox group instead
+func (s *SandboxSender) SendMessageToSubscriber(req request.SendMessageToBotSubscriberRequest) (response.SendMessageToBotSubscriberResponse, error) {
+	resp, err := s.next.SendMessageToGroup(request.SendMessageToBotGroupRequest{
+		GroupID: s.groupID,
+		Message: request.MessageGroup{
+			Tag: req.Message.Tag,
+			Text: request.TextGroup{
+				Format:  req.Message.Text.Format,
+				Content: "[sandbox: direct message to " + req.EmployeeCode + "]\n" + req.Message.Text.Content,
+			},
+		},
+	})
+	return response.SendMessageToBotSubscriberResponse{Code: resp.Code}, err
+}
+
+// SendMessageToGroup posts the message to the sandbox group instead of its target.
+// Thread and quote references are dropped since they belong to the original group.
+func (s *SandboxSender) SendMessageToGroup(req request.SendMessageToBotGroupRequest) (response.SendMessageToBotGroupResponse, error) {
+	redirected := req
+	redirected.GroupID = s.groupID
+	redirected.Message.ThreadID = ""
+	redirected.Message.QuotedMessageID = ""
+	if req.GroupID != s.groupID {
+		redirected.Message.Text.Content = "[sandbox: message to group " + req.GroupID + "]\n" + req.Message.Text.Content
+	}
+	return s.next.SendMessageToGroup(redirected)
+}
@@ -0,0 +1,43 @@
+package seatalk
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"seatalk-bot/internal/config"
+	tokernservice "seatalk-bot/pkg/tokenservice"
+)
+
+// NewSender builds the sender selected by the configuration: the SeaTalk
+// client, or a DryRunSender in dry-run mode, optionally redirected to the
+// sandbox group
+func NewSender(cfg *config.Config, tokens *tokernservice.TokenService) Sender {
+	var sender Sender = NewClient(cfg, tokens)
+	if cfg.DryRun {
+		sender = NewDryRunSender(openDryRunOutput(cfg.DryRunOutput))
+		slog.Warn("dry-run mode enabled, messages will not be sent", "output", cfg.DryRunOutput)
+	}
+	if cfg.SandboxGroupID != "" {
+		sender = NewSandboxSender(sender, cfg.SandboxGroupID)
+		slog.Warn("sandbox mode enabled, messages are redirected", "group_id", cfg.SandboxGroupID)
+	}
+	return sender
+}
+
+// openDryRunOutput opens the file dry-run messages are appended to
+func openDryRunOutput(path string) io.Writer {
+	switch path {
+	case "":
+		return nil
+	case "-":
+		return os.Stdout
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		slog.Error("failed to open dry-run output, logging messages only", "file", path, "error", err)
+		return nil
+	}
+	return file
+}
@@ -0,0 +1,103 @@
+package seatalkemu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/models/request"
+	"seatalk-bot/pkg/eventrouter"
+)
+
+// CallbackResult is the bot's answer to a fired callback
+type CallbackResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// FireCallback sends a signed event callback of the given type to the bot
+func (e *Emulator) FireCallback(ctx context.Context, botURL, eventType string, event interface{}) (CallbackResult, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return CallbackResult{}, errors.New(constants.ErrFailedToMarshalPayload)
+	}
+
+	e.mu.Lock()
+	e.seq++
+	eventID := "emu-event-" + strconv.Itoa(e.seq)
+	e.mu.Unlock()
+
+	body, err := json.Marshal(request.EventCallbackEnvelope{
+		EventID:   eventID,
+		EventType: eventType,
+		Timestamp: time.Now().Unix(),
+		AppID:     e.opts.AppID,
+		Event:     payload,
+	})
+	if err != nil {
+		return CallbackResult{}, errors.New(constants.ErrFailedToMarshalPayload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, botURL, bytes.NewReader(body))
+	if err != nil {
+		return CallbackResult{}, errors.New(constants.ErrFailedToCreateRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.opts.SigningSecret != "" {
+		req.Header.Set(eventrouter.SignatureHeader, eventrouter.Sign(body, e.opts.SigningSecret))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return CallbackResult{}, errors.New(constants.ErrFailedToExecuteRequest + ": " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CallbackResult{}, errors.New(constants.ErrFailedToDecodeResponse)
+	}
+	result := CallbackResult{Status: resp.StatusCode}
+	if json.Valid(respBody) {
+		result.Body = respBody
+	} else {
+		result.Body, _ = json.Marshal(string(respBody))
+	}
+	return result, nil
+}
+
+// handleFireCallback fires a callback described by the request body at the bot
+func (e *Emulator) handleFireCallback(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		BotURL    string          `json:"bot_url"`
+		EventType string          `json:"event_type"`
+		Event     json.RawMessage `json:"event"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.EventType == "" {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	if req.BotURL == "" {
+		req.BotURL = e.opts.BotURL
+	}
+	if req.BotURL == "" {
+		http.Error(w, "bot_url is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Event) == 0 {
+		req.Event = json.RawMessage("{}")
+	}
+
+	result, err := e.FireCallback(r.Context(), req.BotURL, req.EventType, req.Event)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,67 @@
+package seatalkemu
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// employeeStatusActive is the employee status of current employees
+const employeeStatusActive = 2
+
+// handleProfile returns the profiles of the requested employee codes
+func (e *Emulator) handleProfile(w http.ResponseWriter, r *http.Request) {
+	codes := strings.Split(r.URL.Query().Get("employee_code"), ",")
+	if r.URL.Query().Get("employee_code") == "" {
+		writeJSON(w, http.StatusOK, response.GetEmployeeProfilesResponse{Code: CodeInvalidRequest})
+		return
+	}
+
+	code, status, ok := e.admit(r)
+	if !ok {
+		writeJSON(w, status, response.GetEmployeeProfilesResponse{Code: code})
+		return
+	}
+
+	employees := []response.EmployeeProfile{}
+	for _, c := range codes {
+		for _, profile := range e.opts.Employees {
+			if profile.EmployeeCode == strings.TrimSpace(c) {
+				employees = append(employees, profile)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, response.GetEmployeeProfilesResponse{Code: CodeOK, Employees: employees})
+}
+
+// handleEmployeeCode maps the requested emails to employee codes
+func (e *Emulator) handleEmployeeCode(w http.ResponseWriter, r *http.Request) {
+	var req request.GetEmployeeCodesByEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Emails) == 0 {
+		writeJSON(w, http.StatusOK, response.GetEmployeeCodesByEmailResponse{Code: CodeInvalidRequest})
+		return
+	}
+
+	code, status, ok := e.admit(r)
+	if !ok {
+		writeJSON(w, status, response.GetEmployeeCodesByEmailResponse{Code: code})
+		return
+	}
+
+	results := []response.EmployeeCodeResult{}
+	for _, email := range req.Emails {
+		for _, profile := range e.opts.Employees {
+			if strings.EqualFold(profile.Email, email) {
+				results = append(results, response.EmployeeCodeResult{
+					Email:          email,
+					EmployeeCode:   profile.EmployeeCode,
+					EmployeeStatus: employeeStatusActive,
+				})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, response.GetEmployeeCodesByEmailResponse{Code: CodeOK, Employees: results})
+}
@@ -0,0 +1,244 @@
+package seatalkemu
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+)
+
+// Paths of the emulated SeaTalk Open Platform endpoints
+const (
+	AuthPath         = "/auth/app_access_token"
+	SingleChatPath   = "/messaging/v2/single_chat"
+	GroupChatPath    = "/messaging/v2/group_chat"
+	ProfilePath      = "/contacts/v2/profile"
+	EmployeeCodePath = "/contacts/v2/get_employee_code_with_email"
+)
+
+// SeaTalk response codes returned by the emulator
+const (
+	CodeOK                 = 0
+	CodeInvalidCredentials = 2
+	CodeInvalidToken       = 100
+	CodeInvalidRequest     = 1000
+)
+
+// Options configures the emulated SeaTalk app
+type Options struct {
+	AppID         string
+	AppSecret     string
+	SigningSecret string
+	// BotURL is the event callback URL callbacks are fired at
+	BotURL string
+	// TokenTTL is the lifetime of issued access tokens, two hours by default
+	TokenTTL time.Duration
+	// AllowAnonymous accepts send requests without a valid access token
+	AllowAnonymous bool
+	// Employees are the profiles served by the contacts API
+	Employees []response.EmployeeProfile
+}
+
+// failure is a canned error returned by the next send request
+type failure struct {
+	status int
+	code   int
+}
+
+// Emulator is an in-memory fake of the SeaTalk Open Platform API.
+// It records every message it receives so tests can assert on them.
+type Emulator struct {
+	opts     Options
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	messages []Message
+	seq      int
+	failures []failure
+}
+
+// New creates an Emulator
+func New(opts Options) *Emulator {
+	if opts.TokenTTL == 0 {
+		opts.TokenTTL = 2 * time.Hour
+	}
+	return &Emulator{opts: opts, tokens: make(map[string]time.Time)}
+}
+
+// Handler returns the HTTP handler serving the SeaTalk API and the emulator control API
+func (e *Emulator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+AuthPath, e.handleAuth)
+	mux.HandleFunc("POST "+SingleChatPath, e.handleSingleChat)
+	mux.HandleFunc("POST "+GroupChatPath, e.handleGroupChat)
+	mux.HandleFunc("GET "+ProfilePath, e.handleProfile)
+	mux.HandleFunc("POST "+EmployeeCodePath, e.handleEmployeeCode)
+	mux.HandleFunc("GET /emulator/messages", e.handleListMessages)
+	mux.HandleFunc("DELETE /emulator/messages", e.handleResetMessages)
+	mux.HandleFunc("POST /emulator/callbacks", e.handleFireCallback)
+	return mux
+}
+
+// Messages returns a copy of every message received so far
+func (e *Emulator) Messages() []Message {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Message(nil), e.messages...)
+}
+
+// Reset forgets the received messages and pending failures
+func (e *Emulator) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.messages = nil
+	e.failures = nil
+}
+
+// FailNext makes the next send request fail with the given HTTP status and SeaTalk code
+func (e *Emulator) FailNext(status, code int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, failure{status: status, code: code})
+}
+
+// handleAuth issues an app access token for valid app credentials
+func (e *Emulator) handleAuth(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AppID     string `json:"app_id"`
+		AppSecret string `json:"app_secret"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusOK, response.TokenResponse{Code: CodeInvalidRequest})
+		return
+	}
+	if req.AppID != e.opts.AppID || req.AppSecret != e.opts.AppSecret {
+		writeJSON(w, http.StatusOK, response.TokenResponse{Code: CodeInvalidCredentials})
+		return
+	}
+
+	token := randomHex(16)
+	expire := time.Now().Add(e.opts.TokenTTL)
+
+	e.mu.Lock()
+	e.tokens[token] = expire
+	e.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, response.TokenResponse{
+		Code:           CodeOK,
+		AppAccessToken: token,
+		Expire:         expire.Unix(),
+	})
+}
+
+// handleSingleChat records a message sent to a subscriber
+func (e *Emulator) handleSingleChat(w http.ResponseWriter, r *http.Request) {
+	var req request.SendMessageToBotSubscriberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.EmployeeCode == "" {
+		writeJSON(w, http.StatusOK, response.SendMessageToBotSubscriberResponse{Code: CodeInvalidRequest})
+		return
+	}
+
+	code, status, ok := e.admit(r)
+	if !ok {
+		writeJSON(w, status, response.SendMessageToBotSubscriberResponse{Code: code})
+		return
+	}
+
+	e.record(r, Message{
+		Kind:         KindSingleChat,
+		EmployeeCode: req.EmployeeCode,
+		Tag:          req.Message.Tag,
+		Format:       req.Message.Text.Format,
+		Content:      req.Message.Text.Content,
+	})
+	writeJSON(w, http.StatusOK, response.SendMessageToBotSubscriberResponse{Code: CodeOK})
+}
+
+// handleGroupChat records a message sent to a group
+func (e *Emulator) handleGroupChat(w http.ResponseWriter, r *http.Request) {
+	var req request.SendMessageToBotGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.GroupID == "" {
+		writeJSON(w, http.StatusOK, response.SendMessageToBotGroupResponse{Code: CodeInvalidRequest})
+		return
+	}
+
+	code, status, ok := e.admit(r)
+	if !ok {
+		writeJSON(w, status, response.SendMessageToBotGroupResponse{Code: code})
+		return
+	}
+
+	msg := e.record(r, Message{
+		Kind:            KindGroupChat,
+		GroupID:         req.GroupID,
+		ThreadID:        req.Message.ThreadID,
+		QuotedMessageID: req.Message.QuotedMessageID,
+		Tag:             req.Message.Tag,
+		Format:          req.Message.Text.Format,
+		Content:         req.Message.Text.Content,
+	})
+	writeJSON(w, http.StatusOK, response.SendMessageToBotGroupResponse{Code: CodeOK, MessegeId: msg.MessageID})
+}
+
+// admit checks pending failures and the access token of a send request
+func (e *Emulator) admit(r *http.Request) (code, status int, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.failures) > 0 {
+		f := e.failures[0]
+		e.failures = e.failures[1:]
+		return f.code, f.status, false
+	}
+	if e.opts.AllowAnonymous {
+		return CodeOK, http.StatusOK, true
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	expire, issued := e.tokens[token]
+	if !found || !issued || time.Now().After(expire) {
+		return CodeInvalidToken, http.StatusOK, false
+	}
+	return CodeOK, http.StatusOK, true
+}
+
+// record stores a received message with its request details
+func (e *Emulator) record(r *http.Request, msg Message) Message {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.seq++
+	msg.MessageID = "emu-" + strconv.Itoa(e.seq)
+	msg.Authorization = r.Header.Get("Authorization")
+	msg.Mentions = ParseMentions(msg.Content)
+	msg.ReceivedAt = time.Now()
+	e.messages = append(e.messages, msg)
+	return msg
+}
+
+func (e *Emulator) handleListMessages(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, e.Messages())
+}
+
+func (e *Emulator) handleResetMessages(w http.ResponseWriter, r *http.Request) {
+	e.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
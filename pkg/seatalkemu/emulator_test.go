@@ -0,0 +1,221 @@
+package seatalkemu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"seatalk-bot/models/request"
+	"seatalk-bot/models/response"
+	"seatalk-bot/pkg/eventrouter"
+)
+
+// post sends body as JSON to the emulator and decodes the reply into out
+func post(t *testing.T, url, token string, body, out interface{}) int {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// token requests an access token with the given credentials
+func token(t *testing.T, s *Server, appID, appSecret string) response.TokenResponse {
+	t.Helper()
+	var resp response.TokenResponse
+	post(t, s.URL+AuthPath, "", map[string]string{"app_id": appID, "app_secret": appSecret}, &resp)
+	return resp
+}
+
+func groupMessage(groupID, threadID, content string) request.SendMessageToBotGroupRequest {
+	return request.SendMessageToBotGroupRequest{
+		GroupID: groupID,
+		Message: request.MessageGroup{
+			Tag:      "Text",
+			Text:     request.TextGroup{Format: 1, Content: content},
+			ThreadID: threadID,
+		},
+	}
+}
+
+func TestAuth(t *testing.T) {
+	s := NewServer(Options{AppID: "app", AppSecret: "secret"})
+	defer s.Close()
+
+	tests := []struct {
+		name      string
+		appID     string
+		appSecret string
+		code      int
+	}{
+		{"valid credentials", "app", "secret", CodeOK},
+		{"wrong secret", "app", "other", CodeInvalidCredentials},
+		{"unknown app", "other", "secret", CodeInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := token(t, s, tt.appID, tt.appSecret)
+			if resp.Code != tt.code {
+				t.Fatalf("code = %d, want %d", resp.Code, tt.code)
+			}
+			if (resp.AppAccessToken != "") != (tt.code == CodeOK) {
+				t.Errorf("token = %q", resp.AppAccessToken)
+			}
+			if tt.code == CodeOK && time.Until(time.Unix(resp.Expire, 0)) < time.Hour {
+				t.Errorf("token expires at %v, want two hours from now", time.Unix(resp.Expire, 0))
+			}
+		})
+	}
+}
+
+func TestSendRecordsMessage(t *testing.T) {
+	s := NewServer(Options{AppID: "app", AppSecret: "secret"})
+	defer s.Close()
+	tok := token(t, s, "app", "secret").AppAccessToken
+
+	var groupResp response.SendMessageToBotGroupResponse
+	post(t, s.URL+GroupChatPath, tok, groupMessage("g1", "t1", `hi <mention-tag target="seatalk://user?email=jane@example.com"/>`), &groupResp)
+	var singleResp response.SendMessageToBotSubscriberResponse
+	post(t, s.URL+SingleChatPath, tok, request.SendMessageToBotSubscriberRequest{
+		EmployeeCode: "E001",
+		Message:      request.MessageSingle{Tag: "Text", Text: request.TextSingle{Format: 1, Content: "hello"}},
+	}, &singleResp)
+
+	if groupResp.Code != CodeOK || groupResp.MessegeId == "" || singleResp.Code != CodeOK {
+		t.Fatalf("responses = %+v, %+v", groupResp, singleResp)
+	}
+
+	messages := s.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(messages))
+	}
+	group, single := messages[0], messages[1]
+	if group.Kind != KindGroupChat || group.GroupID != "g1" || group.ThreadID != "t1" || group.MessageID != groupResp.MessegeId {
+		t.Errorf("group message = %+v", group)
+	}
+	if !reflect.DeepEqual(group.Mentions, []string{"email=jane@example.com"}) {
+		t.Errorf("mentions = %q", group.Mentions)
+	}
+	if single.Kind != KindSingleChat || single.EmployeeCode != "E001" || single.Content != "hello" {
+		t.Errorf("single message = %+v", single)
+	}
+	for _, msg := range messages {
+		if msg.Authorization != "Bearer "+tok {
+			t.Errorf("recorded Authorization = %q, want the bearer token", msg.Authorization)
+		}
+	}
+
+	s.Reset()
+	if got := len(s.Messages()); got != 0 {
+		t.Errorf("got %d messages after Reset", got)
+	}
+}
+
+func TestSendRejectsBadTokens(t *testing.T) {
+	s := NewServer(Options{AppID: "app", AppSecret: "secret", TokenTTL: time.Millisecond})
+	defer s.Close()
+	expired := token(t, s, "app", "secret").AppAccessToken
+	time.Sleep(5 * time.Millisecond)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"no token", ""},
+		{"unknown token", "forged"},
+		{"expired token", expired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp response.SendMessageToBotGroupResponse
+			post(t, s.URL+GroupChatPath, tt.token, groupMessage("g1", "", "hello"), &resp)
+			if resp.Code != CodeInvalidToken {
+				t.Errorf("code = %d, want %d", resp.Code, CodeInvalidToken)
+			}
+		})
+	}
+	if got := len(s.Messages()); got != 0 {
+		t.Errorf("recorded %d rejected messages", got)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	s := NewServer(Options{AllowAnonymous: true})
+	defer s.Close()
+	s.FailNext(http.StatusTooManyRequests, CodeInvalidRequest)
+
+	var resp response.SendMessageToBotGroupResponse
+	if status := post(t, s.URL+GroupChatPath, "", groupMessage("g1", "", "first"), &resp); status != http.StatusTooManyRequests || resp.Code != CodeInvalidRequest {
+		t.Errorf("first send = %d code %d, want the canned failure", status, resp.Code)
+	}
+	if status := post(t, s.URL+GroupChatPath, "", groupMessage("g1", "", "second"), &resp); status != http.StatusOK || resp.Code != CodeOK {
+		t.Errorf("second send = %d code %d, want success", status, resp.Code)
+	}
+	if messages := s.Messages(); len(messages) != 1 || messages[0].Content != "second" {
+		t.Errorf("messages = %+v, want only the second", messages)
+	}
+}
+
+func TestFireCallbackSignsTheBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		botSecret string
+		status    int
+	}{
+		{"matching secret", "signing", http.StatusOK},
+		{"bot expects another secret", "other", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var envelope request.EventCallbackEnvelope
+			bot := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if !eventrouter.ValidSignature(body, tt.botSecret, r.Header.Get(eventrouter.SignatureHeader)) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				json.Unmarshal(body, &envelope)
+				w.Write([]byte(`{"ok":true}`))
+			}))
+			defer bot.Close()
+
+			emu := New(Options{AppID: "app", SigningSecret: "signing"})
+			result, err := emu.FireCallback(context.Background(), bot.URL, request.EventTypeNewMentionedMessageFromGroupChat, map[string]string{"group_id": "g1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != tt.status {
+				t.Fatalf("status = %d, want %d", result.Status, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if string(result.Body) != `{"ok":true}` {
+				t.Errorf("body = %s", result.Body)
+			}
+			if envelope.EventType != request.EventTypeNewMentionedMessageFromGroupChat || envelope.AppID != "app" || string(envelope.Event) != `{"group_id":"g1"}` {
+				t.Errorf("envelope = %+v", envelope)
+			}
+		})
+	}
+}
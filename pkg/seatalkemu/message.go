@@ -0,0 +1,41 @@
+package seatalkemu
+
+import (
+	"regexp"
+	"time"
+)
+
+// Message kinds recorded by the emulator
+const (
+	KindSingleChat = "single_chat"
+	KindGroupChat  = "group_chat"
+)
+
+// Message is a message received by the emulator
+type Message struct {
+	MessageID       string    `json:"message_id"`
+	Kind            string    `json:"kind"`
+	GroupID         string    `json:"group_id,omitempty"`
+	EmployeeCode    string    `json:"employee_code,omitempty"`
+	ThreadID        string    `json:"thread_id,omitempty"`
+	QuotedMessageID string    `json:"quoted_message_id,omitempty"`
+	Tag             string    `json:"tag"`
+	Format          int       `json:"format"`
+	Content         string    `json:"content"`
+	Mentions        []string  `json:"mentions,omitempty"`
+	Authorization   string    `json:"authorization"`
+	ReceivedAt      time.Time `json:"received_at"`
+}
+
+// mentionTagPattern matches <mention-tag target="seatalk://user?..."/> tags
+var mentionTagPattern = regexp.MustCompile(`<mention-tag target="seatalk://user\?([^"]*)"\s*/?>`)
+
+// ParseMentions returns the target query of every mention tag in content,
+// for example "email=jane@example.com"
+func ParseMentions(content string) []string {
+	var mentions []string
+	for _, match := range mentionTagPattern.FindAllStringSubmatch(content, -1) {
+		mentions = append(mentions, match[1])
+	}
+	return mentions
+}
@@ -0,0 +1,47 @@
+package seatalkemu
+
+import (
+	"net/http/httptest"
+
+	"seatalk-bot/internal/config"
+)
+
+// Server is an Emulator listening on a local port, for use in Go tests
+type Server struct {
+	*Emulator
+	URL string
+	ts  *httptest.Server
+}
+
+// NewServer starts an Emulator on a random local port
+func NewServer(opts Options) *Server {
+	e := New(opts)
+	ts := httptest.NewServer(e.Handler())
+	return &Server{Emulator: e, URL: ts.URL, ts: ts}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// Config returns a bot configuration pointing at the emulator
+func (s *Server) Config() *config.Config {
+	return &config.Config{
+		AppID:             s.opts.AppID,
+		AppSecret:         s.opts.AppSecret,
+		APIURL:            s.URL,
+		AuthURL:           s.URL + AuthPath,
+		SingleChatUrl:     s.URL + SingleChatPath,
+		GroupChatUrl:      s.URL + GroupChatPath,
+		ProfileUrl:        s.URL + ProfilePath,
+		EmployeeCodeUrl:   s.URL + EmployeeCodePath,
+		DirectoryTTL:      config.DefaultDirectoryTTL,
+		AnswerMaxTokens:   config.DefaultAnswerMaxTokens,
+		AnswerMaxChars:    config.DefaultAnswerMaxChars,
+		AnswerHistory:     config.DefaultAnswerHistory,
+		AnswerTimeout:     config.DefaultAnswerTimeout,
+		SigningSecret:     s.opts.SigningSecret,
+		RegressionGroupID: "emulator-group",
+	}
+}
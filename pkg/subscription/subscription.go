@@ -0,0 +1,138 @@
+package subscription
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/filestore"
+)
+
+// Notification topics users can subscribe to
+const (
+	// TopicPIC notifies users ahead of the weeks they are PIC
+	TopicPIC = "pic"
+	// TopicReminders forwards the scheduled reminders sent to the group
+	TopicReminders = "reminders"
+)
+
+// Topics lists every topic users can subscribe to
+var Topics = []string{TopicPIC, TopicReminders}
+
+// Subscription holds the notifications an employee receives in direct messages
+type Subscription struct {
+	Topics []string `json:"topics"`
+	// PICNoticeDays is how many days before a PIC date the employee is notified,
+	// zero meaning the configured default
+	PICNoticeDays int `json:"pic_notice_days,omitempty"`
+}
+
+// Has reports whether the subscription includes the topic
+func (s Subscription) Has(topic string) bool {
+	for _, t := range s.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Store persists the subscriptions of every employee
+type Store struct {
+	path string
+	mu   sync.RWMutex
+	subs map[string]Subscription
+}
+
+// Load loads the subscriptions persisted at path
+func Load(path string) (*Store, error) {
+	subs := make(map[string]Subscription)
+	if err := filestore.LoadJSON(path, &subs); err != nil {
+		return nil, err
+	}
+	if subs == nil {
+		subs = make(map[string]Subscription)
+	}
+	return &Store{path: path, subs: subs}, nil
+}
+
+// ValidTopic reports whether topic can be subscribed to
+func ValidTopic(topic string) bool {
+	for _, t := range Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the subscription of an employee
+func (s *Store) Get(employeeCode string) Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.subs[employeeCode]
+}
+
+// Subscribe adds a topic to the subscription of an employee. For TopicPIC a
+// positive noticeDays overrides the default notice period.
+func (s *Store) Subscribe(employeeCode, topic string, noticeDays int) error {
+	if !ValidTopic(topic) {
+		return errors.New(constants.ErrUnknownTopic + ": " + topic)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := s.subs[employeeCode]
+	if !sub.Has(topic) {
+		sub.Topics = append(sub.Topics, topic)
+		sort.Strings(sub.Topics)
+	}
+	if topic == TopicPIC && noticeDays > 0 {
+		sub.PICNoticeDays = noticeDays
+	}
+	s.subs[employeeCode] = sub
+	return filestore.SaveJSON(s.path, s.subs)
+}
+
+// Unsubscribe removes a topic from the subscription of an employee, or every
+// topic when topic is empty
+func (s *Store) Unsubscribe(employeeCode, topic string) error {
+	if topic != "" && !ValidTopic(topic) {
+		return errors.New(constants.ErrUnknownTopic + ": " + topic)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := s.subs[employeeCode]
+	var topics []string
+	for _, t := range sub.Topics {
+		if topic != "" && t != topic {
+			topics = append(topics, t)
+		}
+	}
+	if len(topics) == 0 {
+		delete(s.subs, employeeCode)
+	} else {
+		sub.Topics = topics
+		s.subs[employeeCode] = sub
+	}
+	return filestore.SaveJSON(s.path, s.subs)
+}
+
+// Subscribers returns the employee codes subscribed to a topic, sorted
+func (s *Store) Subscribers(topic string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var codes []string
+	for code, sub := range s.subs {
+		if sub.Has(topic) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
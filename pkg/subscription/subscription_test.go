@@ -0,0 +1,72 @@
+package subscription
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"seatalk-bot/internal/constants"
+)
+
+func TestSubscriptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name  string
+		apply func() error
+		// want holds the subscribers of each topic after the step
+		pic       []string
+		reminders []string
+		err       string
+	}{
+		{"subscribe", func() error { return s.Subscribe("E002", TopicPIC, 0) }, []string{"E002"}, nil, ""},
+		{"subscribe another employee", func() error { return s.Subscribe("E001", TopicPIC, 3) }, []string{"E001", "E002"}, nil, ""},
+		{"second topic", func() error { return s.Subscribe("E001", TopicReminders, 0) }, []string{"E001", "E002"}, []string{"E001"}, ""},
+		{"subscribing twice", func() error { return s.Subscribe("E001", TopicReminders, 0) }, []string{"E001", "E002"}, []string{"E001"}, ""},
+		{"unknown topic", func() error { return s.Subscribe("E001", "lunch", 0) }, []string{"E001", "E002"}, []string{"E001"}, constants.ErrUnknownTopic},
+		{"unsubscribe one topic", func() error { return s.Unsubscribe("E001", TopicPIC) }, []string{"E002"}, []string{"E001"}, ""},
+		{"unsubscribe everything", func() error { return s.Unsubscribe("E002", "") }, nil, []string{"E001"}, ""},
+		{"unsubscribe unknown topic", func() error { return s.Unsubscribe("E001", "lunch") }, nil, []string{"E001"}, constants.ErrUnknownTopic},
+	}
+	for _, step := range steps {
+		err := step.apply()
+		if (err == nil) != (step.err == "") || (err != nil && !strings.HasPrefix(err.Error(), step.err)) {
+			t.Fatalf("%s: error = %v, want %q", step.name, err, step.err)
+		}
+		if got := s.Subscribers(TopicPIC); !reflect.DeepEqual(got, step.pic) {
+			t.Errorf("%s: PIC subscribers = %q, want %q", step.name, got, step.pic)
+		}
+		if got := s.Subscribers(TopicReminders); !reflect.DeepEqual(got, step.reminders) {
+			t.Errorf("%s: reminder subscribers = %q, want %q", step.name, got, step.reminders)
+		}
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Get("E001"); !reflect.DeepEqual(got, Subscription{Topics: []string{TopicReminders}, PICNoticeDays: 3}) {
+		t.Errorf("reloaded subscription = %+v", got)
+	}
+	if got := reloaded.Get("E002"); got.Has(TopicPIC) || got.Has(TopicReminders) {
+		t.Errorf("unsubscribed employee kept %+v", got)
+	}
+}
+
+func TestNoticeDays(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "subscriptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Subscribe("E001", TopicPIC, 5)
+	s.Subscribe("E001", TopicPIC, 0)
+	s.Subscribe("E001", TopicReminders, 9)
+	if got := s.Get("E001").PICNoticeDays; got != 5 {
+		t.Errorf("notice days = %d, want 5 kept when not given again", got)
+	}
+}
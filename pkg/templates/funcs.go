@@ -0,0 +1,41 @@
+package templates
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/mention"
+)
+
+// Funcs returns the helper functions available to the templates of a locale
+func Funcs(locale string) template.FuncMap {
+	return template.FuncMap{
+		// date formats a time in the locale, e.g. {{date "2 January 2006" .Date}}
+		"date": func(layout string, t time.Time) string {
+			return i18n.FormatDate(locale, layout, t)
+		},
+		"mentionEmail": mention.Email,
+		"mentionCode":  mention.EmployeeCode,
+		"mentionID":    mention.SeatalkID,
+		"join":         join,
+		"add":          func(a, b int) int { return a + b },
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"default":      defaultValue,
+	}
+}
+
+// join concatenates the items with sep, e.g. {{join ", " .Names}}
+func join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// defaultValue returns fallback when value is empty, e.g. {{default "n/a" .Email}}
+func defaultValue(fallback, value string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
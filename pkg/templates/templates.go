@@ -0,0 +1,311 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/directory"
+	"seatalk-bot/pkg/i18n"
+	"seatalk-bot/pkg/schedule"
+)
+
+// Template names
+const (
+	PICAnnouncement      = "pic_announcement"
+	ReturnRefundReminder = "return_refund_reminder"
+	DefaultReply         = "default_reply"
+	PICNotice            = "pic_notice"
+	ScheduleRestored     = "schedule_restored"
+	ScheduleIssues       = "schedule_issues"
+	ScheduleRunway       = "schedule_runway"
+)
+
+// templateExt is the extension of template files
+const templateExt = ".tmpl"
+
+//go:embed defaults
+var defaults embed.FS
+
+// PICAnnouncementData is the data of the weekly PIC announcement
+type PICAnnouncementData struct {
+	Rotation  string
+	WeekStart time.Time
+	WeekEnd   time.Time
+	// Current holds the PICs on duty this week
+	Current []schedule.Schedule
+	// Schedule holds the full rotation after this week's PICs were rotated
+	Schedule []schedule.Schedule
+}
+
+// PICNoticeData is the data of the direct message telling a subscriber they
+// are PIC soon
+type PICNoticeData struct {
+	Rotation string
+	Date     time.Time
+	// Days is how many days are left until Date
+	Days int
+	// Recipient holds what the directory knows about the subscriber
+	Recipient directory.Entry
+}
+
+// ScheduleRestoredData is the data of the announcement that a rotation was
+// rolled back to an earlier version
+type ScheduleRestoredData struct {
+	Rotation string
+	// Version is the version the restoration created
+	Version int
+	// RestoredFrom is the version that was brought back
+	RestoredFrom int
+	// By is the name of whoever restored the rotation
+	By       string
+	Schedule []schedule.Schedule
+}
+
+// ScheduleIssuesData is the data of the direct message telling admins about
+// the problems found in a schedule file
+type ScheduleIssuesData struct {
+	Rotation string
+	File     string
+	// Errors is how many of the issues are errors
+	Errors int
+	Issues []schedule.Issue
+}
+
+// ScheduleRunwayData is the data of the warning that a rotation has no PIC
+// this week or is about to run out
+type ScheduleRunwayData struct {
+	Rotation string
+	// Current is the number of PICs scheduled this week
+	Current int
+	// Periods is the number of turns scheduled after this week
+	Periods int
+	// Required is the number of turns that should be scheduled after this week
+	Required int
+	// Last is the date of the latest entry, zero when there is none
+	Last time.Time
+	// Configured tells whether the rotation engine already plans the rotation
+	Configured bool
+}
+
+// ReminderData is the data of scheduled reminders
+type ReminderData struct {
+	Date time.Time
+	// MentionAll is the @all mention tag, empty where the bot may not notify everyone
+	MentionAll string
+}
+
+// ReplyData is the data of replies to messages sent to the bot
+type ReplyData struct {
+	Text string
+	// Sender holds what the directory knows about the author of the message
+	Sender directory.Entry
+}
+
+// samples holds example data each template is validated against
+var samples = map[string]interface{}{
+	PICAnnouncement: PICAnnouncementData{
+		Rotation:  constants.StockInventoryRotation,
+		WeekStart: time.Date(2024, 9, 17, 0, 0, 0, 0, time.UTC),
+		WeekEnd:   time.Date(2024, 9, 23, 23, 59, 59, 0, time.UTC),
+		Current: []schedule.Schedule{
+			{PIC: "Jane Doe", Date: time.Date(2024, 9, 18, 0, 0, 0, 0, time.UTC), Email: "jane.doe@example.com"},
+		},
+		Schedule: []schedule.Schedule{
+			{PIC: "Jane Doe", Date: time.Date(2024, 9, 18, 0, 0, 0, 0, time.UTC), Email: "jane.doe@example.com"},
+			{PIC: "John Roe", Date: time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC), Email: "john.roe@example.com"},
+		},
+	},
+	PICNotice: PICNoticeData{
+		Rotation:  constants.StockInventoryRotation,
+		Date:      time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC),
+		Days:      7,
+		Recipient: directory.Entry{EmployeeCode: "E001", SeatalkID: "12345", Name: "Jane Doe", Email: "jane.doe@example.com"},
+	},
+	ScheduleRestored: ScheduleRestoredData{
+		Rotation:     constants.StockInventoryRotation,
+		Version:      8,
+		RestoredFrom: 6,
+		By:           "Jane Doe",
+		Schedule: []schedule.Schedule{
+			{PIC: "Jane Doe", Date: time.Date(2024, 9, 18, 0, 0, 0, 0, time.UTC), Email: "jane.doe@example.com"},
+		},
+	},
+	ScheduleIssues: ScheduleIssuesData{
+		Rotation: constants.StockInventoryRotation,
+		File:     constants.StockInventoryScheduleFile,
+		Errors:   1,
+		Issues: []schedule.Issue{
+			{Line: 3, Severity: schedule.SeverityError, Code: schedule.CodeDate, Field: schedule.FieldDate, Value: "32-Sep-2024", Message: "date is not in the 2-Jan-2006 format"},
+			{Severity: schedule.SeverityWarning, Code: schedule.CodeRunway, Message: "schedule runs out on 25-Sep-2024"},
+		},
+	},
+	ScheduleRunway: ScheduleRunwayData{
+		Rotation: constants.StockInventoryRotation,
+		Current:  1,
+		Periods:  1,
+		Required: 2,
+		Last:     time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC),
+	},
+	ReturnRefundReminder: ReminderData{Date: time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC), MentionAll: `<mention-tag target="seatalk://user?id=0"/>`},
+	DefaultReply: ReplyData{
+		Text:   "hello",
+		Sender: directory.Entry{EmployeeCode: "E001", SeatalkID: "12345", Name: "Jane Doe", Email: "jane.doe@example.com"},
+	},
+}
+
+// Renderer renders the bot messages from text/template files, per locale
+type Renderer struct {
+	templates map[string]map[string]*template.Template
+}
+
+// Default returns a Renderer using the built-in templates
+func Default() *Renderer {
+	r, err := Load("")
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Load parses the built-in templates of every locale and validates each of
+// them against sample data. Files in dir override them: <name>.tmpl for the
+// default locale and <locale>/<name>.tmpl for a specific locale. Templates
+// missing from a locale fall back to the default locale.
+func Load(dir string) (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]map[string]*template.Template)}
+
+	base, err := readSources(dir, i18n.DefaultLocale, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range i18n.Supported() {
+		sources := base
+		if locale != i18n.DefaultLocale {
+			if sources, err = readSources(dir, locale, base); err != nil {
+				return nil, err
+			}
+		}
+
+		r.templates[locale] = make(map[string]*template.Template, len(sources))
+		for name, source := range sources {
+			tmpl, err := template.New(name).Funcs(Funcs(locale)).Option("missingkey=error").Parse(source)
+			if err != nil {
+				return nil, errors.New(constants.ErrInvalidTemplate + ": " + locale + ": " + err.Error())
+			}
+			r.templates[locale][name] = tmpl
+		}
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate renders every template with sample data to catch mistakes such as
+// unknown fields before a scheduled job needs them
+func (r *Renderer) Validate() error {
+	for _, locale := range i18n.Supported() {
+		for _, name := range r.Names() {
+			sample, ok := samples[name]
+			if !ok {
+				continue
+			}
+			if _, err := r.Render(locale, name, sample); err != nil {
+				return errors.New(locale + ": " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the loaded templates
+func (r *Renderer) Names() []string {
+	names := make([]string, 0, len(r.templates[i18n.DefaultLocale]))
+	for name := range r.templates[i18n.DefaultLocale] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render executes the named template of the locale with data
+func (r *Renderer) Render(locale, name string, data interface{}) (string, error) {
+	tmpl, ok := r.templates[locale][name]
+	if !ok {
+		tmpl, ok = r.templates[i18n.DefaultLocale][name]
+	}
+	if !ok {
+		return "", errors.New(constants.ErrTemplateNotFound + ": " + name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.New(constants.ErrFailedToRenderTemplate + ": " + err.Error())
+	}
+	return buf.String(), nil
+}
+
+// readSources returns the template sources of a locale by name, starting from
+// fallback, then the built-in templates and finally the files in dir
+func readSources(dir, locale string, fallback map[string]string) (map[string]string, error) {
+	sources := make(map[string]string, len(fallback))
+	for name, source := range fallback {
+		sources[name] = source
+	}
+	if err := collect(defaults, path.Join("defaults", locale), sources); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return sources, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, errors.New(constants.ErrorFileOpen + ": " + err.Error())
+	}
+
+	localeDir := locale
+	if locale == i18n.DefaultLocale {
+		// Files at the top of dir override the default locale
+		if err := collect(os.DirFS(dir), ".", sources); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, localeDir)); err == nil {
+		if err := collect(os.DirFS(dir), localeDir, sources); err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// collect reads every template file directly inside root of fsys
+func collect(fsys fs.FS, root string, sources map[string]string) error {
+	entries, err := fs.ReadDir(fsys, root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.New(constants.ErrorFileRead + ": " + err.Error())
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != templateExt {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return errors.New(constants.ErrorFileRead + ": " + err.Error())
+		}
+		sources[strings.TrimSuffix(entry.Name(), templateExt)] = string(data)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seatalk-bot/internal/constants"
+	"seatalk-bot/pkg/mention"
+	"seatalk-bot/pkg/schedule"
+)
+
+// writeTemplates creates a template directory holding the given files
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDefaultsRenderSamples(t *testing.T) {
+	r := Default()
+	// Templates without sample data would not be validated
+	for _, name := range r.Names() {
+		if _, ok := samples[name]; !ok {
+			t.Errorf("template %s has no sample data", name)
+		}
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	got, err := r.Render("en", PICAnnouncement, samples[PICAnnouncement])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{
+		"Date: 2024-09-18 - PIC: Jane Doe " + mention.Email("jane.doe@example.com"),
+		"Date: 2024-09-25 - PIC: John Roe",
+	} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("announcement %q does not contain %q", got, fragment)
+		}
+	}
+}
+
+func TestNoPICThisWeek(t *testing.T) {
+	got, err := Default().Render("en", PICAnnouncement, PICAnnouncementData{
+		Schedule: []schedule.Schedule{{PIC: "John Roe", Date: time.Date(2024, 9, 25, 0, 0, 0, 0, time.UTC)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "No PIC is scheduled this week.") {
+		t.Errorf("announcement = %q", got)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"default_reply.tmpl":    "You said {{upper .Text}}",
+		"notes.txt":             "{{.Ignored}}",
+		"farewell.tmpl":         "Bye {{.}}",
+		"id/default_reply.tmpl": "Kamu bilang {{.Text}}",
+	})
+	r, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		locale string
+		name   string
+		data   interface{}
+		want   string
+	}{
+		{"en", DefaultReply, ReplyData{Text: "hi"}, "You said HI"},
+		{"id", DefaultReply, ReplyData{Text: "hai"}, "Kamu bilang hai"},
+		{"en", ReturnRefundReminder, ReminderData{}, "Hello, it's time to do the return refund test. Please make sure to do it before 12 PM today. Thank you!"},
+		{"id", ReturnRefundReminder, ReminderData{Date: time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC)}, "Halo, sudah waktunya melakukan tes return refund. Pastikan untuk menyelesaikannya sebelum pukul 12 siang hari ini (Jumat, 20 September 2024). Terima kasih!"},
+		// Templates missing from a locale fall back to the default locale
+		{"id", "farewell", "Jane", "Bye Jane"},
+		{"fr", DefaultReply, ReplyData{Text: "salut"}, "You said SALUT"},
+	}
+	for _, tt := range tests {
+		got, err := r.Render(tt.locale, tt.name, tt.data)
+		if err != nil || got != tt.want {
+			t.Errorf("Render(%s, %s) = %q, %v, want %q", tt.locale, tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := r.Render("en", "notes", nil); err == nil || !strings.HasPrefix(err.Error(), constants.ErrTemplateNotFound) {
+		t.Errorf("Render(notes) error = %v, want files without the template extension ignored", err)
+	}
+}
+
+func TestLoadRejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{"syntax error", map[string]string{"default_reply.tmpl": "{{if .Text}}"}, constants.ErrInvalidTemplate},
+		{"unknown field", map[string]string{"default_reply.tmpl": "{{.Nonexistent}}"}, "en: " + constants.ErrFailedToRenderTemplate},
+		{"unknown function", map[string]string{"default_reply.tmpl": "{{shout .Text}}"}, constants.ErrInvalidTemplate},
+		{"wrong argument type", map[string]string{"return_refund_reminder.tmpl": `{{date .Date "2006"}}`}, "en: " + constants.ErrFailedToRenderTemplate},
+		{"invalid locale override", map[string]string{"id/default_reply.tmpl": "{{.Nonexistent}}"}, "id: " + constants.ErrFailedToRenderTemplate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeTemplates(t, tt.files))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("Load() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Load() of a missing directory succeeded")
+	}
+}
+
+func TestMissingMapKeyIsAnError(t *testing.T) {
+	r, err := Load(writeTemplates(t, map[string]string{"greeting.tmpl": "Hello {{.name}}"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Render("en", "greeting", map[string]string{"name": "Jane"}); err != nil || got != "Hello Jane" {
+		t.Errorf("Render() = %q, %v", got, err)
+	}
+	if got, err := r.Render("en", "greeting", map[string]string{}); err == nil {
+		t.Errorf("Render() without the key = %q, want an error", got)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	r, err := Load(writeTemplates(t, map[string]string{
+		"funcs.tmpl": `{{date "2 Jan" .Date}}|{{join ", " .Names}}|{{add 1 2}}|{{lower "AB"}}|{{default "n/a" .Email}}|{{default "n/a" "x@y.z"}}`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"Date":  time.Date(2024, 9, 18, 0, 0, 0, 0, time.UTC),
+		"Names": []string{"Jane", "John"},
+		"Email": "",
+	}
+	got, err := r.Render("en", "funcs", data)
+	if want := "18 Sep|Jane, John|3|ab|n/a|x@y.z"; err != nil || got != want {
+		t.Errorf("Render() = %q, %v, want %q", got, err, want)
+	}
+}
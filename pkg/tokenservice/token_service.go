@@ -7,13 +7,16 @@ import (
 	"net/http"
 	"seatalk-bot/internal/config"
 	"seatalk-bot/internal/constants"
+	"seatalk-bot/internal/metrics"
 	"seatalk-bot/models/response"
+	"sync"
 	"time"
 )
 
 // TokenService interacts with the Seatalk API for token management
 type TokenService struct {
 	config          *config.Config
+	mu              sync.Mutex
 	accessToken     string
 	tokenExpireTime time.Time
 }
@@ -24,7 +27,9 @@ func NewTokenService(cfg *config.Config) *TokenService {
 }
 
 // GetToken retrieves a new access token from the Seatalk API
-func (s *TokenService) GetToken() (string, error) {
+func (s *TokenService) GetToken() (token string, err error) {
+	defer func() { metrics.ObserveTokenRefresh(err) }()
+
 	url := s.config.AuthURL // Use Auth URL from config
 	payload := map[string]string{
 		"app_id":     s.config.AppID,
@@ -63,6 +68,8 @@ func (s *TokenService) GetToken() (string, error) {
 	}
 
 	// Store the access token and its expiration time
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.accessToken = tokenResponse.AppAccessToken
 	s.tokenExpireTime = time.Unix(tokenResponse.Expire, 0)
 
@@ -72,10 +79,20 @@ func (s *TokenService) GetToken() (string, error) {
 // RefreshToken refreshes the access token if it's expired
 func (s *TokenService) RefreshToken() (string, error) {
 	// Check if the token is about to expire (e.g., 5 minutes before expiration)
+	s.mu.Lock()
 	if time.Now().Add(5 * time.Minute).Before(s.tokenExpireTime) {
+		defer s.mu.Unlock()
 		return s.accessToken, nil // No need to refresh
 	}
+	s.mu.Unlock()
 
 	// Call GetToken to refresh the token
 	return s.GetToken()
 }
+
+// ExpiresAt returns the expiration time of the current access token
+func (s *TokenService) ExpiresAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenExpireTime
+}